@@ -0,0 +1,1448 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Simikao/graphOptimalisation/internal/bench"
+	"github.com/Simikao/graphOptimalisation/internal/generate"
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+	"github.com/Simikao/graphOptimalisation/internal/graphio"
+	"github.com/Simikao/graphOptimalisation/internal/render"
+	"github.com/Simikao/graphOptimalisation/internal/repl"
+	"github.com/Simikao/graphOptimalisation/internal/server"
+	"github.com/Simikao/graphOptimalisation/internal/solver"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"cover", "approximate minimum vertex cover", solverCommand("cover")},
+	{"tsp", "Christofides tour on a metric graph", solverCommand("tsp")},
+	{"cpp", "Chinese postman circuit", solverCommand("cpp")},
+	{"mst", "minimum spanning tree (Kruskal)", solverCommand("mst")},
+	{"forest", "minimum spanning forest of a possibly disconnected graph", runForest},
+	{"arborescence", "minimum spanning arborescence of a directed graph", runArborescence},
+	{"maxflow", "maximum flow and minimum cut (Dinic, Edmonds-Karp, push-relabel)", runMaxFlow},
+	{"mincostflow", "minimum-cost flow meeting vertex supplies and demands", runMinCostFlow},
+	{"transport", "transportation problem from a supply/demand/cost file", runTransport},
+	{"bipartite", "bipartition, maximum matching (Hopcroft-Karp) and König vertex cover", runBipartite},
+	{"matching", "maximum cardinality matching (Edmonds' blossom)", runMatching},
+	{"assign", "minimum-cost assignment on a cost matrix (Hungarian)", runAssign},
+	{"solve", "run any registered solver", runSolve},
+	{"solvers", "list the registered solvers", runSolvers},
+	{"path", "shortest paths (Dijkstra, Bellman-Ford, A*, resource-constrained)", runPath},
+	{"kpaths", "k shortest paths (Yen) or walks (Eppstein)", runKPaths},
+	{"apsp", "all-pairs shortest paths (Floyd-Warshall, Johnson)", runAPSP},
+	{"info", "vertex/edge counts and degree statistics", runInfo},
+	{"convert", "convert a graph between file formats", runConvert},
+	{"generate", "generate a random or structured graph", runGenerate},
+	{"bench", "compare solvers on a directory of instances", runBench},
+	{"animate", "export a step-by-step animation of an algorithm", runAnimate},
+	{"repl", "interactive shell for building and querying graphs", runREPL},
+	{"serve", "HTTP/JSON solver service", runServe},
+}
+
+var errUsage = errors.New("usage")
+
+// commonFlags are shared by every subcommand that reads a graph.
+type commonFlags struct {
+	format   string
+	directed bool
+	json     bool
+	logs     bool
+	svg      string
+	verify   bool
+	timeout  time.Duration
+	progress bool
+}
+
+func newFlagSet(name, args string) (*flag.FlagSet, *commonFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	cf := &commonFlags{}
+	fs.StringVar(&cf.format, "format", "", "input format (default: guessed from extension)")
+	fs.BoolVar(&cf.directed, "directed", false, "treat edge lists without a header as directed")
+	fs.BoolVar(&cf.json, "json", false, "print the result as JSON")
+	fs.BoolVar(&cf.logs, "logs", false, "include the algorithm steps in the output (as events with -json)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: graphopt %s [flags] %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs, cf
+}
+
+// parseArgs parses flags that may appear before, between or after the
+// positional arguments and returns the positional ones.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// parseInput parses flags and loads the graph named by the single positional
+// argument.
+func parseInput(fs *flag.FlagSet, cf *commonFlags, args []string) (g.Graph, error) {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return g.Graph{}, err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return g.Graph{}, errUsage
+	}
+	return graphio.Load(positional[0], cf.format, cf.directed)
+}
+
+// parseSparseInput is parseInput for commands that can also run on adjacency
+// lists: MatrixMarket and METIS files are read into sparse, which is nil for
+// the other formats. It lets those commands handle graphs far too large for
+// the n×n matrices of g.Graph.
+func parseSparseInput(fs *flag.FlagSet, cf *commonFlags, args []string) (g.Graph, *g.SparseGraph, error) {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return g.Graph{}, nil, err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return g.Graph{}, nil, errUsage
+	}
+	format, err := graphio.Detect(positional[0], cf.format)
+	if err != nil {
+		return g.Graph{}, nil, err
+	}
+	if graphio.IsSparse(format) {
+		sparse, err := graphio.LoadSparse(positional[0], format)
+		return g.Graph{}, sparse, err
+	}
+	graph, err := graphio.Load(positional[0], format, cf.directed)
+	return graph, nil, err
+}
+
+// result is what every algorithm subcommand prints.
+type result struct {
+	Command  string    `json:"command"`
+	Solution any       `json:"solution"`
+	Cost     *float64  `json:"cost,omitempty"`
+	Bound    *float64  `json:"bound,omitempty"`
+	Logs     string    `json:"logs,omitempty"`
+	Trace    []g.Event `json:"trace,omitempty"`
+}
+
+func printResult(w io.Writer, res result, cf *commonFlags) error {
+	if !cf.json && res.Trace != nil {
+		res.Logs = (&g.Recorder{Events: res.Trace}).String()
+		res.Trace = nil
+	}
+	if cf.json {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+
+	fmt.Fprintf(w, "%s: %v\n", res.Command, res.Solution)
+	if res.Cost != nil {
+		fmt.Fprintf(w, "cost: %g\n", *res.Cost)
+	}
+	if res.Bound != nil && *res.Bound != *res.Cost {
+		fmt.Fprintf(w, "lower bound: %g\n", *res.Bound)
+	}
+	if res.Logs != "" {
+		fmt.Fprintf(w, "\n%s", res.Logs)
+	}
+	return nil
+}
+
+func addSVGFlag(fs *flag.FlagSet, cf *commonFlags) {
+	fs.StringVar(&cf.svg, "svg", "", "also draw the graph with the solution highlighted to this SVG file")
+}
+
+func addVerifyFlag(fs *flag.FlagSet, cf *commonFlags) {
+	fs.BoolVar(&cf.verify, "verify", false, "check the solution against the graph and fail if it is invalid")
+}
+
+func addRunFlags(fs *flag.FlagSet, cf *commonFlags) {
+	fs.DurationVar(&cf.timeout, "timeout", 0, "stop the algorithm after this long and print the best result so far (0: no limit)")
+	fs.BoolVar(&cf.progress, "progress", false, "report progress of long computations on stderr")
+}
+
+// progressInterval limits how often -progress prints.
+const progressInterval = 500 * time.Millisecond
+
+// context returns the context an algorithm runs under: it is cancelled by
+// Ctrl+C or when -timeout expires.
+func (cf *commonFlags) context() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	cancel := stop
+	if cf.timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, cf.timeout)
+		cancel = func() { cancelTimeout(); stop() }
+	}
+	return ctx, cancel
+}
+
+// progressFunc prints progress on stderr with -progress; without it it is
+// nil and the algorithms report nothing.
+func (cf *commonFlags) progressFunc() g.ProgressFunc {
+	if !cf.progress {
+		return nil
+	}
+	var last time.Time
+	return func(p g.Progress) {
+		if time.Since(last) >= progressInterval {
+			last = time.Now()
+			fmt.Fprintln(os.Stderr, p)
+		}
+	}
+}
+
+// stoppedEarly reports whether err means the algorithm was interrupted or
+// ran out of time; its partial result is then still worth printing.
+func stoppedEarly(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// solutionOverlay highlights a solution according to its problem.
+func solutionOverlay(res solver.Result) render.Overlay {
+	switch s := res.Solution.(type) {
+	case []int:
+		switch res.Problem {
+		case solver.VertexCover:
+			return render.Overlay{Vertices: s}
+		case solver.TSP:
+			if len(s) > 0 {
+				return render.Overlay{Tour: append(append([]int(nil), s...), s[0])}
+			}
+		default:
+			return render.Overlay{Tour: s}
+		}
+	case [][2]int:
+		return render.Overlay{Edges: s}
+	}
+	return render.Overlay{}
+}
+
+// solverCommand returns the subcommand that runs the named solver.
+func solverCommand(name string) func(args []string) error {
+	return func(args []string) error {
+		fs, cf := newFlagSet(name, "<graph>")
+		addSolverFlags(fs, cf)
+		graph, err := parseInput(fs, cf, args)
+		if err != nil {
+			return err
+		}
+		sv, err := solver.Lookup(name)
+		if err != nil {
+			return err
+		}
+		return runSolver(sv, &graph, cf)
+	}
+}
+
+func addSolverFlags(fs *flag.FlagSet, cf *commonFlags) {
+	addSVGFlag(fs, cf)
+	addVerifyFlag(fs, cf)
+	addRunFlags(fs, cf)
+}
+
+func runSolve(args []string) error {
+	fs, cf := newFlagSet("solve", "<solver> <graph>")
+	addSolverFlags(fs, cf)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		fs.Usage()
+		return errUsage
+	}
+	sv, err := solver.Lookup(positional[0])
+	if err != nil {
+		return err
+	}
+	graph, err := graphio.Load(positional[1], cf.format, cf.directed)
+	if err != nil {
+		return err
+	}
+	return runSolver(sv, &graph, cf)
+}
+
+// runSolver runs sv and prints its result. If the run is interrupted or
+// times out, the best result found so far is printed before the error.
+func runSolver(sv solver.Solver, graph *g.Graph, cf *commonFlags) error {
+	ctx, cancel := cf.context()
+	defer cancel()
+	res, runErr := solver.Run(ctx, sv, graph, solver.Options{Trace: cf.logs, Verify: cf.verify, Progress: cf.progressFunc()})
+	if runErr != nil && (!stoppedEarly(runErr) || res.Solution == nil) {
+		return runErr
+	}
+	if err := drawSolution(graph, cf, solutionOverlay(res)); err != nil {
+		return err
+	}
+	out := result{Command: sv.Name(), Solution: res.Solution, Cost: &res.Cost, Trace: res.Trace}
+	if res.HasBound() {
+		out.Bound = &res.Bound
+	}
+	if err := printResult(os.Stdout, out, cf); err != nil {
+		return err
+	}
+	if runErr != nil {
+		return fmt.Errorf("stopped early, the result is incomplete: %w", runErr)
+	}
+	return nil
+}
+
+func drawSolution(graph *g.Graph, cf *commonFlags, overlay render.Overlay) error {
+	if cf.svg == "" {
+		return nil
+	}
+	return render.ToSVG(graph, cf.svg, render.Options{Overlay: overlay, ShowWeights: graph.Weighted})
+}
+
+func runSolvers(args []string) error {
+	fs, cf := newFlagSet("solvers", "")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	type info struct {
+		Name        string `json:"name"`
+		Problem     string `json:"problem"`
+		Description string `json:"description"`
+	}
+	var list []info
+	for _, sv := range solver.All() {
+		list = append(list, info{sv.Name(), string(sv.Problem()), sv.Description()})
+	}
+	if cf.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+	for _, i := range list {
+		fmt.Printf("%-12s %-16s %s\n", i.Name, i.Problem, i.Description)
+	}
+	return nil
+}
+
+var pathAlgorithms = []string{"dijkstra", "bellman-ford", "astar", "constrained"}
+
+// pathResult is the JSON printed by the path command; unreachable vertices
+// have no distance.
+type pathResult struct {
+	Algorithm string     `json:"algorithm"`
+	Source    int        `json:"source"`
+	Target    int        `json:"target,omitempty"`
+	Distances []*float64 `json:"distances,omitempty"`
+	Paths     [][]int    `json:"paths,omitempty"`
+	Path      []int      `json:"path,omitempty"`
+	Distance  *float64   `json:"distance,omitempty"`
+	Resource  *float64   `json:"resource,omitempty"`
+}
+
+func runPath(args []string) error {
+	fs, cf := newFlagSet("path", "<graph>")
+	addRunFlags(fs, cf)
+	var (
+		algorithm, heap, heuristic string
+		from, to                   int
+		limit                      float64
+	)
+	fs.StringVar(&algorithm, "algorithm", "dijkstra", "one of "+strings.Join(pathAlgorithms, ", "))
+	fs.IntVar(&from, "from", 1, "source vertex")
+	fs.IntVar(&to, "to", 0, "target vertex (0: paths to every vertex; required for astar)")
+	fs.StringVar(&heap, "heap", "binary", "priority queue for dijkstra: binary or pairing")
+	fs.StringVar(&heuristic, "heuristic", "euclidean", "heuristic for astar: euclidean (needs coordinates) or zero")
+	fs.Float64Var(&limit, "limit", math.Inf(1), "for constrained: maximum total resource (fourth edge-list column)")
+	graph, sparse, err := parseSparseInput(fs, cf, args)
+	if err != nil {
+		return err
+	}
+	// Na listach sąsiedztwa działają tylko dijkstra i bellman-ford
+	if sparse != nil && algorithm != "dijkstra" && algorithm != "bellman-ford" {
+		graph, sparse = sparse.ToGraph(), nil
+	}
+	n, checkVertex := len(graph.AdjMatrix), graph.CheckVertex
+	if sparse != nil {
+		n, checkVertex = sparse.N(), sparse.CheckVertex
+	}
+
+	if to != 0 {
+		if err := checkVertex(to); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := cf.context()
+	defer cancel()
+	var sp *g.ShortestPaths
+	switch algorithm {
+	case "dijkstra":
+		kind := g.BinaryHeap
+		switch heap {
+		case "binary":
+		case "pairing":
+			kind = g.PairingHeap
+		default:
+			return fmt.Errorf("unknown heap %q (binary or pairing)", heap)
+		}
+		if sparse != nil {
+			sp, err = sparse.Dijkstra(ctx, from, kind)
+		} else {
+			sp, err = graph.Dijkstra(ctx, from, kind)
+		}
+	case "bellman-ford":
+		if sparse != nil {
+			sp, err = sparse.BellmanFord(ctx, from)
+		} else {
+			sp, err = graph.BellmanFord(ctx, from)
+		}
+	case "astar":
+		if to == 0 {
+			return fmt.Errorf("astar needs a target (-to)")
+		}
+		var h g.Heuristic
+		switch heuristic {
+		case "euclidean":
+			if h, err = graph.EuclideanHeuristic(); err != nil {
+				return err
+			}
+		case "zero":
+			h = g.ZeroHeuristic
+		default:
+			return fmt.Errorf("unknown heuristic %q (euclidean or zero)", heuristic)
+		}
+		sp, err = graph.AStar(ctx, from, to, h)
+	case "constrained":
+		if to == 0 {
+			return fmt.Errorf("constrained needs a target (-to)")
+		}
+		cp, err := graph.ConstrainedShortestPath(ctx, from, to, limit, cf.progressFunc())
+		if errors.Is(err, g.ErrNoPath) {
+			fmt.Printf("no path from %d to %d within resource %g\n", from, to, limit)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		res := pathResult{Algorithm: algorithm, Source: from, Target: to, Path: cp.Vertices, Distance: &cp.Cost, Resource: &cp.Resource}
+		if cf.json {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(res)
+		}
+		fmt.Printf("path: %v\ndistance: %g\nresource: %g\n", cp.Vertices, cp.Cost, cp.Resource)
+		return nil
+	default:
+		return fmt.Errorf("unknown algorithm %q (known: %s)", algorithm, strings.Join(pathAlgorithms, ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	res := pathResult{Algorithm: algorithm, Source: from, Target: to}
+	distance := func(v int) *float64 {
+		if d := sp.Distance(v); !math.IsInf(d, 1) {
+			return &d
+		}
+		return nil
+	}
+	if to != 0 {
+		res.Path, res.Distance = sp.PathTo(to), distance(to)
+	} else {
+		for v := 1; v <= n; v++ {
+			res.Distances = append(res.Distances, distance(v))
+			res.Paths = append(res.Paths, sp.PathTo(v))
+		}
+	}
+	if cf.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+
+	if to != 0 {
+		if res.Path == nil {
+			fmt.Printf("no path from %d to %d\n", from, to)
+			return nil
+		}
+		fmt.Printf("path: %v\ndistance: %g\n", res.Path, *res.Distance)
+		return nil
+	}
+	for v, d := range res.Distances {
+		if d == nil {
+			fmt.Printf("%d: unreachable\n", v+1)
+		} else {
+			fmt.Printf("%d: %g %v\n", v+1, *d, res.Paths[v])
+		}
+	}
+	return nil
+}
+
+func runKPaths(args []string) error {
+	fs, cf := newFlagSet("kpaths", "<graph>")
+	addRunFlags(fs, cf)
+	var (
+		algorithm   string
+		from, to, k int
+	)
+	fs.StringVar(&algorithm, "algorithm", "yen", "yen (loopless paths) or eppstein (walks, may repeat vertices)")
+	fs.IntVar(&from, "from", 1, "source vertex")
+	fs.IntVar(&to, "to", 0, "target vertex")
+	fs.IntVar(&k, "k", 3, "number of paths")
+	graph, err := parseInput(fs, cf, args)
+	if err != nil {
+		return err
+	}
+	if to == 0 {
+		return fmt.Errorf("kpaths needs a target (-to)")
+	}
+	if k < 1 {
+		return fmt.Errorf("-k must be positive")
+	}
+
+	ctx, cancel := cf.context()
+	defer cancel()
+	var paths []g.Path
+	switch algorithm {
+	case "yen":
+		paths, err = graph.KShortestPaths(ctx, from, to, k, cf.progressFunc())
+	case "eppstein":
+		paths, err = graph.KShortestWalks(ctx, from, to, k, cf.progressFunc())
+	default:
+		return fmt.Errorf("unknown algorithm %q (yen or eppstein)", algorithm)
+	}
+	if errors.Is(err, g.ErrNoPath) {
+		fmt.Printf("no path from %d to %d\n", from, to)
+		return nil
+	}
+	if err != nil && !stoppedEarly(err) {
+		return err
+	}
+
+	if cf.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(paths); encErr != nil {
+			return encErr
+		}
+	} else {
+		for i, p := range paths {
+			fmt.Printf("%d: %g %v\n", i+1, p.Cost, p.Vertices)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("stopped early, the result is incomplete: %w", err)
+	}
+	return nil
+}
+
+var apspAlgorithms = map[string]g.APSPAlgorithm{
+	"auto":           g.APSPAuto,
+	"floyd-warshall": g.APSPFloydWarshall,
+	"johnson":        g.APSPJohnson,
+}
+
+// apspResult is the JSON printed by the apsp command; unreachable pairs have
+// a null distance.
+type apspResult struct {
+	Distances [][]*float64 `json:"distances"`
+	Next      [][]int      `json:"next"`
+}
+
+func runAPSP(args []string) error {
+	fs, cf := newFlagSet("apsp", "<graph>")
+	addRunFlags(fs, cf)
+	var (
+		algorithm string
+		opts      g.APSPOptions
+		from, to  int
+	)
+	fs.StringVar(&algorithm, "algorithm", "auto", "auto, floyd-warshall or johnson")
+	fs.IntVar(&opts.Workers, "workers", 0, "parallel Dijkstra runs for johnson (0: number of CPUs)")
+	fs.IntVar(&from, "from", 0, "print only the path from this vertex (with -to)")
+	fs.IntVar(&to, "to", 0, "print only the path to this vertex (with -from)")
+	graph, err := parseInput(fs, cf, args)
+	if err != nil {
+		return err
+	}
+	var ok bool
+	if opts.Algorithm, ok = apspAlgorithms[algorithm]; !ok {
+		return fmt.Errorf("unknown algorithm %q (auto, floyd-warshall or johnson)", algorithm)
+	}
+	if (from == 0) != (to == 0) {
+		return fmt.Errorf("-from and -to go together")
+	}
+	for _, v := range []int{from, to} {
+		if v != 0 {
+			if err := graph.CheckVertex(v); err != nil {
+				return err
+			}
+		}
+	}
+
+	opts.Progress = cf.progressFunc()
+	ctx, cancel := cf.context()
+	defer cancel()
+	ap, err := graph.AllPairsShortestPaths(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if from != 0 {
+		path := ap.Path(from, to)
+		if path == nil {
+			fmt.Printf("no path from %d to %d\n", from, to)
+			return nil
+		}
+		fmt.Printf("path: %v\ndistance: %g\n", path, ap.Distance(from, to))
+		return nil
+	}
+	if cf.json {
+		res := apspResult{Next: ap.Next}
+		for _, row := range ap.Dist {
+			out := make([]*float64, len(row))
+			for j := range row {
+				if !math.IsInf(row[j], 1) {
+					out[j] = &row[j]
+				}
+			}
+			res.Distances = append(res.Distances, out)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	// Ten sam układ co macierz wag: "inf" dla par bez ścieżki
+	for _, row := range ap.Dist {
+		fields := make([]string, len(row))
+		for j, d := range row {
+			fields[j] = strconv.FormatFloat(d, 'g', -1, 64)
+			if math.IsInf(d, 1) {
+				fields[j] = "inf"
+			}
+		}
+		fmt.Println(strings.Join(fields, " "))
+	}
+	return nil
+}
+
+var mstAlgorithms = map[string]g.MSTAlgorithm{
+	"kruskal": g.MSTKruskal,
+	"prim":    g.MSTPrim,
+	"boruvka": g.MSTBoruvka,
+}
+
+func runForest(args []string) error {
+	fs, cf := newFlagSet("forest", "<graph>")
+	addRunFlags(fs, cf)
+	addSVGFlag(fs, cf)
+	var (
+		algorithm, heap string
+		opts            g.MSTOptions
+	)
+	fs.StringVar(&algorithm, "algorithm", "kruskal", "kruskal, prim or boruvka")
+	fs.StringVar(&heap, "heap", "binary", "priority queue for prim: binary or pairing")
+	fs.IntVar(&opts.Workers, "workers", 0, "goroutines for boruvka (0: number of CPUs)")
+	graph, sparse, err := parseSparseInput(fs, cf, args)
+	if err != nil {
+		return err
+	}
+	var ok bool
+	if opts.Algorithm, ok = mstAlgorithms[algorithm]; !ok {
+		return fmt.Errorf("unknown algorithm %q (kruskal, prim or boruvka)", algorithm)
+	}
+	// Na listach sąsiedztwa działa tylko Kruskal; rysunek i tak potrzebuje macierzy
+	if sparse != nil && (opts.Algorithm != g.MSTKruskal || cf.svg != "") {
+		graph, sparse = sparse.ToGraph(), nil
+	}
+	switch heap {
+	case "binary":
+	case "pairing":
+		opts.Heap = g.PairingHeap
+	default:
+		return fmt.Errorf("unknown heap %q (binary or pairing)", heap)
+	}
+
+	opts.Progress = cf.progressFunc()
+	ctx, cancel := cf.context()
+	defer cancel()
+	var forest *g.SpanningForest
+	if sparse != nil {
+		forest, err = sparse.MinimumSpanningForest(ctx, opts.Progress, nil)
+	} else {
+		forest, err = graph.MinimumSpanningForest(ctx, opts, nil)
+	}
+	if err != nil && (!stoppedEarly(err) || forest == nil) {
+		return err
+	}
+	if drawErr := drawSolution(&graph, cf, render.Overlay{Edges: forest.Edges}); drawErr != nil {
+		return drawErr
+	}
+	if cf.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(forest); encErr != nil {
+			return encErr
+		}
+	} else {
+		fmt.Printf("components: %d\nweight: %g\n", forest.Components, forest.Weight)
+		// Krawędzie pogrupowane według składowych
+		for c := 1; c <= forest.Components; c++ {
+			var vertices []int
+			for v, id := range forest.Component {
+				if id == c {
+					vertices = append(vertices, v+1)
+				}
+			}
+			var edges [][2]int
+			for _, e := range forest.Edges {
+				if forest.Component[e[0]-1] == c {
+					edges = append(edges, e)
+				}
+			}
+			fmt.Printf("component %d: vertices %v, edges %v\n", c, vertices, edges)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("stopped early, the result is incomplete: %w", err)
+	}
+	return nil
+}
+
+func runArborescence(args []string) error {
+	fs, cf := newFlagSet("arborescence", "<graph>")
+	addRunFlags(fs, cf)
+	addSVGFlag(fs, cf)
+	root := fs.Int("root", 1, "root vertex")
+	graph, err := parseInput(fs, cf, args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cf.context()
+	defer cancel()
+	arb, err := graph.MinimumArborescence(ctx, *root, cf.progressFunc())
+	if err != nil {
+		return err
+	}
+	if err := drawSolution(&graph, cf, render.Overlay{Edges: arb.Edges}); err != nil {
+		return err
+	}
+	if cf.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(arb)
+	}
+	fmt.Printf("root: %d\nweight: %g\nedges: %v\n", arb.Root, arb.Weight, arb.Edges)
+	return nil
+}
+
+var flowAlgorithms = map[string]g.MaxFlowAlgorithm{
+	"dinic":        g.FlowDinic,
+	"edmonds-karp": g.FlowEdmondsKarp,
+	"push-relabel": g.FlowPushRelabel,
+}
+
+func runMaxFlow(args []string) error {
+	fs, cf := newFlagSet("maxflow", "<graph>")
+	addRunFlags(fs, cf)
+	addSVGFlag(fs, cf)
+	var (
+		algorithm string
+		from, to  int
+	)
+	fs.StringVar(&algorithm, "algorithm", "dinic", "dinic, edmonds-karp or push-relabel")
+	fs.IntVar(&from, "from", 1, "source vertex")
+	fs.IntVar(&to, "to", 0, "sink vertex (default: the last vertex)")
+	graph, err := parseInput(fs, cf, args)
+	if err != nil {
+		return err
+	}
+	alg, ok := flowAlgorithms[algorithm]
+	if !ok {
+		return fmt.Errorf("unknown algorithm %q (dinic, edmonds-karp or push-relabel)", algorithm)
+	}
+	if to == 0 {
+		to = len(graph.AdjMatrix)
+	}
+
+	ctx, cancel := cf.context()
+	defer cancel()
+	res, err := graph.MaxFlow(ctx, from, to, alg, cf.progressFunc())
+	if err != nil {
+		return err
+	}
+	if err := drawSolution(&graph, cf, render.Overlay{Edges: res.CutEdges, Vertices: res.SourceSide}); err != nil {
+		return err
+	}
+	if cf.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	fmt.Printf("max flow: %g\n", res.Value)
+	for u, row := range res.Flow {
+		for v, f := range row {
+			if f > 0 {
+				capacity := 1.0
+				if graph.Weighted {
+					capacity = graph.WeightMatrix[u][v]
+				}
+				fmt.Printf("  %d -> %d: %g / %g\n", u+1, v+1, f, capacity)
+			}
+		}
+	}
+	fmt.Printf("min cut: %v\nsource side: %v\n", res.CutEdges, res.SourceSide)
+	return nil
+}
+
+var minCostFlowAlgorithms = map[string]g.MinCostFlowAlgorithm{
+	"ssp":             g.SuccessiveShortestPaths,
+	"network-simplex": g.NetworkSimplex,
+}
+
+// parseSupplies reads "v:amount" pairs separated by commas.
+func parseSupplies(spec string, n int) ([]float64, error) {
+	supply := make([]float64, n)
+	for _, item := range strings.Split(spec, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		vs, amount, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("supply %q: expected vertex:amount", item)
+		}
+		v, err := strconv.Atoi(vs)
+		if err != nil || v < 1 || v > n {
+			return nil, fmt.Errorf("supply %q: %q is not a vertex of the graph", item, vs)
+		}
+		x, err := strconv.ParseFloat(amount, 64)
+		if err != nil {
+			return nil, fmt.Errorf("supply %q: %q is not a number", item, amount)
+		}
+		supply[v-1] += x
+	}
+	return supply, nil
+}
+
+func runMinCostFlow(args []string) error {
+	fs, cf := newFlagSet("mincostflow", "<graph>")
+	addRunFlags(fs, cf)
+	var (
+		algorithm, supplies string
+		capacities          bool
+	)
+	fs.StringVar(&algorithm, "algorithm", "network-simplex", "ssp or network-simplex")
+	fs.StringVar(&supplies, "supply", "", "supplies (positive) and demands (negative) as vertex:amount,...")
+	fs.BoolVar(&capacities, "capacities", false, "take edge capacities from the fourth edge-list column (default: unbounded)")
+	graph, err := parseInput(fs, cf, args)
+	if err != nil {
+		return err
+	}
+	var opts g.MinCostFlowOptions
+	var ok bool
+	if opts.Algorithm, ok = minCostFlowAlgorithms[algorithm]; !ok {
+		return fmt.Errorf("unknown algorithm %q (ssp or network-simplex)", algorithm)
+	}
+	supply, err := parseSupplies(supplies, len(graph.AdjMatrix))
+	if err != nil {
+		return err
+	}
+	if capacities {
+		if graph.Resources == nil {
+			return fmt.Errorf("-capacities: %w", g.ErrNoResources)
+		}
+		opts.Capacity = graph.Resources
+	}
+
+	opts.Progress = cf.progressFunc()
+	ctx, cancel := cf.context()
+	defer cancel()
+	res, err := graph.MinCostFlow(ctx, supply, opts)
+	if err != nil {
+		return err
+	}
+	if cf.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	fmt.Printf("cost: %g\n", res.Cost)
+	for u, row := range res.Flow {
+		for v, f := range row {
+			if f > 0 {
+				fmt.Printf("  %d -> %d: %g\n", u+1, v+1, f)
+			}
+		}
+	}
+	fmt.Printf("potentials: %v\n", res.Potential)
+	return nil
+}
+
+// readNumberRows reads whitespace-separated numbers, one row per non-empty
+// line, "-" standing for +Inf. Everything after '#' is a comment.
+func readNumberRows(filename string) ([][]float64, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]float64
+	for i, line := range strings.Split(string(data), "\n") {
+		if j := strings.IndexByte(line, '#'); j >= 0 {
+			line = line[:j]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		row := make([]float64, len(fields))
+		for k, f := range fields {
+			if f == "-" {
+				row[k] = math.Inf(1)
+				continue
+			}
+			if row[k], err = strconv.ParseFloat(f, 64); err != nil {
+				return nil, fmt.Errorf("%s:%d: %q is not a number", filename, i+1, f)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// readTransportation reads a transportation problem: a line of supplies, a
+// line of demands and one line of costs per source, "-" marking a missing
+// route.
+func readTransportation(filename string) (supply, demand []float64, cost [][]float64, err error) {
+	rows, err := readNumberRows(filename)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(rows) < 2 {
+		return nil, nil, nil, fmt.Errorf("%s: expected supplies, demands and costs", filename)
+	}
+	return rows[0], rows[1], rows[2:], nil
+}
+
+func runTransport(args []string) error {
+	fs, cf := newFlagSet("transport", "<problem>")
+	addRunFlags(fs, cf)
+	algorithm := fs.String("algorithm", "network-simplex", "ssp or network-simplex")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	alg, ok := minCostFlowAlgorithms[*algorithm]
+	if !ok {
+		return fmt.Errorf("unknown algorithm %q (ssp or network-simplex)", *algorithm)
+	}
+	supply, demand, cost, err := readTransportation(positional[0])
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cf.context()
+	defer cancel()
+	plan, err := g.SolveTransportation(ctx, supply, demand, cost, alg, cf.progressFunc())
+	if err != nil {
+		return err
+	}
+	if cf.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	}
+	fmt.Printf("cost: %g\n", plan.Cost)
+	for _, row := range plan.Ship {
+		fields := make([]string, len(row))
+		for j, x := range row {
+			fields[j] = strconv.FormatFloat(x, 'g', -1, 64)
+		}
+		fmt.Println(strings.Join(fields, " "))
+	}
+	return nil
+}
+
+type bipartiteResult struct {
+	Left     []int    `json:"left"`
+	Right    []int    `json:"right"`
+	Matching [][2]int `json:"matching"`
+	Cover    []int    `json:"cover"`
+}
+
+func runBipartite(args []string) error {
+	fs, cf := newFlagSet("bipartite", "<graph>")
+	addRunFlags(fs, cf)
+	addSVGFlag(fs, cf)
+	graph, err := parseInput(fs, cf, args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cf.context()
+	defer cancel()
+	m, err := graph.HopcroftKarp(ctx, cf.progressFunc())
+	if err != nil {
+		return err
+	}
+	cover, err := graph.KonigVertexCover(ctx, cf.progressFunc())
+	if err != nil {
+		return err
+	}
+	res := bipartiteResult{Left: m.Left, Right: m.Right, Matching: m.Edges, Cover: cover}
+	if err := drawSolution(&graph, cf, render.Overlay{Edges: res.Matching, Vertices: res.Cover}); err != nil {
+		return err
+	}
+	if cf.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	fmt.Printf("left: %v\nright: %v\n", res.Left, res.Right)
+	fmt.Printf("matching (%d): %v\n", len(res.Matching), res.Matching)
+	fmt.Printf("vertex cover (%d): %v\n", len(res.Cover), res.Cover)
+	return nil
+}
+
+func runMatching(args []string) error {
+	fs, cf := newFlagSet("matching", "<graph>")
+	addRunFlags(fs, cf)
+	addSVGFlag(fs, cf)
+	graph, err := parseInput(fs, cf, args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cf.context()
+	defer cancel()
+	m, err := graph.MaximumMatching(ctx, cf.progressFunc())
+	if err != nil && !stoppedEarly(err) {
+		return err
+	}
+	if drawErr := drawSolution(&graph, cf, render.Overlay{Edges: m.Edges}); drawErr != nil {
+		return drawErr
+	}
+
+	if cf.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(m); encErr != nil {
+			return encErr
+		}
+	} else {
+		fmt.Printf("matching (%d): %v\n", len(m.Edges), m.Edges)
+	}
+	if err != nil {
+		return fmt.Errorf("stopped early, the result is incomplete: %w", err)
+	}
+	return nil
+}
+
+func runAssign(args []string) error {
+	fs, cf := newFlagSet("assign", "<cost matrix>")
+	addRunFlags(fs, cf)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	cost, err := readNumberRows(positional[0])
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cf.context()
+	defer cancel()
+	a, err := g.SolveAssignment(ctx, cost, cf.progressFunc())
+	if err != nil {
+		return err
+	}
+	if cf.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(a)
+	}
+	fmt.Printf("cost: %g\n", a.Cost)
+	for i, j := range a.Column {
+		if j >= 0 {
+			fmt.Printf("  %d -> %d: %g\n", i+1, j+1, cost[i][j])
+		}
+	}
+	return nil
+}
+
+type graphInfo struct {
+	Vertices  int   `json:"vertices"`
+	Edges     int   `json:"edges"`
+	Directed  bool  `json:"directed"`
+	Weighted  bool  `json:"weighted"`
+	MinDegree int   `json:"min_degree"`
+	MaxDegree int   `json:"max_degree"`
+	Even      int   `json:"even_degree_vertices"`
+	Odd       int   `json:"odd_degree_vertices"`
+	Degrees   []int `json:"degrees_sorted"`
+}
+
+func runInfo(args []string) error {
+	fs, cf := newFlagSet("info", "<graph>")
+	graph, err := parseInput(fs, cf, args)
+	if err != nil {
+		return err
+	}
+
+	info := graphInfo{
+		Vertices: len(graph.AdjMatrix),
+		Edges:    len(graph.Edges),
+		Directed: graph.Directed,
+		Weighted: graph.Weighted,
+	}
+	if info.Vertices > 0 {
+		info.MinDegree, info.MaxDegree = graph.GetMinMaxDegree()
+		info.Even, info.Odd = graph.GetEvenOddDegreeCounts()
+		info.Degrees = graph.SortedByDegrees()
+	}
+
+	if cf.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+	fmt.Printf("vertices: %d\nedges: %d\ndirected: %t\nweighted: %t\n", info.Vertices, info.Edges, info.Directed, info.Weighted)
+	fmt.Printf("degree: min %d, max %d\neven/odd degree vertices: %d/%d\n", info.MinDegree, info.MaxDegree, info.Even, info.Odd)
+	fmt.Printf("degrees (sorted): %v\n", info.Degrees)
+	return nil
+}
+
+func runConvert(args []string) error {
+	fs, cf := newFlagSet("convert", "<input> <output|->")
+	var to string
+	fs.StringVar(&to, "to", "", "output format (default: guessed from extension)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		fs.Usage()
+		return errUsage
+	}
+
+	graph, err := graphio.Load(positional[0], cf.format, cf.directed)
+	if err != nil {
+		return err
+	}
+	return graphio.Save(&graph, positional[1], to)
+}
+
+var generateModels = []string{"gnp", "gnm", "ba", "ws", "regular", "grid", "torus", "complete", "geometric", "bipartite"}
+
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	var (
+		n, m, k, d, rows, cols, n2 int
+		p, beta, radius            float64
+		seed                       int64
+		to                         string
+		gen                        generate.Generator
+	)
+	fs.IntVar(&n, "n", 10, "number of vertices (size of the first part for bipartite)")
+	fs.IntVar(&m, "m", 0, "edges for gnm; edges per new vertex for ba (default 2)")
+	fs.IntVar(&k, "k", 4, "ring neighbours for ws")
+	fs.IntVar(&d, "d", 3, "degree for regular")
+	fs.IntVar(&rows, "rows", 3, "rows for grid and torus")
+	fs.IntVar(&cols, "cols", 3, "columns for grid and torus")
+	fs.IntVar(&n2, "n2", 0, "size of the second part for bipartite (default n)")
+	fs.Float64Var(&p, "p", 0.3, "edge probability for gnp and bipartite")
+	fs.Float64Var(&beta, "beta", 0.1, "rewiring probability for ws")
+	fs.Float64Var(&radius, "radius", 0, "connection radius for geometric (0: complete)")
+	fs.Int64Var(&seed, "seed", 1, "random seed")
+	fs.BoolVar(&gen.Directed, "directed", false, "directed variant (gnp, gnm, complete, bipartite)")
+	fs.BoolVar(&gen.Weighted, "weighted", false, "random integer edge weights")
+	fs.IntVar(&gen.MinWeight, "min-weight", 1, "smallest random weight")
+	fs.IntVar(&gen.MaxWeight, "max-weight", 10, "largest random weight")
+	fs.StringVar(&to, "to", "", "output format (default: guessed from extension, edgelist for stdout)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: graphopt generate [flags] <model> <output|->\n\nmodels: %s\n\n", strings.Join(generateModels, ", "))
+		fs.PrintDefaults()
+	}
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		fs.Usage()
+		return errUsage
+	}
+	model, output := positional[0], positional[1]
+	if output == "-" && to == "" {
+		to = graphio.EdgeList
+	}
+
+	gen.Seed(seed)
+
+	var graph g.Graph
+	switch model {
+	case "gnp":
+		graph, err = gen.ErdosRenyi(n, p)
+	case "gnm":
+		graph, err = gen.ErdosRenyiM(n, m)
+	case "ba":
+		if m == 0 {
+			m = 2
+		}
+		graph, err = gen.BarabasiAlbert(n, m)
+	case "ws":
+		graph, err = gen.WattsStrogatz(n, k, beta)
+	case "regular":
+		graph, err = gen.RandomRegular(n, d)
+	case "grid", "torus":
+		graph, err = gen.Grid(rows, cols, model == "torus")
+	case "complete":
+		graph, err = gen.Complete(n)
+	case "geometric":
+		graph, err = gen.Geometric(n, radius)
+	case "bipartite":
+		if n2 == 0 {
+			n2 = n
+		}
+		graph, err = gen.Bipartite(n, n2, p)
+	default:
+		return fmt.Errorf("unknown model %q (known: %s)", model, strings.Join(generateModels, ", "))
+	}
+	if err != nil {
+		return err
+	}
+	return graphio.Save(&graph, output, to)
+}
+
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	var (
+		cfg       bench.Config
+		solvers   string
+		bestKnown string
+		output    string
+		to        string
+	)
+	fs.StringVar(&solvers, "solvers", "cover,cover-exact,tsp,tsp-exact", "comma-separated solvers (known: "+strings.Join(solver.Names(), ", ")+")")
+	fs.DurationVar(&cfg.Timeout, "timeout", 10*time.Second, "time limit of a single run (0: none)")
+	fs.IntVar(&cfg.Repetitions, "reps", 3, "runs of every solver on every instance")
+	fs.BoolVar(&cfg.Directed, "directed", false, "treat edge lists without a header as directed")
+	fs.StringVar(&bestKnown, "best", "", "CSV file with best known costs (instance,problem,cost)")
+	fs.StringVar(&output, "o", "-", "output file")
+	fs.StringVar(&to, "to", "", "output format: csv or md (default: guessed from -o, csv for stdout)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: graphopt bench [flags] <instance dir>\n")
+		fs.PrintDefaults()
+	}
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	if to == "" {
+		to = "csv"
+		if ext := strings.ToLower(filepath.Ext(output)); ext == ".md" || ext == ".markdown" {
+			to = "md"
+		}
+	}
+	write := bench.WriteCSV
+	switch to {
+	case "csv":
+	case "md", "markdown":
+		write = bench.WriteMarkdown
+	default:
+		return fmt.Errorf("unknown output format %q (csv or md)", to)
+	}
+
+	selected, err := solver.LookupAll(strings.Split(solvers, ","))
+	if err != nil {
+		return err
+	}
+	if bestKnown != "" {
+		if cfg.BestKnown, err = bench.LoadBestKnown(bestKnown); err != nil {
+			return err
+		}
+	}
+	instances, err := bench.Instances(positional[0])
+	if err != nil {
+		return err
+	}
+
+	cfg.Progress = os.Stderr
+	rows, err := bench.Run(instances, selected, cfg)
+	if err != nil {
+		return err
+	}
+	if output == "-" {
+		return write(os.Stdout, rows)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := write(file, rows); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+var animatedAlgorithms = []string{"cover", "mst", "fleury", "tsp", "cpp"}
+
+func runAnimate(args []string) error {
+	fs, cf := newFlagSet("animate", "<"+strings.Join(animatedAlgorithms, "|")+"> <graph> <output.html|dir>")
+	frameFormat := fs.String("frames", "svg", "frame format when writing to a directory: svg or dot")
+	delay := fs.Int("delay", 800, "milliseconds per frame in the HTML player")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 3 {
+		fs.Usage()
+		return errUsage
+	}
+	algorithm, output := positional[0], positional[2]
+	graph, err := graphio.Load(positional[1], cf.format, cf.directed)
+	if err != nil {
+		return err
+	}
+
+	// Algorytmy, które zmieniają graf, działają na kopii; rysujemy graf po zmianach,
+	// żeby było widać dołożone krawędzie
+	ctx := context.Background()
+	var rec g.Recorder
+	drawn := &graph
+	switch algorithm {
+	case "cover":
+		_, err = graph.ApproximateVertexCover(ctx, &rec)
+	case "mst":
+		_, err = graph.KruskalMST(ctx, &rec)
+	case "tsp":
+		_, err = graph.Christofides(ctx, &rec, nil)
+	case "fleury":
+		work := graph.Clone()
+		_, err = work.FleurysAlgorithm(ctx, &rec)
+	case "cpp":
+		work := graph.Clone()
+		_, _, err = work.ChinesePostmanProblem(ctx, &rec, nil)
+		// fleury zeruje macierz sąsiedztwa kopii, więc krawędzie odtwarzamy z listy
+		after := g.NewGraph(len(graph.AdjMatrix), false, true)
+		for _, e := range work.Edges {
+			after.AddEdge(e[0], e[1], graph.WeightMatrix[e[0]-1][e[1]-1])
+		}
+		after.Labels, after.Coords = graph.Labels, graph.Coords
+		drawn = &after
+	default:
+		return fmt.Errorf("cannot animate %q (known: %s)", algorithm, strings.Join(animatedAlgorithms, ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	frames := render.Frames(rec.Events)
+	opts := render.Options{ShowWeights: graph.Weighted}
+	if len(graph.Coords) == len(graph.AdjMatrix) {
+		opts.Layout = render.Coordinates
+	}
+	if ext := strings.ToLower(filepath.Ext(output)); ext == ".html" || ext == ".htm" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		title := fmt.Sprintf("%s: %s", algorithm, filepath.Base(positional[1]))
+		if err := render.WriteHTML(file, drawn, frames, title, *delay, opts); err != nil {
+			return err
+		}
+		return file.Close()
+	}
+	return render.WriteFrames(output, drawn, frames, *frameFormat, opts)
+}
+
+func runREPL(args []string) error {
+	fs, cf := newFlagSet("repl", "[graph]")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	shell := repl.New(os.Stdin, os.Stdout)
+	if home, err := os.UserHomeDir(); err == nil {
+		shell.HistoryFile = filepath.Join(home, ".graphopt_history")
+	}
+	if len(positional) == 1 {
+		graph, err := graphio.Load(positional[0], cf.format, cf.directed)
+		if err != nil {
+			return err
+		}
+		shell.SetGraph(graph)
+	}
+	return shell.Run()
+}
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	var cfg server.Config
+	fs.DurationVar(&cfg.Timeout, "timeout", 30*time.Second, "default time limit of a request")
+	fs.DurationVar(&cfg.MaxTimeout, "max-timeout", 10*time.Minute, "largest time limit a request may ask for")
+	fs.IntVar(&cfg.Workers, "workers", 4, "number of asynchronous jobs run at the same time")
+	fs.IntVar(&cfg.MaxVertices, "max-vertices", 1000, "largest graph a request may send")
+	fs.IntVar(&cfg.MaxPendingJobs, "max-pending-jobs", 64, "asynchronous jobs that may be queued or running")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: graphopt serve [flags]\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(cfg).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("graphopt: listening on %s", *addr)
+	return srv.ListenAndServe()
+}
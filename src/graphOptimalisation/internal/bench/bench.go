@@ -0,0 +1,299 @@
+// Package bench runs solvers over a set of instance files and reports their
+// cost, gap to the best known value, running time and memory use.
+package bench
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+	"github.com/Simikao/graphOptimalisation/internal/graphio"
+	"github.com/Simikao/graphOptimalisation/internal/solver"
+)
+
+// Statusy wiersza wyników
+const (
+	StatusOK      = "ok"
+	StatusTimeout = "timeout"
+	StatusError   = "error"
+)
+
+// Instances lists the graph files in dir whose format graphio can detect.
+func Instances(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		format, err := graphio.Detect(e.Name(), "")
+		if err != nil || format == graphio.SVG {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, e.Name()))
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no graph files in %s", dir)
+	}
+	return paths, nil
+}
+
+// BestKnown maps an instance file name (without directory) and a problem to
+// the best known cost.
+type BestKnown map[string]map[string]float64
+
+// LoadBestKnown reads a CSV file with the columns instance,problem,cost. A
+// first line starting with "instance" is treated as a header.
+func LoadBestKnown(path string) (BestKnown, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = 3
+	r.Comment = '#'
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	best := make(BestKnown)
+	for i, rec := range records {
+		if i == 0 && rec[0] == "instance" {
+			continue
+		}
+		cost, err := strconv.ParseFloat(strings.TrimSpace(rec[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: record %d: invalid cost %q", path, i+1, rec[2])
+		}
+		instance, problem := strings.TrimSpace(rec[0]), strings.TrimSpace(rec[1])
+		if best[instance] == nil {
+			best[instance] = make(map[string]float64)
+		}
+		best[instance][problem] = cost
+	}
+	return best, nil
+}
+
+type Config struct {
+	// Timeout limits a single run of a solver; zero means no limit.
+	Timeout time.Duration
+	// Repetitions of every (instance, solver) pair; the reported time is the
+	// mean over them. At least one run is made.
+	Repetitions int
+	// Directed is passed to graphio for edge lists without a header.
+	Directed bool
+	// BestKnown overrides the best cost found by the solvers themselves.
+	BestKnown BestKnown
+	// Progress, if set, receives one line per finished (instance, solver) pair.
+	Progress io.Writer
+}
+
+// Row is the result of one solver on one instance.
+type Row struct {
+	Instance string
+	Vertices int
+	Edges    int
+	Solver   string
+	Problem  string
+	Status   string
+	Error    string
+	Cost     float64
+	// Best is the best known cost, or NaN if there is none.
+	Best float64
+	// Time is the mean over the successful repetitions.
+	Time time.Duration
+	// Alloc is the mean number of bytes allocated per run.
+	Alloc uint64
+}
+
+// Ratio is Cost/Best, or NaN if unknown.
+func (r Row) Ratio() float64 {
+	if r.Status != StatusOK || math.IsNaN(r.Best) {
+		return math.NaN()
+	}
+	if r.Best == 0 {
+		if r.Cost == 0 {
+			return 1
+		}
+		return math.Inf(1)
+	}
+	return r.Cost / r.Best
+}
+
+// Gap is the relative distance to Best in percent, or NaN if unknown.
+func (r Row) Gap() float64 {
+	return (r.Ratio() - 1) * 100
+}
+
+// measure runs s once, stopping it when timeout expires.
+func measure(s solver.Solver, graph *g.Graph, timeout time.Duration) (cost float64, elapsed time.Duration, alloc uint64, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("internal error: %v", p)
+		}
+	}()
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	res, err := solver.Run(context.Background(), s, graph, solver.Options{Timeout: timeout})
+	runtime.ReadMemStats(&after)
+	return res.Cost, res.Runtime, after.TotalAlloc - before.TotalAlloc, err
+}
+
+// Run runs every solver on every instance and fills in the best known costs.
+// An instance that cannot be read is an error; a solver that fails or times
+// out only marks its row.
+func Run(instances []string, solvers []solver.Solver, cfg Config) ([]Row, error) {
+	reps := max(cfg.Repetitions, 1)
+	var rows []Row
+	for _, path := range instances {
+		graph, err := graphio.Load(path, "", cfg.Directed)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range solvers {
+			row := Row{
+				Instance: filepath.Base(path),
+				Vertices: len(graph.AdjMatrix),
+				Edges:    len(graph.Edges),
+				Solver:   s.Name(),
+				Problem:  string(s.Problem()),
+				Status:   StatusOK,
+				Best:     math.NaN(),
+			}
+			var total time.Duration
+			var alloc uint64
+			for i := 0; i < reps; i++ {
+				cost, elapsed, bytes, err := measure(s, &graph, cfg.Timeout)
+				if errors.Is(err, context.DeadlineExceeded) {
+					row.Status, row.Time = StatusTimeout, elapsed
+					break
+				}
+				if err != nil {
+					row.Status, row.Error = StatusError, err.Error()
+					break
+				}
+				row.Cost = cost
+				total += elapsed
+				alloc += bytes
+			}
+			if row.Status == StatusOK {
+				row.Time = total / time.Duration(reps)
+				row.Alloc = alloc / uint64(reps)
+			}
+			if cfg.Progress != nil {
+				fmt.Fprintf(cfg.Progress, "%s %s: %s\n", row.Instance, row.Solver, row.Status)
+			}
+			rows = append(rows, row)
+		}
+	}
+	fillBest(rows, cfg.BestKnown)
+	return rows, nil
+}
+
+// fillBest sets Best from known values, or else from the lowest cost any
+// solver reached for the same instance and problem.
+func fillBest(rows []Row, known BestKnown) {
+	type key struct{ instance, problem string }
+	best := make(map[key]float64)
+	for _, r := range rows {
+		if r.Status != StatusOK {
+			continue
+		}
+		k := key{r.Instance, r.Problem}
+		if b, ok := best[k]; !ok || r.Cost < b {
+			best[k] = r.Cost
+		}
+	}
+	for i := range rows {
+		k := key{rows[i].Instance, rows[i].Problem}
+		if b, ok := known[k.instance][k.problem]; ok {
+			rows[i].Best = b
+		} else if b, ok := best[k]; ok {
+			rows[i].Best = b
+		}
+	}
+}
+
+var header = []string{"instance", "vertices", "edges", "solver", "problem", "status", "cost", "best", "ratio", "gap_percent", "time_ms", "alloc_bytes"}
+
+func formatFloat(x float64) string {
+	if math.IsNaN(x) {
+		return ""
+	}
+	return strconv.FormatFloat(x, 'g', 6, 64)
+}
+
+func (r Row) fields() []string {
+	cost := ""
+	if r.Status == StatusOK {
+		cost = formatFloat(r.Cost)
+	}
+	status := r.Status
+	if r.Error != "" {
+		status += ": " + r.Error
+	}
+	return []string{
+		r.Instance,
+		strconv.Itoa(r.Vertices),
+		strconv.Itoa(r.Edges),
+		r.Solver,
+		r.Problem,
+		status,
+		cost,
+		formatFloat(r.Best),
+		formatFloat(r.Ratio()),
+		formatFloat(r.Gap()),
+		strconv.FormatFloat(float64(r.Time.Microseconds())/1000, 'f', 3, 64),
+		strconv.FormatUint(r.Alloc, 10),
+	}
+}
+
+func WriteCSV(w io.Writer, rows []Row) error {
+	cw := csv.NewWriter(w)
+	cw.Write(header)
+	for _, r := range rows {
+		cw.Write(r.fields())
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func WriteMarkdown(w io.Writer, rows []Row) error {
+	sorted := append([]Row(nil), rows...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Instance != sorted[j].Instance {
+			return sorted[i].Instance < sorted[j].Instance
+		}
+		return sorted[i].Problem < sorted[j].Problem
+	})
+
+	fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+	fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(header)))
+	for _, r := range sorted {
+		fields := r.fields()
+		for i, f := range fields {
+			fields[i] = strings.ReplaceAll(f, "|", `\|`)
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(fields, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+// Package generate builds random and structured graphs for testing and
+// benchmarking. Every generator draws from the Generator's own seeded source,
+// so the same seed and parameters always give the same graph.
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+var ErrInvalidParameter = errors.New("invalid generator parameter")
+
+// maxAttempts bounds the restarts of generators that can fail by chance.
+const maxAttempts = 1000
+
+type Generator struct {
+	rng *rand.Rand
+
+	// Directed only applies to models that have a directed variant
+	// (ErdosRenyi, ErdosRenyiM, Complete, Bipartite).
+	Directed bool
+	// Weighted gives every edge a random integer weight from
+	// [MinWeight, MaxWeight]. Geometric graphs are always weighted.
+	Weighted             bool
+	MinWeight, MaxWeight int
+}
+
+func New(seed int64) *Generator {
+	gen := &Generator{MinWeight: 1, MaxWeight: 10}
+	gen.Seed(seed)
+	return gen
+}
+
+// Seed restarts the random source, so a Generator declared as a plain value
+// must be seeded before use.
+func (gen *Generator) Seed(seed int64) {
+	gen.rng = rand.New(rand.NewSource(seed))
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidParameter, fmt.Sprintf(format, args...))
+}
+
+func (gen *Generator) newGraph(n int, directed bool) (g.Graph, error) {
+	if n < 1 {
+		return g.Graph{}, invalid("n must be positive, got %d", n)
+	}
+	if gen.Weighted && gen.MinWeight > gen.MaxWeight {
+		return g.Graph{}, invalid("min weight %d exceeds max weight %d", gen.MinWeight, gen.MaxWeight)
+	}
+	return g.NewGraph(n, directed, gen.Weighted), nil
+}
+
+func (gen *Generator) weight() float64 {
+	return float64(gen.MinWeight + gen.rng.Intn(gen.MaxWeight-gen.MinWeight+1))
+}
+
+func hasEdge(graph *g.Graph, u, v int) bool {
+	return graph.AdjMatrix[u-1][v-1] == 1
+}
+
+func (gen *Generator) addEdge(graph *g.Graph, u, v int) {
+	if graph.Weighted {
+		graph.AddEdge(u, v, gen.weight())
+	} else {
+		graph.AddEdge(u, v)
+	}
+}
+
+func checkProbability(name string, p float64) error {
+	if p < 0 || p > 1 || math.IsNaN(p) {
+		return invalid("%s must be in [0, 1], got %v", name, p)
+	}
+	return nil
+}
+
+// ErdosRenyi returns G(n, p): every pair of vertices is joined independently
+// with probability p.
+func (gen *Generator) ErdosRenyi(n int, p float64) (g.Graph, error) {
+	if err := checkProbability("p", p); err != nil {
+		return g.Graph{}, err
+	}
+	graph, err := gen.newGraph(n, gen.Directed)
+	if err != nil {
+		return g.Graph{}, err
+	}
+	for u := 1; u <= n; u++ {
+		for v := 1; v <= n; v++ {
+			if u == v || (!gen.Directed && v < u) {
+				continue
+			}
+			if gen.rng.Float64() < p {
+				gen.addEdge(&graph, u, v)
+			}
+		}
+	}
+	return graph, nil
+}
+
+// ErdosRenyiM returns G(n, m): m edges chosen uniformly among all possible ones.
+func (gen *Generator) ErdosRenyiM(n, m int) (g.Graph, error) {
+	graph, err := gen.newGraph(n, gen.Directed)
+	if err != nil {
+		return g.Graph{}, err
+	}
+	pairs := n * (n - 1)
+	if !gen.Directed {
+		pairs /= 2
+	}
+	if m < 0 || m > pairs {
+		return g.Graph{}, invalid("m must be in [0, %d] for n=%d, got %d", pairs, n, m)
+	}
+
+	// Gęste grafy: losujemy krawędzie do usunięcia z grafu pełnego
+	if m > pairs/2 {
+		all, err := gen.Complete(n)
+		if err != nil {
+			return g.Graph{}, err
+		}
+		for removed := 0; removed < pairs-m; {
+			u, v := gen.rng.Intn(n)+1, gen.rng.Intn(n)+1
+			if u != v && hasEdge(&all, u, v) {
+				all.RemoveEdge(u, v)
+				removed++
+			}
+		}
+		return all, nil
+	}
+
+	for added := 0; added < m; {
+		u, v := gen.rng.Intn(n)+1, gen.rng.Intn(n)+1
+		if !gen.Directed && u > v {
+			u, v = v, u
+		}
+		if u != v && !hasEdge(&graph, u, v) {
+			gen.addEdge(&graph, u, v)
+			added++
+		}
+	}
+	return graph, nil
+}
+
+// Complete returns K_n (or the complete digraph if Directed is set).
+func (gen *Generator) Complete(n int) (g.Graph, error) {
+	return gen.ErdosRenyi(n, 1)
+}
+
+// BarabasiAlbert grows a scale-free graph by preferential attachment: it
+// starts from a clique on m+1 vertices and joins every further vertex to m
+// distinct existing vertices chosen with probability proportional to degree.
+func (gen *Generator) BarabasiAlbert(n, m int) (g.Graph, error) {
+	if m < 1 || m >= n {
+		return g.Graph{}, invalid("m must be in [1, n), got m=%d n=%d", m, n)
+	}
+	graph, err := gen.newGraph(n, false)
+	if err != nil {
+		return g.Graph{}, err
+	}
+
+	// Każdy wierzchołek występuje w targets tyle razy, ile wynosi jego stopień
+	var targets []int
+	for u := 1; u <= m+1; u++ {
+		for v := u + 1; v <= m+1; v++ {
+			gen.addEdge(&graph, u, v)
+			targets = append(targets, u, v)
+		}
+	}
+	for u := m + 2; u <= n; u++ {
+		chosen := make(map[int]bool, m)
+		for len(chosen) < m {
+			chosen[targets[gen.rng.Intn(len(targets))]] = true
+		}
+		// Kolejność z mapy jest losowa, więc dodajemy krawędzie po numerach
+		for v := 1; v < u; v++ {
+			if chosen[v] {
+				gen.addEdge(&graph, u, v)
+				targets = append(targets, u, v)
+			}
+		}
+	}
+	return graph, nil
+}
+
+// WattsStrogatz returns a small-world graph: a ring where every vertex is
+// joined to its k nearest neighbours, with every edge rewired to a random
+// endpoint with probability beta.
+func (gen *Generator) WattsStrogatz(n, k int, beta float64) (g.Graph, error) {
+	if k < 2 || k%2 != 0 || k >= n {
+		return g.Graph{}, invalid("k must be even and in [2, n), got k=%d n=%d", k, n)
+	}
+	if err := checkProbability("beta", beta); err != nil {
+		return g.Graph{}, err
+	}
+	graph, err := gen.newGraph(n, false)
+	if err != nil {
+		return g.Graph{}, err
+	}
+
+	for j := 1; j <= k/2; j++ {
+		for u := 1; u <= n; u++ {
+			gen.addEdge(&graph, u, (u-1+j)%n+1)
+		}
+	}
+	// Przepinamy istniejące krawędzie, więc graf zachowuje n*k/2 krawędzi;
+	// wierzchołek połączony już ze wszystkimi zostawia krawędź na miejscu
+	for j := 1; j <= k/2; j++ {
+		for u := 1; u <= n; u++ {
+			v := (u-1+j)%n + 1
+			if gen.rng.Float64() >= beta || !hasEdge(&graph, u, v) || graph.GetDegree(u) == n-1 {
+				continue
+			}
+			w := gen.rng.Intn(n) + 1
+			for w == u || hasEdge(&graph, u, w) {
+				w = gen.rng.Intn(n) + 1
+			}
+			graph.RemoveEdge(u, v)
+			gen.addEdge(&graph, u, w)
+		}
+	}
+	return graph, nil
+}
+
+// RandomRegular returns a graph chosen at random among d-regular graphs on n
+// vertices, using the pairing algorithm of Steger and Wormald.
+func (gen *Generator) RandomRegular(n, d int) (g.Graph, error) {
+	if d < 0 || d >= n || n*d%2 != 0 {
+		return g.Graph{}, invalid("need 0 <= d < n and n*d even, got n=%d d=%d", n, d)
+	}
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		graph, err := gen.newGraph(n, false)
+		if err != nil {
+			return g.Graph{}, err
+		}
+		if gen.pairStubs(&graph, d) {
+			return graph, nil
+		}
+	}
+	return g.Graph{}, fmt.Errorf("no %d-regular graph on %d vertices found after %d attempts", d, n, maxAttempts)
+}
+
+// pairStubs repeatedly pairs the free half-edges at random, keeping the pairs
+// that make a new simple edge. It reports false if it gets stuck.
+func (gen *Generator) pairStubs(graph *g.Graph, d int) bool {
+	n := len(graph.AdjMatrix)
+	stubs := make([]int, 0, n*d)
+	for v := 1; v <= n; v++ {
+		for i := 0; i < d; i++ {
+			stubs = append(stubs, v)
+		}
+	}
+
+	for len(stubs) > 0 {
+		gen.rng.Shuffle(len(stubs), func(i, j int) { stubs[i], stubs[j] = stubs[j], stubs[i] })
+		var left []int
+		for i := 0; i+1 < len(stubs); i += 2 {
+			u, v := stubs[i], stubs[i+1]
+			if u != v && !hasEdge(graph, u, v) {
+				gen.addEdge(graph, u, v)
+			} else {
+				left = append(left, u, v)
+			}
+		}
+		if len(left) == 0 {
+			return true
+		}
+
+		// Jeśli żadnej pary pozostałych wierzchołków nie da się już połączyć, zaczynamy od nowa
+		stuck := true
+		for i := 0; i < len(left) && stuck; i++ {
+			for j := i + 1; j < len(left); j++ {
+				if left[i] != left[j] && !hasEdge(graph, left[i], left[j]) {
+					stuck = false
+					break
+				}
+			}
+		}
+		if stuck {
+			return false
+		}
+		stubs = left
+	}
+	return true
+}
+
+// Grid returns the rows x cols grid graph, or the torus if wrap is set.
+// Vertex (r, c) is numbered r*cols+c+1 and placed at coordinates (c, r).
+func (gen *Generator) Grid(rows, cols int, wrap bool) (g.Graph, error) {
+	if rows < 1 || cols < 1 {
+		return g.Graph{}, invalid("grid dimensions must be positive, got %dx%d", rows, cols)
+	}
+	graph, err := gen.newGraph(rows*cols, false)
+	if err != nil {
+		return g.Graph{}, err
+	}
+
+	id := func(r, c int) int { return r*cols + c + 1 }
+	link := func(u, v int) {
+		if u != v && !hasEdge(&graph, u, v) {
+			gen.addEdge(&graph, u, v)
+		}
+	}
+	graph.Coords = make([][2]float64, rows*cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			graph.Coords[id(r, c)-1] = [2]float64{float64(c), float64(r)}
+			if c+1 < cols || wrap {
+				link(id(r, c), id(r, (c+1)%cols))
+			}
+			if r+1 < rows || wrap {
+				link(id(r, c), id((r+1)%rows, c))
+			}
+		}
+	}
+	return graph, nil
+}
+
+// Geometric places n points uniformly in the unit square and joins the pairs
+// closer than radius, weighting each edge by the Euclidean distance. With
+// radius <= 0 the graph is complete, which makes its weights metric as
+// required by Christofides. The points are stored in Coords.
+func (gen *Generator) Geometric(n int, radius float64) (g.Graph, error) {
+	if n < 1 {
+		return g.Graph{}, invalid("n must be positive, got %d", n)
+	}
+	graph := g.NewGraph(n, false, true)
+	graph.Coords = make([][2]float64, n)
+	for i := range graph.Coords {
+		graph.Coords[i] = [2]float64{gen.rng.Float64(), gen.rng.Float64()}
+	}
+	for u := 1; u <= n; u++ {
+		for v := u + 1; v <= n; v++ {
+			a, b := graph.Coords[u-1], graph.Coords[v-1]
+			dist := math.Hypot(a[0]-b[0], a[1]-b[1])
+			if radius <= 0 || dist <= radius {
+				graph.AddEdge(u, v, dist)
+			}
+		}
+	}
+	return graph, nil
+}
+
+// Bipartite returns a random bipartite graph with parts 1..n1 and
+// n1+1..n1+n2, joining every cross pair with probability p. Directed graphs
+// only have arcs from the first part to the second.
+func (gen *Generator) Bipartite(n1, n2 int, p float64) (g.Graph, error) {
+	if n1 < 1 || n2 < 1 {
+		return g.Graph{}, invalid("both parts must be non-empty, got %d and %d", n1, n2)
+	}
+	if err := checkProbability("p", p); err != nil {
+		return g.Graph{}, err
+	}
+	graph, err := gen.newGraph(n1+n2, gen.Directed)
+	if err != nil {
+		return g.Graph{}, err
+	}
+
+	// Dwie kolumny, żeby render.Coordinates od razu pokazał podział
+	graph.Coords = make([][2]float64, n1+n2)
+	for u := 1; u <= n1; u++ {
+		graph.Coords[u-1] = [2]float64{0, float64(u - 1)}
+	}
+	for v := n1 + 1; v <= n1+n2; v++ {
+		graph.Coords[v-1] = [2]float64{float64(max(n1, n2)) / 2, float64(v - n1 - 1)}
+	}
+	for u := 1; u <= n1; u++ {
+		for v := n1 + 1; v <= n1+n2; v++ {
+			if gen.rng.Float64() < p {
+				gen.addEdge(&graph, u, v)
+			}
+		}
+	}
+	return graph, nil
+}
@@ -0,0 +1,231 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+// models builds one graph of every kind with gen.
+var models = []struct {
+	name  string
+	build func(gen *Generator) (g.Graph, error)
+}{
+	{"erdos-renyi", func(gen *Generator) (g.Graph, error) { return gen.ErdosRenyi(12, 0.3) }},
+	{"erdos-renyi-m", func(gen *Generator) (g.Graph, error) { return gen.ErdosRenyiM(12, 20) }},
+	{"erdos-renyi-m dense", func(gen *Generator) (g.Graph, error) { return gen.ErdosRenyiM(12, 60) }},
+	{"barabasi-albert", func(gen *Generator) (g.Graph, error) { return gen.BarabasiAlbert(15, 2) }},
+	{"watts-strogatz", func(gen *Generator) (g.Graph, error) { return gen.WattsStrogatz(14, 4, 0.3) }},
+	{"random-regular", func(gen *Generator) (g.Graph, error) { return gen.RandomRegular(12, 3) }},
+	{"geometric", func(gen *Generator) (g.Graph, error) { return gen.Geometric(12, 0.4) }},
+	{"bipartite", func(gen *Generator) (g.Graph, error) { return gen.Bipartite(5, 7, 0.4) }},
+}
+
+func sameGraph(a, b *g.Graph) bool {
+	if len(a.AdjMatrix) != len(b.AdjMatrix) || len(a.Edges) != len(b.Edges) {
+		return false
+	}
+	for i, e := range a.Edges {
+		if e != b.Edges[i] {
+			return false
+		}
+	}
+	for i := range a.AdjMatrix {
+		for j := range a.AdjMatrix[i] {
+			if a.AdjMatrix[i][j] != b.AdjMatrix[i][j] {
+				return false
+			}
+		}
+	}
+	if a.Weighted != b.Weighted {
+		return false
+	}
+	for _, e := range a.Edges {
+		if a.Weighted && a.WeightMatrix[e[0]-1][e[1]-1] != b.WeightMatrix[e[0]-1][e[1]-1] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkSimple fails unless graph has no loops and every edge is listed once.
+func checkSimple(t *testing.T, graph *g.Graph) {
+	t.Helper()
+	arcs := 0
+	for i, row := range graph.AdjMatrix {
+		if row[i] != 0 {
+			t.Errorf("loop at vertex %d", i+1)
+		}
+		for _, present := range row {
+			arcs += present
+		}
+	}
+	if !graph.Directed {
+		arcs /= 2
+	}
+	if arcs != len(graph.Edges) {
+		t.Errorf("adjacency matrix has %d edges but Edges lists %d", arcs, len(graph.Edges))
+	}
+}
+
+func TestSameSeedSameGraph(t *testing.T) {
+	for _, m := range models {
+		for _, weighted := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/weighted=%t", m.name, weighted), func(t *testing.T) {
+				var graphs [2]g.Graph
+				for i := range graphs {
+					gen := New(42)
+					gen.Weighted = weighted
+					graph, err := m.build(gen)
+					if err != nil {
+						t.Fatal(err)
+					}
+					checkSimple(t, &graph)
+					graphs[i] = graph
+				}
+				if !sameGraph(&graphs[0], &graphs[1]) {
+					t.Error("two generators with the same seed built different graphs")
+				}
+			})
+		}
+	}
+
+	// Seed musi też restartować źródło istniejącego generatora
+	gen := New(1)
+	first, _ := gen.ErdosRenyi(20, 0.5)
+	gen.Seed(1)
+	again, _ := gen.ErdosRenyi(20, 0.5)
+	if !sameGraph(&first, &again) {
+		t.Error("Seed did not restart the random source")
+	}
+	other, _ := New(2).ErdosRenyi(20, 0.5)
+	if sameGraph(&first, &other) {
+		t.Error("seeds 1 and 2 built the same graph")
+	}
+}
+
+func TestErdosRenyiMEdgeCount(t *testing.T) {
+	for _, directed := range []bool{false, true} {
+		pairs := 10 * 9
+		if !directed {
+			pairs /= 2
+		}
+		for _, m := range []int{0, 1, pairs / 3, pairs/2 + 1, pairs - 1, pairs} {
+			gen := New(int64(m))
+			gen.Directed = directed
+			graph, err := gen.ErdosRenyiM(10, m)
+			if err != nil {
+				t.Fatalf("directed=%t m=%d: %v", directed, m, err)
+			}
+			checkSimple(t, &graph)
+			if len(graph.Edges) != m {
+				t.Errorf("directed=%t m=%d: got %d edges", directed, m, len(graph.Edges))
+			}
+		}
+	}
+}
+
+func TestRandomRegularDegrees(t *testing.T) {
+	for _, tt := range []struct{ n, d int }{{10, 0}, {10, 1}, {10, 3}, {9, 4}, {12, 11}, {30, 5}} {
+		graph, err := New(7).RandomRegular(tt.n, tt.d)
+		if err != nil {
+			t.Fatalf("n=%d d=%d: %v", tt.n, tt.d, err)
+		}
+		checkSimple(t, &graph)
+		for v := 1; v <= tt.n; v++ {
+			if deg := graph.GetDegree(v); deg != tt.d {
+				t.Errorf("n=%d d=%d: vertex %d has degree %d", tt.n, tt.d, v, deg)
+			}
+		}
+	}
+}
+
+func TestWattsStrogatzEdgeCount(t *testing.T) {
+	for _, tt := range []struct{ n, k int }{{10, 2}, {10, 4}, {9, 8}, {40, 6}} {
+		for _, beta := range []float64{0, 0.2, 0.7, 1} {
+			graph, err := New(3).WattsStrogatz(tt.n, tt.k, beta)
+			if err != nil {
+				t.Fatalf("n=%d k=%d beta=%v: %v", tt.n, tt.k, beta, err)
+			}
+			checkSimple(t, &graph)
+			if want := tt.n * tt.k / 2; len(graph.Edges) != want {
+				t.Errorf("n=%d k=%d beta=%v: got %d edges, want %d", tt.n, tt.k, beta, len(graph.Edges), want)
+			}
+		}
+	}
+}
+
+func TestBarabasiAlbertEdgeCount(t *testing.T) {
+	for _, tt := range []struct{ n, m int }{{5, 1}, {20, 2}, {30, 4}} {
+		graph, err := New(5).BarabasiAlbert(tt.n, tt.m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkSimple(t, &graph)
+		// Klika na m+1 wierzchołkach, potem m krawędzi na każdy kolejny
+		if want := tt.m*(tt.m+1)/2 + (tt.n-tt.m-1)*tt.m; len(graph.Edges) != want {
+			t.Errorf("n=%d m=%d: got %d edges, want %d", tt.n, tt.m, len(graph.Edges), want)
+		}
+	}
+}
+
+func TestGridEdgeCount(t *testing.T) {
+	gen := New(0)
+	grid, err := gen.Grid(3, 4, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 3*3 + 2*4; len(grid.Edges) != want {
+		t.Errorf("grid: got %d edges, want %d", len(grid.Edges), want)
+	}
+	torus, err := gen.Grid(3, 4, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 2 * 3 * 4; len(torus.Edges) != want {
+		t.Errorf("torus: got %d edges, want %d", len(torus.Edges), want)
+	}
+}
+
+func TestGeneratorWeights(t *testing.T) {
+	gen := New(11)
+	gen.Weighted = true
+	gen.MinWeight, gen.MaxWeight = 3, 5
+	graph, err := gen.ErdosRenyi(15, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range graph.Edges {
+		if w := graph.WeightMatrix[e[0]-1][e[1]-1]; w < 3 || w > 5 {
+			t.Errorf("edge %d-%d has weight %v outside [3, 5]", e[0], e[1], w)
+		}
+	}
+}
+
+func TestGeneratorInvalidParameters(t *testing.T) {
+	gen := New(0)
+	reversed := New(0)
+	reversed.Weighted = true
+	reversed.MinWeight, reversed.MaxWeight = 5, 1
+	tests := []struct {
+		name  string
+		build func() (g.Graph, error)
+	}{
+		{"no vertices", func() (g.Graph, error) { return gen.ErdosRenyi(0, 0.5) }},
+		{"p above 1", func() (g.Graph, error) { return gen.ErdosRenyi(5, 1.5) }},
+		{"too many edges", func() (g.Graph, error) { return gen.ErdosRenyiM(5, 11) }},
+		{"m not below n", func() (g.Graph, error) { return gen.BarabasiAlbert(3, 3) }},
+		{"odd k", func() (g.Graph, error) { return gen.WattsStrogatz(10, 3, 0.1) }},
+		{"odd n*d", func() (g.Graph, error) { return gen.RandomRegular(5, 3) }},
+		{"empty grid", func() (g.Graph, error) { return gen.Grid(0, 3, false) }},
+		{"empty part", func() (g.Graph, error) { return gen.Bipartite(3, 0, 0.5) }},
+		{"min above max weight", func() (g.Graph, error) { return reversed.Complete(4) }},
+	}
+	for _, tt := range tests {
+		if _, err := tt.build(); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, ErrInvalidParameter)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// AllPairs holds the shortest paths between every pair of vertices. Both
+// matrices are indexed by u-1, v-1.
+type AllPairs struct {
+	// Dist[u-1][v-1] is the length of the shortest path from u to v, or +Inf
+	// if there is none.
+	Dist [][]float64
+	// Next[u-1][v-1] is the vertex that follows u on that path, or 0 if
+	// u == v or v is unreachable.
+	Next [][]int
+}
+
+func newAllPairs(n int) *AllPairs {
+	ap := &AllPairs{Dist: make([][]float64, n), Next: make([][]int, n)}
+	for i := range ap.Dist {
+		ap.Dist[i] = make([]float64, n)
+		ap.Next[i] = make([]int, n)
+	}
+	return ap
+}
+
+// Distance returns the length of the shortest path from u to v.
+func (ap *AllPairs) Distance(u, v int) float64 {
+	return ap.Dist[u-1][v-1]
+}
+
+// Path returns the vertices of the shortest path from u to v, both included,
+// or nil if v is unreachable from u.
+func (ap *AllPairs) Path(u, v int) []int {
+	if math.IsInf(ap.Dist[u-1][v-1], 1) {
+		return nil
+	}
+	path := []int{u}
+	for u != v {
+		u = ap.Next[u-1][v-1]
+		if u == 0 || len(path) > len(ap.Next) {
+			return nil // Ujemny cykl psuje macierz następników
+		}
+		path = append(path, u)
+	}
+	return path
+}
+
+// APSPAlgorithm selects how AllPairsShortestPaths works.
+type APSPAlgorithm int
+
+const (
+	// APSPAuto picks Johnson's algorithm for sparse graphs and
+	// Floyd–Warshall for dense ones.
+	APSPAuto APSPAlgorithm = iota
+	APSPFloydWarshall
+	APSPJohnson
+)
+
+func (a APSPAlgorithm) String() string {
+	switch a {
+	case APSPAuto:
+		return "auto"
+	case APSPFloydWarshall:
+		return "floyd-warshall"
+	case APSPJohnson:
+		return "johnson"
+	}
+	return "unknown"
+}
+
+type APSPOptions struct {
+	Algorithm APSPAlgorithm
+	// Workers is the number of sources Johnson's algorithm runs Dijkstra
+	// from at the same time; zero means GOMAXPROCS.
+	Workers int
+	// Progress, if set, receives progress reports.
+	Progress ProgressFunc
+}
+
+// AllPairsShortestPaths computes distances and next hops between all pairs
+// of vertices. Negative weights are allowed; if they form a negative cycle
+// the error wraps ErrNegativeCycle. If ctx is cancelled it returns nil and
+// ctx.Err().
+func (g *Graph) AllPairsShortestPaths(ctx context.Context, opts APSPOptions) (*AllPairs, error) {
+	floydWarshall := func() (*AllPairs, error) {
+		return g.floydWarshall(newReporter(ctx, opts.Progress, "floyd-warshall"))
+	}
+	johnson := func() (*AllPairs, error) {
+		return g.johnson(newReporter(ctx, opts.Progress, "johnson"), opts.Workers)
+	}
+	switch opts.Algorithm {
+	case APSPFloydWarshall:
+		return floydWarshall()
+	case APSPJohnson:
+		return johnson()
+	}
+	// Johnson: O(nm log n), Floyd–Warshall: O(n³); przy gęstym grafie wygrywa prostota
+	n := len(g.AdjMatrix)
+	arcs := len(g.Edges)
+	if !g.Directed {
+		arcs *= 2
+	}
+	if arcs*int(math.Log2(float64(n)+1)) < n*n {
+		return johnson()
+	}
+	return floydWarshall()
+}
+
+// FloydWarshall computes all shortest paths in O(n³). With a negative cycle
+// it still returns the matrices, which are then meaningless, together with an
+// error wrapping ErrNegativeCycle. To receive progress, call
+// AllPairsShortestPaths with APSPOptions.Progress.
+func (g *Graph) FloydWarshall(ctx context.Context) (*AllPairs, error) {
+	return g.floydWarshall(newReporter(ctx, nil, "floyd-warshall"))
+}
+
+func (g *Graph) floydWarshall(rep reporter) (*AllPairs, error) {
+	n := len(g.AdjMatrix)
+	ap := newAllPairs(n)
+	dist, next := ap.Dist, ap.Next
+
+	// O istnieniu krawędzi decyduje AdjMatrix
+	for i := range dist {
+		for j := range dist[i] {
+			dist[i][j] = math.Inf(1)
+			if g.AdjMatrix[i][j] > 0 {
+				dist[i][j] = g.edgeWeight(i+1, j+1)
+				next[i][j] = j + 1
+			}
+		}
+		// Pętla własna o ujemnej wadze to też ujemny cykl
+		if dist[i][i] >= 0 {
+			dist[i][i], next[i][i] = 0, 0
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		if err := rep.step("Floyd-Warshall", k, n); err != nil {
+			return nil, err
+		}
+		for i := 0; i < n; i++ {
+			if math.IsInf(dist[i][k], 1) {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if d := dist[i][k] + dist[k][j]; dist[i][j] > d {
+					dist[i][j] = d
+					next[i][j] = next[i][k]
+				}
+			}
+		}
+	}
+
+	for i := range dist {
+		if dist[i][i] < 0 {
+			return ap, fmt.Errorf("shortest paths: %w through vertex %d", ErrNegativeCycle, i+1)
+		}
+	}
+	return ap, nil
+}
+
+// Johnson computes all shortest paths by running Dijkstra from every vertex,
+// on weights made non-negative with potentials from one run of Bellman–Ford.
+// It takes O(nm log n), which beats Floyd–Warshall on sparse graphs. The
+// runs are split between workers goroutines (zero means GOMAXPROCS). If a
+// negative cycle exists the error is a *NegativeCycleError.
+//
+// Dijkstra runs backwards from every target, so that Next[·][v] comes from a
+// single tree of paths into v; next hops taken from the trees of different
+// sources could go round a cycle of zero length.
+func (g *Graph) Johnson(ctx context.Context, workers int) (*AllPairs, error) {
+	return g.johnson(newReporter(ctx, nil, "johnson"), workers)
+}
+
+func (g *Graph) johnson(rep reporter, workers int) (*AllPairs, error) {
+	ctx := rep.ctx
+	n := len(g.AdjMatrix)
+	adj := g.arcs()
+
+	// Potencjały: odległości od dodatkowego wierzchołka połączonego zerowymi krawędziami
+	potential := make([]float64, n)
+	if _, _, ok := g.negativeEdge(); ok {
+		virtual := make([]arc, n)
+		for v := range virtual {
+			virtual[v] = arc{v, 0}
+		}
+		sp, err := bellmanFord(reporter{ctx: ctx}, append(adj[:n:n], virtual), n+1)
+		if err != nil {
+			return nil, err
+		}
+		copy(potential, sp.Dist)
+	}
+	// Odwrócone łuki z nieujemnymi wagami w' = w + h(u) - h(v)
+	reversed := make([][]arc, n)
+	for u := range adj {
+		for _, a := range adj[u] {
+			// Zaokrąglenia mogą dać -1e-16 zamiast zera
+			w := max(a.weight+potential[u]-potential[a.to], 0)
+			reversed[a.to] = append(reversed[a.to], arc{u, w})
+		}
+	}
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	ap := newAllPairs(n)
+	targets := make(chan int)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, max(n, 1)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Postęp raportuje tylko główna gorutyna
+			inner := reporter{ctx: ctx}
+			for t := range targets {
+				// Poprzednik u w drzewie odwróconego grafu to następnik u na ścieżce do t
+				sp := search(inner, reversed, t+1, 0, BinaryHeap, nil)
+				for u := range sp.Dist {
+					ap.Dist[u][t] = sp.Dist[u] - potential[u] + potential[t]
+					ap.Next[u][t] = sp.Pred[u]
+				}
+				done <- struct{}{}
+			}
+		}()
+	}
+	go func() {
+		defer close(targets)
+		for t := 0; t < n; t++ {
+			select {
+			case targets <- t:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	finished := 0
+	for range done {
+		finished++
+		if finished%max(n/100, 1) == 0 {
+			rep.report("Dijkstra to every vertex", finished, n, math.NaN())
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return ap, nil
+}
@@ -0,0 +1,159 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Simikao/graphOptimalisation/internal/generate"
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+var apspAlgorithms = []g.APSPAlgorithm{g.APSPAuto, g.APSPFloydWarshall, g.APSPJohnson}
+
+// checkPaths verifies that every path reported by ap starts and ends where it
+// should, uses edges of graph and is as long as the reported distance.
+func checkPaths(t *testing.T, graph *g.Graph, ap *g.AllPairs) {
+	t.Helper()
+	n := len(graph.AdjMatrix)
+	for u := 1; u <= n; u++ {
+		for v := 1; v <= n; v++ {
+			path := ap.Path(u, v)
+			if math.IsInf(ap.Distance(u, v), 1) {
+				if path != nil {
+					t.Errorf("path %d -> %d is %v, want none", u, v, path)
+				}
+				continue
+			}
+			if len(path) == 0 || path[0] != u || path[len(path)-1] != v {
+				t.Errorf("path %d -> %d is %v", u, v, path)
+				continue
+			}
+			length := 0.0
+			for i := 1; i < len(path); i++ {
+				a, b := path[i-1], path[i]
+				if !graph.HasEdge(a, b) {
+					t.Errorf("path %d -> %d uses the non-edge %d-%d", u, v, a, b)
+				}
+				length += graph.WeightMatrix[a-1][b-1]
+			}
+			if math.Abs(length-ap.Distance(u, v)) > 1e-9 {
+				t.Errorf("path %d -> %d has length %g, distance is %g", u, v, length, ap.Distance(u, v))
+			}
+		}
+	}
+}
+
+func TestAllPairsShortestPaths(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name     string
+		n        int
+		directed bool
+		edges    [][3]float64
+		want     [][]float64
+	}{
+		{"empty", 0, false, nil, [][]float64{}},
+		{"single vertex", 1, false, nil, [][]float64{{0}}},
+		{
+			"undirected triangle with a shortcut", 3, false,
+			[][3]float64{{1, 2, 1}, {2, 3, 1}, {1, 3, 5}},
+			[][]float64{{0, 1, 2}, {1, 0, 1}, {2, 1, 0}},
+		},
+		{
+			"directed with a negative edge", 4, true,
+			[][3]float64{{1, 2, 4}, {1, 3, 2}, {3, 2, -1}, {2, 4, 3}},
+			[][]float64{{0, 1, 2, 4}, {inf, 0, inf, 3}, {inf, -1, 0, 2}, {inf, inf, inf, 0}},
+		},
+		{
+			"disconnected", 4, false,
+			[][3]float64{{1, 2, 3}, {3, 4, 2}},
+			[][]float64{{0, 3, inf, inf}, {3, 0, inf, inf}, {inf, inf, 0, 2}, {inf, inf, 2, 0}},
+		},
+	}
+	for _, tt := range tests {
+		graph := g.NewGraph(tt.n, tt.directed, true)
+		for _, e := range tt.edges {
+			graph.AddEdge(int(e[0]), int(e[1]), e[2])
+		}
+		for _, alg := range apspAlgorithms {
+			t.Run(tt.name+"/"+alg.String(), func(t *testing.T) {
+				ap, err := graph.AllPairsShortestPaths(context.Background(), g.APSPOptions{Algorithm: alg})
+				if err != nil {
+					t.Fatal(err)
+				}
+				for u := range tt.want {
+					for v, want := range tt.want[u] {
+						if got := ap.Distance(u+1, v+1); got != want {
+							t.Errorf("distance %d -> %d = %g, want %g", u+1, v+1, got, want)
+						}
+					}
+				}
+				checkPaths(t, &graph, ap)
+			})
+		}
+	}
+}
+
+func TestAllPairsNegativeCycle(t *testing.T) {
+	graph := g.NewGraph(3, true, true)
+	graph.AddEdge(1, 2, 1).AddEdge(2, 3, -3).AddEdge(3, 1, 1)
+	for _, alg := range apspAlgorithms {
+		_, err := graph.AllPairsShortestPaths(context.Background(), g.APSPOptions{Algorithm: alg})
+		if !errors.Is(err, g.ErrNegativeCycle) {
+			t.Errorf("%s: got %v, want %v", alg, err, g.ErrNegativeCycle)
+		}
+	}
+}
+
+// acyclic rebuilds graph with every edge pointing from the smaller vertex to
+// the larger, so negative weights cannot form a cycle.
+func acyclic(graph g.Graph) g.Graph {
+	dag := g.NewGraph(len(graph.AdjMatrix), true, true)
+	for _, e := range graph.Edges {
+		u, v := min(e[0], e[1]), max(e[0], e[1])
+		if u != v && !dag.HasEdge(u, v) {
+			dag.AddEdge(u, v, graph.WeightMatrix[e[0]-1][e[1]-1])
+		}
+	}
+	return dag
+}
+
+func TestJohnsonMatchesFloydWarshall(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 20; seed++ {
+		gen := generate.New(seed)
+		gen.Weighted, gen.Directed = true, seed%2 == 0
+		// Rzadkie grafy bywają niespójne, gęste nie
+		graph, err := gen.ErdosRenyi(int(3+seed), 0.1+float64(seed%5)*0.2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seed%4 == 3 {
+			gen.MinWeight = -5
+			if graph, err = gen.ErdosRenyi(int(3+seed), 0.5); err != nil {
+				t.Fatal(err)
+			}
+			graph = acyclic(graph)
+		}
+
+		fw, err := graph.FloydWarshall(ctx)
+		if err != nil {
+			t.Fatalf("seed %d: FloydWarshall: %v", seed, err)
+		}
+		johnson, err := graph.Johnson(ctx, 3)
+		if err != nil {
+			t.Fatalf("seed %d: Johnson: %v", seed, err)
+		}
+		for u := range fw.Dist {
+			for v := range fw.Dist[u] {
+				if a, b := fw.Dist[u][v], johnson.Dist[u][v]; a != b && math.Abs(a-b) > 1e-9 {
+					t.Errorf("seed %d: distance %d -> %d is %g by Floyd-Warshall, %g by Johnson", seed, u+1, v+1, a, b)
+				}
+			}
+		}
+		checkPaths(t, &graph, fw)
+		checkPaths(t, &graph, johnson)
+	}
+}
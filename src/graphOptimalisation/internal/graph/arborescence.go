@@ -0,0 +1,248 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrUnreachable = errors.New("vertices unreachable from the root")
+
+// Arborescence is a directed spanning tree with every edge pointing away
+// from Root.
+type Arborescence struct {
+	Root int `json:"root"`
+	// Parent[v-1] is the vertex whose edge enters v, or 0 for the root.
+	Parent []int `json:"parent"`
+	// Edges are (Parent[v-1], v) for every vertex but the root.
+	Edges  [][2]int `json:"edges"`
+	Weight float64  `json:"weight"`
+}
+
+// MinimumArborescence returns a minimum-weight spanning arborescence rooted
+// at root (Chu–Liu/Edmonds in Tarjan's O(m log n) form). In an undirected
+// graph every edge can be used in both directions. Weights may be negative.
+// If some vertex cannot be reached from root the error wraps ErrUnreachable
+// and lists them. If ctx is cancelled it returns nil and ctx.Err(). Progress
+// goes to progress, which may be nil.
+func (g *Graph) MinimumArborescence(ctx context.Context, root int, progress ProgressFunc) (*Arborescence, error) {
+	if err := g.CheckVertex(root); err != nil {
+		return nil, fmt.Errorf("MinimumArborescence: %w", err)
+	}
+	n := len(g.AdjMatrix)
+	adj := g.arcs()
+	if unreachable := unreachableFrom(adj, root-1); len(unreachable) > 0 {
+		return nil, fmt.Errorf("MinimumArborescence: %w %d: %v", ErrUnreachable, root, unreachable)
+	}
+	rep := newReporter(ctx, progress, "arborescence")
+
+	// Kopiec krawędzi wchodzących do każdego wierzchołka (pomijamy pętle i krawędzie do korzenia)
+	heaps := make([]*skewNode, n)
+	for u := range adj {
+		for _, a := range adj[u] {
+			if u != a.to && a.to != root-1 {
+				heaps[a.to] = mergeSkew(heaps[a.to], &skewNode{edge: inEdge{u, a.to, a.weight}})
+			}
+		}
+	}
+
+	type contraction struct {
+		vertex, time int
+		cycle        []inEdge
+	}
+	uf := newRollbackUnionFind(n)
+	seen := make([]int, n)
+	for i := range seen {
+		seen[i] = -1
+	}
+	seen[root-1] = root - 1
+	path := make([]int, n)
+	queue := make([]inEdge, n)
+	in := make([]inEdge, n)
+	var contractions []contraction
+
+	for s := 0; s < n; s++ {
+		if s%checkEvery == 0 {
+			if err := rep.step("Chu-Liu/Edmonds", s, n); err != nil {
+				return nil, err
+			}
+		}
+		// Idziemy wstecz najtańszymi krawędziami, aż trafimy na odwiedzony wierzchołek
+		u, qi := s, 0
+		for seen[u] < 0 {
+			e := heaps[u].top()
+			heaps[u].delta -= e.weight // dalsze krawędzie kosztują tyle, o ile przebijają wybraną
+			heaps[u] = heaps[u].pop()
+			queue[qi], path[qi] = e, u
+			qi++
+			seen[u] = s
+			u = uf.find(e.from)
+			if seen[u] != s {
+				continue
+			}
+			// Cykl: ściągamy go do jednego wierzchołka ze złączonym kopcem
+			var merged *skewNode
+			end, time := qi, uf.time()
+			for {
+				qi--
+				w := path[qi]
+				merged = mergeSkew(merged, heaps[w])
+				if !uf.join(u, w) {
+					break
+				}
+			}
+			u = uf.find(u)
+			heaps[u], seen[u] = merged, -1
+			contractions = append(contractions, contraction{u, time, append([]inEdge(nil), queue[qi:end]...)})
+		}
+		for _, e := range queue[:qi] {
+			in[uf.find(e.to)] = e
+		}
+	}
+
+	// Rozwijamy cykle od ostatniego: krawędź wchodząca do cyklu wypiera krawędź cyklu
+	for i := len(contractions) - 1; i >= 0; i-- {
+		c := contractions[i]
+		uf.rollback(c.time)
+		entering := in[c.vertex]
+		for _, e := range c.cycle {
+			in[uf.find(e.to)] = e
+		}
+		in[uf.find(entering.to)] = entering
+	}
+
+	arb := &Arborescence{Root: root, Parent: make([]int, n)}
+	for v := range in {
+		if v == root-1 {
+			continue
+		}
+		arb.Parent[v] = in[v].from + 1
+		arb.Edges = append(arb.Edges, [2]int{in[v].from + 1, v + 1})
+		arb.Weight += g.edgeWeight(in[v].from+1, v+1)
+	}
+	return arb, nil
+}
+
+// unreachableFrom lists (1-based) the vertices that no path from source
+// reaches.
+func unreachableFrom(adj [][]arc, source int) []int {
+	visited := make([]bool, len(adj))
+	visited[source] = true
+	stack := []int{source}
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, a := range adj[u] {
+			if !visited[a.to] {
+				visited[a.to] = true
+				stack = append(stack, a.to)
+			}
+		}
+	}
+	var out []int
+	for v, ok := range visited {
+		if !ok {
+			out = append(out, v+1)
+		}
+	}
+	return out
+}
+
+// inEdge is an edge (0-based endpoints) in the heap of edges entering a
+// vertex.
+type inEdge struct {
+	from, to int
+	weight   float64
+}
+
+// skewNode is a node of a skew heap whose whole subtree has delta still to
+// be added to its weights, so a heap can be shifted in O(1).
+type skewNode struct {
+	edge        inEdge
+	delta       float64
+	left, right *skewNode
+}
+
+func (h *skewNode) push() {
+	h.edge.weight += h.delta
+	if h.left != nil {
+		h.left.delta += h.delta
+	}
+	if h.right != nil {
+		h.right.delta += h.delta
+	}
+	h.delta = 0
+}
+
+func (h *skewNode) top() inEdge {
+	h.push()
+	return h.edge
+}
+
+func (h *skewNode) pop() *skewNode {
+	h.push()
+	return mergeSkew(h.left, h.right)
+}
+
+func mergeSkew(a, b *skewNode) *skewNode {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	a.push()
+	b.push()
+	if a.edge.weight > b.edge.weight {
+		a, b = b, a
+	}
+	a.left, a.right = mergeSkew(b, a.right), a.left
+	return a
+}
+
+// rollbackUnionFind is a union-find without path compression whose unions
+// can be undone in reverse order.
+type rollbackUnionFind struct {
+	parent  []int // ujemny rozmiar dla korzenia
+	history [][2]int
+}
+
+func newRollbackUnionFind(n int) *rollbackUnionFind {
+	uf := &rollbackUnionFind{parent: make([]int, n)}
+	for i := range uf.parent {
+		uf.parent[i] = -1
+	}
+	return uf
+}
+
+func (uf *rollbackUnionFind) find(x int) int {
+	for uf.parent[x] >= 0 {
+		x = uf.parent[x]
+	}
+	return x
+}
+
+func (uf *rollbackUnionFind) time() int { return len(uf.history) }
+
+// join merges the sets of a and b and reports whether they were different.
+func (uf *rollbackUnionFind) join(a, b int) bool {
+	a, b = uf.find(a), uf.find(b)
+	if a == b {
+		return false
+	}
+	if uf.parent[a] > uf.parent[b] {
+		a, b = b, a
+	}
+	uf.history = append(uf.history, [2]int{a, uf.parent[a]}, [2]int{b, uf.parent[b]})
+	uf.parent[a] += uf.parent[b]
+	uf.parent[b] = a
+	return true
+}
+
+func (uf *rollbackUnionFind) rollback(t int) {
+	for len(uf.history) > t {
+		last := uf.history[len(uf.history)-1]
+		uf.parent[last[0]] = last[1]
+		uf.history = uf.history[:len(uf.history)-1]
+	}
+}
@@ -0,0 +1,186 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Simikao/graphOptimalisation/internal/generate"
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+// checkArborescence verifies that a is a spanning arborescence of graph
+// rooted at a.Root and that its weight adds up.
+func checkArborescence(t *testing.T, graph *g.Graph, a *g.Arborescence) {
+	t.Helper()
+	n := len(graph.AdjMatrix)
+	if len(a.Edges) != n-1 {
+		t.Errorf("got %d edges, want %d", len(a.Edges), n-1)
+	}
+	weight := 0.0
+	for v := 1; v <= n; v++ {
+		p := a.Parent[v-1]
+		if v == a.Root {
+			if p != 0 {
+				t.Errorf("root %d has parent %d", v, p)
+			}
+			continue
+		}
+		if !graph.HasEdge(p, v) {
+			t.Errorf("parent edge %d -> %d is not in the graph", p, v)
+			continue
+		}
+		weight += graph.WeightMatrix[p-1][v-1]
+		// Od każdego wierzchołka po rodzicach dochodzimy do korzenia
+		u, steps := v, 0
+		for u != a.Root && u != 0 && steps <= n {
+			u, steps = a.Parent[u-1], steps+1
+		}
+		if u != a.Root {
+			t.Errorf("vertex %d does not lead to the root", v)
+		}
+	}
+	if math.Abs(weight-a.Weight) > 1e-9 {
+		t.Errorf("edges weigh %g, reported weight is %g", weight, a.Weight)
+	}
+}
+
+// bruteArborescence tries every choice of parents and returns the least
+// weight of a spanning arborescence rooted at root, or +Inf.
+func bruteArborescence(graph *g.Graph, root int) float64 {
+	n := len(graph.AdjMatrix)
+	parent := make([]int, n+1)
+	best := math.Inf(1)
+	var choose func(v int, weight float64)
+	choose = func(v int, weight float64) {
+		if v > n {
+			for u := 1; u <= n; u++ {
+				w, steps := u, 0
+				for w != root && steps <= n {
+					w, steps = parent[w], steps+1
+				}
+				if w != root {
+					return
+				}
+			}
+			best = min(best, weight)
+			return
+		}
+		if v == root {
+			choose(v+1, weight)
+			return
+		}
+		for p := 1; p <= n; p++ {
+			if p != v && graph.HasEdge(p, v) {
+				parent[v] = p
+				choose(v+1, weight+graph.WeightMatrix[p-1][v-1])
+			}
+		}
+	}
+	choose(1, 0)
+	return best
+}
+
+func TestMinimumArborescence(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		directed bool
+		edges    [][3]float64
+		root     int
+		weight   float64
+	}{
+		{"single vertex", 1, true, nil, 1, 0},
+		{
+			// Tanie krawędzie 2 -> 3 -> 4 -> 2 tworzą cykl, który trzeba rozerwać
+			"cycle to contract", 4, true,
+			[][3]float64{{1, 2, 10}, {1, 3, 12}, {2, 3, 1}, {3, 4, 1}, {4, 2, 1}, {1, 4, 20}},
+			1, 12,
+		},
+		{"negative weights", 3, true, [][3]float64{{1, 2, -1}, {2, 3, -4}, {1, 3, 2}, {3, 2, -2}}, 1, -5},
+		{"undirected", 4, false, [][3]float64{{1, 2, 3}, {2, 3, 1}, {3, 4, 2}, {1, 4, 1}}, 2, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := g.NewGraph(tt.n, tt.directed, true)
+			for _, e := range tt.edges {
+				graph.AddEdge(int(e[0]), int(e[1]), e[2])
+			}
+			a, err := graph.MinimumArborescence(context.Background(), tt.root, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.Weight != tt.weight {
+				t.Errorf("weight %g, want %g", a.Weight, tt.weight)
+			}
+			checkArborescence(t, &graph, a)
+		})
+	}
+}
+
+func TestMinimumArborescenceErrors(t *testing.T) {
+	ctx := context.Background()
+	graph := g.NewGraph(3, true, true)
+	graph.AddEdge(1, 2, 1).AddEdge(3, 2, 1)
+	if _, err := graph.MinimumArborescence(ctx, 1, nil); !errors.Is(err, g.ErrUnreachable) {
+		t.Errorf("got %v, want %v", err, g.ErrUnreachable)
+	}
+	empty := g.NewGraph(0, true, false)
+	if _, err := empty.MinimumArborescence(ctx, 1, nil); !errors.Is(err, g.ErrVertexOutOfRange) {
+		t.Errorf("empty graph: got %v, want %v", err, g.ErrVertexOutOfRange)
+	}
+}
+
+func TestMinimumArborescenceMatchesBruteForce(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 30; seed++ {
+		gen := generate.New(seed)
+		gen.Weighted, gen.Directed = true, true
+		gen.MinWeight = -3
+		graph, err := gen.ErdosRenyi(6, 0.5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := bruteArborescence(&graph, 1)
+		a, err := graph.MinimumArborescence(ctx, 1, nil)
+		if math.IsInf(want, 1) {
+			if !errors.Is(err, g.ErrUnreachable) {
+				t.Errorf("seed %d: got %v, want %v", seed, err, g.ErrUnreachable)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if a.Weight != want {
+			t.Errorf("seed %d: weight %g, want %g", seed, a.Weight, want)
+		}
+		checkArborescence(t, &graph, a)
+	}
+}
+
+func TestMinimumArborescenceUndirectedMatchesMST(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 10; seed++ {
+		gen := generate.New(seed)
+		gen.Weighted = true
+		graph, err := gen.ErdosRenyi(10, 0.4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tree, err := graph.MinimumSpanningTree(ctx, g.MSTOptions{}, nil)
+		if errors.Is(err, g.ErrDisconnected) {
+			continue
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		a, err := graph.MinimumArborescence(ctx, int(1+seed%10), nil)
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if a.Weight != tree.Weight {
+			t.Errorf("seed %d: arborescence weighs %g, spanning tree %g", seed, a.Weight, tree.Weight)
+		}
+	}
+}
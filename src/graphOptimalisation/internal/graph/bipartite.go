@@ -0,0 +1,358 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var ErrNotBipartite = errors.New("graph is not bipartite")
+
+// OddCycleError is returned when a graph is not bipartite; the odd cycle
+// proves it.
+type OddCycleError struct {
+	// Cycle lists the vertices of the cycle, the first repeated at the end.
+	Cycle []int
+}
+
+func (e *OddCycleError) Error() string {
+	parts := make([]string, len(e.Cycle))
+	for i, v := range e.Cycle {
+		parts[i] = fmt.Sprint(v)
+	}
+	return fmt.Sprintf("%v: odd cycle %s", ErrNotBipartite, strings.Join(parts, " - "))
+}
+
+func (e *OddCycleError) Unwrap() error { return ErrNotBipartite }
+
+// undirectedAdjacency lists the neighbours of every vertex (0-based),
+// ignoring edge directions.
+func (g *Graph) undirectedAdjacency() [][]int {
+	adj := make([][]int, len(g.AdjMatrix))
+	for _, e := range g.Edges {
+		u, v := e[0]-1, e[1]-1
+		adj[u] = append(adj[u], v)
+		if u != v {
+			adj[v] = append(adj[v], u)
+		}
+	}
+	return adj
+}
+
+// Bipartition splits the vertices into two sides with every edge between
+// them; edge directions are ignored. Every component puts its smallest
+// vertex on the left. If the graph is not bipartite the error is an
+// *OddCycleError.
+func (g *Graph) Bipartition() (left, right []int, err error) {
+	adj := g.undirectedAdjacency()
+	n := len(adj)
+	side := make([]int, n) // 0: nieodwiedzony, 1: lewa, 2: prawa
+	parent := make([]int, n)
+	depth := make([]int, n)
+	for start := range adj {
+		if side[start] != 0 {
+			continue
+		}
+		side[start], parent[start] = 1, -1
+		queue := []int{start}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, v := range adj[u] {
+				if side[v] == 0 {
+					side[v], parent[v], depth[v] = 3-side[u], u, depth[u]+1
+					queue = append(queue, v)
+				} else if side[v] == side[u] {
+					return nil, nil, &OddCycleError{Cycle: oddCycle(parent, depth, u, v)}
+				}
+			}
+		}
+	}
+	for v, s := range side {
+		if s == 1 {
+			left = append(left, v+1)
+		} else {
+			right = append(right, v+1)
+		}
+	}
+	return left, right, nil
+}
+
+// oddCycle closes the BFS tree paths from u and v to their common ancestor
+// with the edge (u, v).
+func oddCycle(parent, depth []int, u, v int) []int {
+	var fromU, fromV []int
+	for depth[u] > depth[v] {
+		fromU, u = append(fromU, u+1), parent[u]
+	}
+	for depth[v] > depth[u] {
+		fromV, v = append(fromV, v+1), parent[v]
+	}
+	for u != v {
+		fromU, u = append(fromU, u+1), parent[u]
+		fromV, v = append(fromV, v+1), parent[v]
+	}
+	cycle := append(fromU, u+1)
+	for i := len(fromV) - 1; i >= 0; i-- {
+		cycle = append(cycle, fromV[i])
+	}
+	return append(cycle, cycle[0])
+}
+
+// BipartiteMatching is a maximum matching of a bipartite graph.
+type BipartiteMatching struct {
+	Left  []int `json:"left"`
+	Right []int `json:"right"`
+	// Edges of the matching are the pairs (left, right).
+	Matching
+}
+
+// HopcroftKarp finds a maximum cardinality matching of a bipartite graph in
+// O(m√n): each phase finds a maximal set of shortest augmenting paths by
+// BFS from all free left vertices and DFS along the layers. Edge directions
+// are ignored. If the graph is not bipartite the error is an
+// *OddCycleError. If ctx is cancelled it returns the matching found so far
+// and ctx.Err(). Progress goes to progress, which may be nil.
+func (g *Graph) HopcroftKarp(ctx context.Context, progress ProgressFunc) (*BipartiteMatching, error) {
+	left, right, err := g.Bipartition()
+	if err != nil {
+		return nil, fmt.Errorf("HopcroftKarp: %w", err)
+	}
+	rep := newReporter(ctx, progress, "hopcroft-karp")
+	adj := g.undirectedAdjacency()
+	n := len(adj)
+	mate := make([]int, n) // -1 dla wolnych, indeksy od 0
+	for i := range mate {
+		mate[i] = -1
+	}
+	dist := make([]int, n)
+
+	// Warstwy naprzemienne od wolnych wierzchołków lewej strony
+	bfs := func() bool {
+		queue := make([]int, 0, len(left))
+		for _, u := range left {
+			dist[u-1] = -1
+			if mate[u-1] < 0 {
+				dist[u-1] = 0
+				queue = append(queue, u-1)
+			}
+		}
+		found := false
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, v := range adj[u] {
+				w := mate[v]
+				if w < 0 {
+					found = true
+				} else if dist[w] < 0 {
+					dist[w] = dist[u] + 1
+					queue = append(queue, w)
+				}
+			}
+		}
+		return found
+	}
+	var dfs func(u int) bool
+	dfs = func(u int) bool {
+		for _, v := range adj[u] {
+			w := mate[v]
+			if w < 0 || (dist[w] == dist[u]+1 && dfs(w)) {
+				mate[u], mate[v] = v, u
+				return true
+			}
+		}
+		dist[u] = -1 // ślepy zaułek w tej fazie
+		return false
+	}
+
+	size := 0
+	for bfs() {
+		if err = rep.step("augmenting phases", size, min(len(left), len(right))); err != nil {
+			break
+		}
+		for _, u := range left {
+			if mate[u-1] < 0 && dfs(u-1) {
+				size++
+			}
+		}
+	}
+
+	m := &BipartiteMatching{Left: left, Right: right, Matching: Matching{Mate: make([]int, n)}}
+	for v, w := range mate {
+		m.Mate[v] = w + 1
+	}
+	for _, u := range left {
+		if w := mate[u-1]; w >= 0 {
+			m.Edges = append(m.Edges, [2]int{u, w + 1})
+		}
+	}
+	return m, err
+}
+
+// KonigVertexCover returns a minimum vertex cover of a bipartite graph built
+// from a maximum matching by König's theorem: with Z the vertices reachable
+// from free left vertices by alternating paths, the cover is the left
+// vertices outside Z and the right vertices in Z. Its size equals the size
+// of the matching. If the graph is not bipartite the error is an
+// *OddCycleError. If ctx is cancelled it returns nil and ctx.Err(). Progress
+// of the matching goes to progress, which may be nil.
+func (g *Graph) KonigVertexCover(ctx context.Context, progress ProgressFunc) ([]int, error) {
+	m, err := g.HopcroftKarp(ctx, progress)
+	if err != nil {
+		if m == nil {
+			err = fmt.Errorf("KonigVertexCover: %w", errors.Unwrap(err))
+		}
+		return nil, err
+	}
+	adj := g.undirectedAdjacency()
+	n := len(adj)
+	isLeft := make([]bool, n)
+	reached := make([]bool, n)
+	var stack []int
+	for _, u := range m.Left {
+		isLeft[u-1] = true
+		if m.Mate[u-1] == 0 {
+			reached[u-1] = true
+			stack = append(stack, u-1)
+		}
+	}
+	// Z lewej dowolną krawędzią, z prawej tylko krawędzią skojarzenia
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, v := range adj[u] {
+			if reached[v] || m.Mate[u] == v+1 {
+				continue
+			}
+			reached[v] = true
+			if w := m.Mate[v] - 1; w >= 0 && !reached[w] {
+				reached[w] = true
+				stack = append(stack, w)
+			}
+		}
+	}
+	var cover []int
+	for v := 0; v < n; v++ {
+		if isLeft[v] != reached[v] {
+			cover = append(cover, v+1)
+		}
+	}
+	return cover, nil
+}
+
+// Assignment is a solution of the assignment problem.
+type Assignment struct {
+	// Column[i] is the column assigned to row i (0-based), or -1 if there
+	// are more rows than columns and row i is left out.
+	Column []int   `json:"column"`
+	Cost   float64 `json:"cost"`
+	// RowPotential[i] + ColumnPotential[j] <= cost[i][j], with equality for
+	// the assigned pairs.
+	RowPotential    []float64 `json:"row_potential"`
+	ColumnPotential []float64 `json:"column_potential"`
+}
+
+// SolveAssignment assigns rows to distinct columns of a cost matrix at the
+// least total cost with the Hungarian algorithm (O(n²m) for n <= m). If there
+// are more rows than columns, every column gets a row instead. An entry of
+// +Inf forbids the pair; if no complete assignment avoids them the error
+// wraps ErrInfeasible. If ctx is cancelled it returns nil and ctx.Err().
+// Progress goes to progress, which may be nil.
+func SolveAssignment(ctx context.Context, cost [][]float64, progress ProgressFunc) (*Assignment, error) {
+	rows := len(cost)
+	cols := 0
+	if rows > 0 {
+		cols = len(cost[0])
+	}
+	for i, row := range cost {
+		if len(row) != cols {
+			return nil, fmt.Errorf("SolveAssignment: row %d has %d entries, row 1 has %d", i+1, len(row), cols)
+		}
+	}
+	if rows > cols {
+		// Więcej wierszy niż kolumn: rozwiązujemy transpozycję
+		transposed := make([][]float64, cols)
+		for j := range transposed {
+			transposed[j] = make([]float64, rows)
+			for i := range cost {
+				transposed[j][i] = cost[i][j]
+			}
+		}
+		t, err := SolveAssignment(ctx, transposed, progress)
+		if err != nil {
+			return nil, err
+		}
+		a := &Assignment{Column: make([]int, rows), Cost: t.Cost, RowPotential: t.ColumnPotential, ColumnPotential: t.RowPotential}
+		for i := range a.Column {
+			a.Column[i] = -1
+		}
+		for j, i := range t.Column {
+			a.Column[i] = j
+		}
+		return a, nil
+	}
+
+	rep := newReporter(ctx, progress, "hungarian")
+	// Wersja z potencjałami: u dla wierszy, v dla kolumn, kolumna 0 pomocnicza
+	u := make([]float64, rows+1)
+	v := make([]float64, cols+1)
+	owner := make([]int, cols+1) // wiersz (od 1) przypisany do kolumny, 0 jeśli brak
+	way := make([]int, cols+1)
+	minv := make([]float64, cols+1)
+	used := make([]bool, cols+1)
+	for i := 1; i <= rows; i++ {
+		if err := rep.step("assigning rows", i-1, rows); err != nil {
+			return nil, err
+		}
+		owner[0] = i
+		j0 := 0
+		for j := range minv {
+			minv[j], used[j] = math.Inf(1), false
+		}
+		for owner[j0] != 0 {
+			used[j0] = true
+			i0, delta, j1 := owner[j0], math.Inf(1), -1
+			for j := 1; j <= cols; j++ {
+				if used[j] {
+					continue
+				}
+				if cur := cost[i0-1][j-1] - u[i0] - v[j]; cur < minv[j] {
+					minv[j], way[j] = cur, j0
+				}
+				if minv[j] < delta {
+					delta, j1 = minv[j], j
+				}
+			}
+			if j1 < 0 {
+				return nil, fmt.Errorf("SolveAssignment: %w: row %d cannot be assigned", ErrInfeasible, i)
+			}
+			for j := 0; j <= cols; j++ {
+				if used[j] {
+					u[owner[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+		}
+		// Przesuwamy przypisania wzdłuż znalezionej ścieżki naprzemiennej
+		for j0 != 0 {
+			j1 := way[j0]
+			owner[j0] = owner[j1]
+			j0 = j1
+		}
+	}
+
+	a := &Assignment{Column: make([]int, rows), RowPotential: u[1:], ColumnPotential: v[1:]}
+	for j := 1; j <= cols; j++ {
+		if owner[j] != 0 {
+			a.Column[owner[j]-1] = j - 1
+			a.Cost += cost[owner[j]-1][j-1]
+		}
+	}
+	return a, nil
+}
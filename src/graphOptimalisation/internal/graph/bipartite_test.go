@@ -0,0 +1,275 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Simikao/graphOptimalisation/internal/generate"
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+	"github.com/Simikao/graphOptimalisation/internal/validate"
+)
+
+func undirected(n int, edges [][2]int) g.Graph {
+	graph := g.NewGraph(n, false, false)
+	for _, e := range edges {
+		graph.AddEdge(e[0], e[1])
+	}
+	return graph
+}
+
+func TestBipartition(t *testing.T) {
+	tests := []struct {
+		name        string
+		graph       g.Graph
+		left, right []int
+	}{
+		{"empty", undirected(0, nil), nil, nil},
+		{"single vertex", undirected(1, nil), []int{1}, nil},
+		{"even cycle", undirected(4, [][2]int{{1, 2}, {2, 3}, {3, 4}, {4, 1}}), []int{1, 3}, []int{2, 4}},
+		{"two components", undirected(5, [][2]int{{2, 1}, {4, 3}, {5, 4}}), []int{1, 3, 5}, []int{2, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right, err := tt.graph.Bipartition()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalInts(left, tt.left) || !equalInts(right, tt.right) {
+				t.Errorf("got %v | %v, want %v | %v", left, right, tt.left, tt.right)
+			}
+		})
+	}
+}
+
+func TestBipartitionOddCycle(t *testing.T) {
+	// Trójkąt 3-4-5 wisi na ścieżce 1-2-3
+	graph := undirected(5, [][2]int{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 3}})
+	_, _, err := graph.Bipartition()
+	var cerr *g.OddCycleError
+	if !errors.As(err, &cerr) || !errors.Is(err, g.ErrNotBipartite) {
+		t.Fatalf("got %v, want an *OddCycleError", err)
+	}
+	c := cerr.Cycle
+	if len(c)%2 != 0 || c[0] != c[len(c)-1] {
+		t.Fatalf("cycle %v is not a closed odd cycle", c)
+	}
+	for i := 1; i < len(c); i++ {
+		if !graph.HasEdge(c[i-1], c[i]) {
+			t.Errorf("cycle %v uses the non-edge %d-%d", c, c[i-1], c[i])
+		}
+	}
+
+	if _, err := graph.HopcroftKarp(context.Background(), nil); !errors.As(err, &cerr) {
+		t.Errorf("HopcroftKarp: got %v, want an *OddCycleError", err)
+	}
+	if _, err := graph.KonigVertexCover(context.Background(), nil); !errors.As(err, &cerr) {
+		t.Errorf("KonigVertexCover: got %v, want an *OddCycleError", err)
+	}
+}
+
+func TestHopcroftKarp(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph g.Graph
+		size  int
+	}{
+		{"empty", undirected(0, nil), 0},
+		{"single vertex", undirected(1, nil), 0},
+		{"perfect", undirected(6, [][2]int{{1, 4}, {1, 5}, {2, 4}, {3, 5}, {3, 6}}), 3},
+		// Wierzchołki 1 i 2 mają wspólnego jedynego sąsiada
+		{"deficient", undirected(5, [][2]int{{1, 4}, {2, 4}, {3, 4}, {3, 5}}), 2},
+		{"path needing augmentation", undirected(6, [][2]int{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 6}}), 3},
+		{"disconnected", undirected(7, [][2]int{{1, 2}, {3, 4}, {4, 5}}), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := tt.graph.HopcroftKarp(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(m.Edges) != tt.size {
+				t.Errorf("matching %v has %d edges, want %d", m.Edges, len(m.Edges), tt.size)
+			}
+			if err := validate.VerifyMatching(&tt.graph, m.Edges); err != nil {
+				t.Error(err)
+			}
+			cover, err := tt.graph.KonigVertexCover(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cover) != tt.size {
+				t.Errorf("cover %v has %d vertices, want %d", cover, len(cover), tt.size)
+			}
+			if err := validate.VerifyVertexCover(&tt.graph, cover); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestKonigCoverMatchesHopcroftKarp(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 30; seed++ {
+		gen := generate.New(seed)
+		graph, err := gen.Bipartite(int(1+seed%6), int(1+seed%5), 0.1+float64(seed%5)*0.15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := graph.HopcroftKarp(ctx, nil)
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		cover, err := graph.KonigVertexCover(ctx, nil)
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		// Pokrycie nie może być mniejsze od skojarzenia, więc równość dowodzi optymalności obu
+		if len(cover) != len(m.Edges) {
+			t.Errorf("seed %d: König cover has %d vertices, matching %d edges", seed, len(cover), len(m.Edges))
+		}
+		if err := validate.VerifyMatching(&graph, m.Edges); err != nil {
+			t.Errorf("seed %d: %v", seed, err)
+		}
+		if err := validate.VerifyVertexCover(&graph, cover); err != nil {
+			t.Errorf("seed %d: %v", seed, err)
+		}
+		for _, e := range m.Edges {
+			if m.Mate[e[0]-1] != e[1] || m.Mate[e[1]-1] != e[0] {
+				t.Errorf("seed %d: Mate disagrees with the edge %v", seed, e)
+			}
+		}
+	}
+}
+
+// bruteAssignment tries every assignment of rows to distinct columns (rows
+// <= columns) and returns the least cost.
+func bruteAssignment(cost [][]float64) float64 {
+	best := math.Inf(1)
+	used := make([]bool, len(cost[0]))
+	var assign func(i int, total float64)
+	assign = func(i int, total float64) {
+		if i == len(cost) {
+			best = min(best, total)
+			return
+		}
+		for j := range used {
+			if !used[j] {
+				used[j] = true
+				assign(i+1, total+cost[i][j])
+				used[j] = false
+			}
+		}
+	}
+	assign(0, 0)
+	return best
+}
+
+// checkAssignment verifies that a assigns distinct columns, adds up to its
+// cost and that the potentials prove it optimal.
+func checkAssignment(t *testing.T, cost [][]float64, a *g.Assignment) {
+	t.Helper()
+	const eps = 1e-9
+	used := map[int]bool{}
+	total := 0.0
+	assigned := 0
+	for i, j := range a.Column {
+		if j < 0 {
+			continue
+		}
+		if used[j] {
+			t.Errorf("column %d assigned twice", j)
+		}
+		used[j] = true
+		assigned++
+		total += cost[i][j]
+		if d := cost[i][j] - a.RowPotential[i] - a.ColumnPotential[j]; math.Abs(d) > eps {
+			t.Errorf("assigned pair (%d, %d) has reduced cost %g", i, j, d)
+		}
+	}
+	if assigned != min(len(cost), len(cost[0])) {
+		t.Errorf("%d pairs assigned, want %d", assigned, min(len(cost), len(cost[0])))
+	}
+	if math.Abs(total-a.Cost) > eps {
+		t.Errorf("assignment costs %g, reported cost is %g", total, a.Cost)
+	}
+	for i := range cost {
+		for j := range cost[i] {
+			if d := cost[i][j] - a.RowPotential[i] - a.ColumnPotential[j]; d < -eps {
+				t.Errorf("pair (%d, %d) has negative reduced cost %g", i, j, d)
+			}
+		}
+	}
+}
+
+func TestSolveAssignment(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name   string
+		cost   [][]float64
+		column []int
+		total  float64
+	}{
+		{"one by one", [][]float64{{7}}, []int{0}, 7},
+		{"square", [][]float64{{4, 1, 3}, {2, 0, 5}, {3, 2, 2}}, []int{1, 0, 2}, 5},
+		{"more columns", [][]float64{{9, 2, 7}, {3, 6, 1}}, []int{1, 2}, 3},
+		{"more rows", [][]float64{{9, 3}, {2, 6}, {7, 1}}, []int{-1, 0, 1}, 3},
+		{"forbidden pairs", [][]float64{{1, inf}, {1, 5}}, []int{0, 1}, 6},
+		{"negative costs", [][]float64{{-1, -5}, {-3, -2}}, []int{1, 0}, -8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := g.SolveAssignment(context.Background(), tt.cost, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.Cost != tt.total || !equalInts(a.Column, tt.column) {
+				t.Errorf("got %v costing %g, want %v costing %g", a.Column, a.Cost, tt.column, tt.total)
+			}
+			checkAssignment(t, tt.cost, a)
+		})
+	}
+}
+
+func TestSolveAssignmentErrors(t *testing.T) {
+	ctx := context.Background()
+	inf := math.Inf(1)
+	if _, err := g.SolveAssignment(ctx, [][]float64{{1, inf}, {2, inf}}, nil); !errors.Is(err, g.ErrInfeasible) {
+		t.Errorf("got %v, want %v", err, g.ErrInfeasible)
+	}
+	if _, err := g.SolveAssignment(ctx, [][]float64{{1, 2}, {3}}, nil); err == nil {
+		t.Error("ragged matrix accepted")
+	}
+	a, err := g.SolveAssignment(ctx, nil, nil)
+	if err != nil || a.Cost != 0 || len(a.Column) != 0 {
+		t.Errorf("empty matrix: got %v, %v", a, err)
+	}
+}
+
+func TestSolveAssignmentMatchesBruteForce(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 20; seed++ {
+		gen := generate.New(seed)
+		gen.Weighted = true
+		gen.MinWeight, gen.MaxWeight = -5, 20
+		rows, cols := int(1+seed%5), 5
+		// Macierz kosztów to wagi pełnego grafu dwudzielnego
+		graph, err := gen.Bipartite(rows, cols, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cost := make([][]float64, rows)
+		for i := range cost {
+			cost[i] = graph.WeightMatrix[i][rows : rows+cols]
+		}
+		a, err := g.SolveAssignment(ctx, cost, nil)
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if want := bruteAssignment(cost); a.Cost != want {
+			t.Errorf("seed %d: cost %g, want %g", seed, a.Cost, want)
+		}
+		checkAssignment(t, cost, a)
+	}
+}
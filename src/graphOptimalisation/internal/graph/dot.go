@@ -0,0 +1,149 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadDOT reads the subset of Graphviz DOT produced by WriteDOT: numeric
+// vertices, "u -> v" or "u -- v" edges with an optional label="w" weight and
+// "v;" statements declaring vertices without edges.
+func ReadDOT(r io.Reader, opts ReadOptions) (Graph, error) {
+	type rawEdge struct {
+		u, v   int
+		weight float64
+	}
+
+	var (
+		edges     []rawEdge
+		maxVertex int
+		directed  bool
+		weighted  bool
+		lineNo    int
+	)
+
+	scanner := newLineScanner(r)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+
+		// Checking if the graph is directed
+		if strings.HasPrefix(line, "digraph") {
+			directed = true
+		}
+
+		if !strings.Contains(line, "->") && !strings.Contains(line, "--") {
+			// Deklaracja wierzchołka, np. "7;" dla wierzchołka bez krawędzi
+			node, _, _ := strings.Cut(line, ";")
+			node, _, _ = strings.Cut(node, "[")
+			if id, err := strconv.Atoi(strings.TrimSpace(node)); err == nil {
+				if id < 1 {
+					return Graph{}, parseErr(lineNo, ErrInvalidVertex, "%d is not a positive integer", id)
+				}
+				maxVertex = max(maxVertex, id)
+			}
+			continue
+		}
+		parts := strings.FieldsFunc(line, func(r rune) bool {
+			return r == '-' || r == '>' || r == ';' || r == '[' || r == ']'
+		})
+		if len(parts) < 2 {
+			return Graph{}, parseErr(lineNo, ErrMissingField, "edge needs two endpoints")
+		}
+
+		e := rawEdge{weight: 1}
+		for i, dst := range []*int{&e.u, &e.v} {
+			id, err := strconv.Atoi(strings.TrimSpace(parts[i]))
+			if err != nil || id < 1 {
+				return Graph{}, parseErr(lineNo, ErrInvalidVertex, "%q is not a positive integer", strings.TrimSpace(parts[i]))
+			}
+			*dst = id
+		}
+
+		if strings.Contains(line, "label=") {
+			weighted = true
+			start := strings.Index(line, "\"") + 1
+			end := strings.LastIndex(line, "\"")
+			if start <= 0 || end < start {
+				return Graph{}, parseErr(lineNo, ErrInvalidWeight, "label must be quoted")
+			}
+			weight, err := strconv.ParseFloat(line[start:end], 64)
+			if err != nil {
+				return Graph{}, parseErr(lineNo, ErrInvalidWeight, "%q is not a number", line[start:end])
+			}
+			e.weight = weight
+		}
+
+		maxVertex = max(maxVertex, e.u, e.v)
+		edges = append(edges, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return Graph{}, err
+	}
+
+	if err := checkVertexLimit(opts.MaxVertices, maxVertex, 0); err != nil {
+		return Graph{}, err
+	}
+	graph := NewGraph(maxVertex, directed, weighted)
+	for _, e := range edges {
+		graph.AddEdge(e.u, e.v, e.weight)
+	}
+	return graph, nil
+}
+
+// LoadDOT opens filename and reads it with ReadDOT.
+func LoadDOT(filename string) (Graph, error) {
+	return loadFile(filename, func(r io.Reader) (Graph, error) {
+		return ReadDOT(r, ReadOptions{})
+	})
+}
+
+// WriteDOT writes g in Graphviz DOT format.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	// Ustaw nagłówek grafu w zależności od typu (skierowany/nieskierowany)
+	var graphType string
+	var edgeConnector string
+	if g.Directed {
+		graphType = "digraph"
+		edgeConnector = "->"
+	} else {
+		graphType = "graph"
+		edgeConnector = "--"
+	}
+
+	fmt.Fprintf(bw, "%s G {\n", graphType)
+
+	// Dodanie krawędzi
+	for i := range g.AdjMatrix {
+		for j := range g.AdjMatrix[i] {
+			if g.AdjMatrix[i][j] == 1 {
+				indexingFixI := i + 1
+				indexingFixJ := j + 1
+				if g.Directed || i <= j {
+					if g.Weighted {
+						fmt.Fprintf(bw, "  %d %s %d [label=\"%s\"];\n", indexingFixI, edgeConnector, indexingFixJ, strconv.FormatFloat(g.WeightMatrix[i][j], 'g', -1, 64))
+					} else {
+						fmt.Fprintf(bw, "  %d %s %d;\n", indexingFixI, edgeConnector, indexingFixJ)
+					}
+				}
+			}
+		}
+	}
+
+	// Wierzchołki bez krawędzi trzeba zadeklarować, inaczej znikną z rysunku
+	// i z grafu wczytanego z powrotem
+	for v := 1; v <= len(g.AdjMatrix); v++ {
+		if g.GetDegree(v) == 0 {
+			fmt.Fprintf(bw, "  %d;\n", v)
+		}
+	}
+
+	// Zakończenie
+	fmt.Fprintf(bw, "}\n")
+	return bw.Flush()
+}
@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOTKeepsWeights(t *testing.T) {
+	weights := []float64{1.0 / 3, 0.125, 1e-5, -2.5, 1234567.891, 0}
+	graph := NewGraph(len(weights)+1, false, true)
+	for i, w := range weights {
+		graph.AddEdge(i+1, i+2, w)
+	}
+	var buf bytes.Buffer
+	if err := graph.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `label="0.3333333333333333"`) {
+		t.Errorf("weight 1/3 written with lost precision:\n%s", buf.String())
+	}
+
+	back, err := ReadDOT(&buf, ReadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, w := range weights {
+		if got, err := back.GetWeight(i+1, i+2); err != nil || got != w {
+			t.Errorf("edge %d-%d: got weight %g (%v), want %g", i+1, i+2, got, err, w)
+		}
+	}
+}
+
+func TestDOTRoundTrip(t *testing.T) {
+	for _, directed := range []bool{false, true} {
+		for _, weighted := range []bool{false, true} {
+			// Wierzchołki 3 i 6 nie mają krawędzi; 6 jest ostatni
+			graph := NewGraph(6, directed, weighted)
+			graph.AddEdge(1, 2, 1.5).AddEdge(2, 4, -2).AddEdge(5, 5, 7).AddEdge(4, 1, 0.25)
+			var buf bytes.Buffer
+			if err := graph.WriteDOT(&buf); err != nil {
+				t.Fatal(err)
+			}
+			back, err := ReadDOT(&buf, ReadOptions{})
+			if err != nil {
+				t.Fatalf("directed=%t weighted=%t: %v", directed, weighted, err)
+			}
+			if back.Directed != directed || back.Weighted != weighted || len(back.AdjMatrix) != 6 || len(back.Edges) != 4 {
+				t.Fatalf("directed=%t weighted=%t: got directed=%t weighted=%t, %d vertices and %d edges",
+					directed, weighted, back.Directed, back.Weighted, len(back.AdjMatrix), len(back.Edges))
+			}
+			for u := 1; u <= 6; u++ {
+				for v := 1; v <= 6; v++ {
+					if back.HasEdge(u, v) != graph.HasEdge(u, v) || back.edgeWeight(u, v) != graph.edgeWeight(u, v) {
+						t.Errorf("directed=%t weighted=%t: edge %d-%d differs after round trip", directed, weighted, u, v)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidVertex      = errors.New("invalid vertex id")
+	ErrInvalidWeight      = errors.New("invalid weight")
+	ErrMissingField       = errors.New("missing field")
+	ErrTooManyFields      = errors.New("too many fields")
+	ErrInvalidHeader      = errors.New("invalid header")
+	ErrVertexOutOfRange   = errors.New("vertex out of range")
+	ErrInconsistentWeight = errors.New("weighted and unweighted edges mixed")
+	ErrHeaderMismatch     = errors.New("content does not match header")
+	ErrDuplicateEdge      = errors.New("duplicate edge")
+)
+
+// ParseError reports a problem found while reading a graph file. Line is
+// 1-based; a zero Line means the error concerns the file as a whole.
+type ParseError struct {
+	Line int
+	Err  error
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%v: %s", e.Err, e.Msg)
+	}
+	return fmt.Sprintf("line %d: %v: %s", e.Line, e.Err, e.Msg)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func parseErr(line int, err error, format string, args ...any) error {
+	return &ParseError{Line: line, Err: err, Msg: fmt.Sprintf(format, args...)}
+}
+
+// EdgeListOptions controls how ReadEdgeList interprets its input. A header
+// line in the file takes precedence over Directed and Weighted.
+type EdgeListOptions struct {
+	// Directed is used when the file has no header declaring it.
+	Directed bool
+	// Weighted forces a weighted (true) or unweighted (false) graph.
+	// When nil, the graph is weighted iff the edge lines carry a third column.
+	Weighted *bool
+	// Delimiter separates fields. Zero detects ',' or '\t' from the first
+	// edge line and falls back to runs of whitespace.
+	Delimiter rune
+	// MaxVertices limits the size of the graph as in ReadOptions; zero means
+	// no limit.
+	MaxVertices int
+}
+
+// edgeListHeader is the optional first non-comment line of an edge list,
+// e.g. "n=5 m=9 directed=false weighted=true".
+type edgeListHeader struct {
+	n, m     int
+	directed *bool
+	weighted *bool
+}
+
+func parseEdgeListHeader(line string, lineNo int) (edgeListHeader, error) {
+	h := edgeListHeader{n: -1, m: -1}
+	for _, field := range strings.Fields(line) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			// Bare flags: "directed", "undirected", "weighted", "unweighted"
+			key, value = field, "true"
+			switch strings.ToLower(field) {
+			case "undirected":
+				key, value = "directed", "false"
+			case "unweighted":
+				key, value = "weighted", "false"
+			}
+		}
+		key = strings.ToLower(key)
+		switch key {
+		case "n", "m":
+			num, err := strconv.Atoi(value)
+			if err != nil || num < 0 {
+				return h, parseErr(lineNo, ErrInvalidHeader, "%s must be a non-negative integer, got %q", key, value)
+			}
+			if key == "n" {
+				h.n = num
+			} else {
+				h.m = num
+			}
+		case "directed", "weighted":
+			flag, err := strconv.ParseBool(value)
+			if err != nil {
+				return h, parseErr(lineNo, ErrInvalidHeader, "%s must be a boolean, got %q", key, value)
+			}
+			if key == "directed" {
+				h.directed = &flag
+			} else {
+				h.weighted = &flag
+			}
+		default:
+			return h, parseErr(lineNo, ErrInvalidHeader, "unknown key %q", key)
+		}
+	}
+	return h, nil
+}
+
+func isEdgeListHeader(line string) bool {
+	for _, field := range strings.Fields(line) {
+		if strings.Contains(field, "=") {
+			return true
+		}
+		switch strings.ToLower(field) {
+		case "directed", "undirected", "weighted", "unweighted":
+			return true
+		}
+	}
+	return false
+}
+
+func splitEdgeListLine(line string, delim rune) []string {
+	if delim == 0 || delim == ' ' {
+		return strings.Fields(line)
+	}
+	parts := strings.Split(line, string(delim))
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+// ReadEdgeList reads a graph given as one edge per line: "u v", "u v w" or
+// "u v w r", where r is a second weight stored in Graph.Resources; it must be
+// given for every edge or none. Vertices are 1-based. Everything after '#' is
+// a comment, blank lines are skipped and the first non-comment line may be a
+// header of key=value pairs (n, m, directed, weighted). An edge given twice
+// (in an undirected graph also as "v u") is an error.
+func ReadEdgeList(r io.Reader, opts EdgeListOptions) (Graph, error) {
+	type rawEdge struct {
+		u, v     int
+		weight   float64
+		hasW     bool
+		resource float64
+		hasR     bool
+		line     int
+	}
+
+	var (
+		edges     []rawEdge
+		maxVertex int
+		header    edgeListHeader
+		hasHeader bool
+		seenData  bool
+		delim     = opts.Delimiter
+		weightedN int
+		resourceN int
+	)
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if !seenData && !hasHeader && isEdgeListHeader(line) {
+			h, err := parseEdgeListHeader(line, lineNo)
+			if err != nil {
+				return Graph{}, err
+			}
+			header, hasHeader = h, true
+			continue
+		}
+		seenData = true
+
+		if delim == 0 {
+			switch {
+			case strings.ContainsRune(line, ','):
+				delim = ','
+			case strings.ContainsRune(line, '\t'):
+				delim = '\t'
+			default:
+				delim = ' '
+			}
+		}
+
+		parts := splitEdgeListLine(line, delim)
+		if len(parts) < 2 {
+			return Graph{}, parseErr(lineNo, ErrMissingField, "expected at least 2 fields, got %d", len(parts))
+		}
+		if len(parts) > 4 {
+			return Graph{}, parseErr(lineNo, ErrTooManyFields, "expected at most 4 fields, got %d", len(parts))
+		}
+
+		e := rawEdge{line: lineNo}
+		for i, dst := range []*int{&e.u, &e.v} {
+			id, err := strconv.Atoi(parts[i])
+			if err != nil || id < 1 {
+				return Graph{}, parseErr(lineNo, ErrInvalidVertex, "%q is not a positive integer", parts[i])
+			}
+			*dst = id
+		}
+		if len(parts) >= 3 {
+			w, err := strconv.ParseFloat(parts[2], 64)
+			if err != nil {
+				return Graph{}, parseErr(lineNo, ErrInvalidWeight, "%q is not a number", parts[2])
+			}
+			e.weight, e.hasW = w, true
+			weightedN++
+		}
+		if len(parts) == 4 {
+			r, err := strconv.ParseFloat(parts[3], 64)
+			if err != nil {
+				return Graph{}, parseErr(lineNo, ErrInvalidWeight, "resource %q is not a number", parts[3])
+			}
+			e.resource, e.hasR = r, true
+			resourceN++
+		}
+
+		maxVertex = max(maxVertex, e.u, e.v)
+		edges = append(edges, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return Graph{}, err
+	}
+
+	directed := opts.Directed
+	if header.directed != nil {
+		directed = *header.directed
+	}
+
+	var weighted bool
+	switch {
+	case header.weighted != nil:
+		weighted = *header.weighted
+	case opts.Weighted != nil:
+		weighted = *opts.Weighted
+	default:
+		weighted = weightedN > 0
+	}
+
+	if weighted && weightedN != len(edges) {
+		for _, e := range edges {
+			if !e.hasW {
+				return Graph{}, parseErr(e.line, ErrInconsistentWeight, "edge %d-%d has no weight", e.u, e.v)
+			}
+		}
+	}
+
+	if resourceN > 0 && resourceN != len(edges) {
+		for _, e := range edges {
+			if !e.hasR {
+				return Graph{}, parseErr(e.line, ErrInconsistentWeight, "edge %d-%d has no resource", e.u, e.v)
+			}
+		}
+	}
+
+	n := maxVertex
+	if hasHeader && header.n >= 0 {
+		if maxVertex > header.n {
+			for _, e := range edges {
+				if e.u > header.n || e.v > header.n {
+					return Graph{}, parseErr(e.line, ErrVertexOutOfRange, "edge %d-%d exceeds n=%d", e.u, e.v, header.n)
+				}
+			}
+		}
+		n = header.n
+	}
+	if hasHeader && header.m >= 0 && header.m != len(edges) {
+		return Graph{}, parseErr(0, ErrHeaderMismatch, "header declares m=%d but %d edges were read", header.m, len(edges))
+	}
+
+	if err := checkVertexLimit(opts.MaxVertices, n, 0); err != nil {
+		return Graph{}, err
+	}
+	graph := NewGraph(n, directed, weighted)
+	for _, e := range edges {
+		if graph.HasEdge(e.u, e.v) {
+			return Graph{}, parseErr(e.line, ErrDuplicateEdge, "edge %d-%d appears more than once", e.u, e.v)
+		}
+		if weighted {
+			graph.AddEdge(e.u, e.v, e.weight)
+		} else {
+			graph.AddEdge(e.u, e.v)
+		}
+		if e.hasR {
+			graph.SetResource(e.u, e.v, e.resource)
+		}
+	}
+	return graph, nil
+}
+
+// LoadEdgeList opens filename and reads it with ReadEdgeList.
+func LoadEdgeList(filename string, opts EdgeListOptions) (Graph, error) {
+	return loadFile(filename, func(r io.Reader) (Graph, error) {
+		return ReadEdgeList(r, opts)
+	})
+}
+
+// WriteEdgeList writes g in the format accepted by ReadEdgeList, starting
+// with a header so the file round-trips without extra options.
+func (g *Graph) WriteEdgeList(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "n=%d m=%d directed=%t weighted=%t\n", len(g.AdjMatrix), len(g.Edges), g.Directed, g.Weighted)
+	for _, edge := range g.Edges {
+		if g.Weighted {
+			weight := g.WeightMatrix[edge[0]-1][edge[1]-1]
+			fmt.Fprintf(bw, "%d %d %s", edge[0], edge[1], strconv.FormatFloat(weight, 'g', -1, 64))
+		} else {
+			fmt.Fprintf(bw, "%d %d", edge[0], edge[1])
+		}
+		if g.Resources != nil {
+			// Czwarta kolumna wymaga trzeciej; w grafie bez wag to zawsze 1
+			if !g.Weighted {
+				fmt.Fprint(bw, " 1")
+			}
+			fmt.Fprintf(bw, " %s", strconv.FormatFloat(g.Resources[edge[0]-1][edge[1]-1], 'g', -1, 64))
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
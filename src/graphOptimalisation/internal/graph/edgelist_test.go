@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadEdgeListHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		vertices int
+		directed bool
+		weighted bool
+	}{
+		{"key=value", "n=4 directed=true\n1 2\n", 4, true, false},
+		{"upper-case keys", "N=5 M=1 Directed=true\n1 2\n", 5, true, false},
+		{"bare flags", "directed weighted\n1 2 3\n", 2, true, true},
+		{"bare flags in any case", "Undirected UNWEIGHTED\n1 2\n", 2, false, false},
+		{"no header", "1 2 0.5\n2 3 1\n", 3, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := ReadEdgeList(strings.NewReader(tt.input), EdgeListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(g.AdjMatrix) != tt.vertices || g.Directed != tt.directed || g.Weighted != tt.weighted {
+				t.Errorf("got %d vertices, directed=%t, weighted=%t; want %d, %t, %t",
+					len(g.AdjMatrix), g.Directed, g.Weighted, tt.vertices, tt.directed, tt.weighted)
+			}
+		})
+	}
+}
+
+func TestReadEdgeListErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"unknown key", "n=2 colour=red\n1 2\n", ErrInvalidHeader},
+		{"bad vertex", "1 x\n", ErrInvalidVertex},
+		{"bad weight", "1 2 heavy\n", ErrInvalidWeight},
+		{"one field", "1\n", ErrMissingField},
+		{"five fields", "1 2 3 4 5\n", ErrTooManyFields},
+		{"vertex above n", "n=2\n1 3\n", ErrVertexOutOfRange},
+		{"duplicate edge", "1 2 1\n2 3 1\n1 2 5\n", ErrDuplicateEdge},
+		{"reversed duplicate", "undirected\n1 2\n2 1\n", ErrDuplicateEdge},
+		{"duplicate loop", "directed\n2 2\n2 2\n", ErrDuplicateEdge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadEdgeList(strings.NewReader(tt.input), EdgeListOptions{})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
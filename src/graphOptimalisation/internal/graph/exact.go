@@ -0,0 +1,248 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// MaxHeldKarpVertices is the largest graph OptimalTour accepts; the
+// dynamic programme needs O(2^n * n) memory.
+const MaxHeldKarpVertices = 20
+
+var ErrTooLarge = errors.New("graph too large for an exact algorithm")
+
+// MinimumVertexCover finds a smallest vertex cover by branch and bound. It
+// branches on a vertex v of maximum degree: either v is in the cover, or all
+// of its neighbours are. Running time is exponential, so it is meant as a
+// baseline for ApproximateVertexCover on small instances. If ctx is
+// cancelled it returns the smallest cover found so far, which is always a
+// valid cover, together with ctx.Err(). Progress goes to progress, which may
+// be nil.
+func (g *Graph) MinimumVertexCover(ctx context.Context, progress ProgressFunc) ([]int, error) {
+	if g.Directed {
+		return nil, fmt.Errorf("MinimumVertexCover: %w", ErrDirectedGraph)
+	}
+	n := len(g.AdjMatrix)
+	adj := make([][]int, n)
+	for _, e := range g.Edges {
+		u, v := e[0]-1, e[1]-1
+		if u == v {
+			continue
+		}
+		adj[u] = append(adj[u], v)
+		adj[v] = append(adj[v], u)
+	}
+
+	// Pętle własne muszą być w każdym pokryciu
+	removed := make([]bool, n)
+	var forced []int
+	for _, e := range g.Edges {
+		if e[0] == e[1] && !removed[e[0]-1] {
+			removed[e[0]-1] = true
+			forced = append(forced, e[0]-1)
+		}
+	}
+
+	// Najlepsze dotąd pokrycie: na start wszystkie wierzchołki z krawędziami.
+	// To tylko górne ograniczenie, które przeszukiwanie z odcięciami poprawia
+	best := make([]int, 0, n)
+	for v := range adj {
+		if !removed[v] && len(adj[v]) > 0 {
+			best = append(best, v)
+		}
+	}
+	current := make([]int, 0, n)
+
+	rep := newReporter(ctx, progress, "cover-exact")
+	nodes := 0
+	var stopped error
+	var search func()
+	search = func() {
+		if stopped != nil {
+			return
+		}
+		if nodes++; nodes%checkEvery == 0 {
+			rep.report("branch and bound", nodes, 0, float64(len(forced)+len(best)))
+			if stopped = ctx.Err(); stopped != nil {
+				return
+			}
+		}
+		// Wierzchołek o największym stopniu w pozostałym grafie
+		maxV, maxDeg, edges := -1, 0, 0
+		for v := range adj {
+			if removed[v] {
+				continue
+			}
+			deg := 0
+			for _, u := range adj[v] {
+				if !removed[u] {
+					deg++
+				}
+			}
+			edges += deg
+			if deg > maxDeg {
+				maxV, maxDeg = v, deg
+			}
+		}
+		if maxV < 0 {
+			if len(current) < len(best) {
+				best = append(best[:0], current...)
+			}
+			return
+		}
+		// Każdy wierzchołek pokrywa co najwyżej maxDeg krawędzi
+		edges /= 2
+		if len(current)+(edges+maxDeg-1)/maxDeg >= len(best) {
+			return
+		}
+
+		removed[maxV] = true
+		current = append(current, maxV)
+		search()
+		current = current[:len(current)-1]
+		removed[maxV] = false
+
+		var taken []int
+		for _, u := range adj[maxV] {
+			if !removed[u] {
+				removed[u] = true
+				taken = append(taken, u)
+			}
+		}
+		current = append(current, taken...)
+		removed[maxV] = true
+		search()
+		removed[maxV] = false
+		current = current[:len(current)-len(taken)]
+		for _, u := range taken {
+			removed[u] = false
+		}
+	}
+	search()
+
+	result := make([]int, 0, len(forced)+len(best))
+	for _, v := range append(forced, best...) {
+		result = append(result, v+1)
+	}
+	sort.Ints(result)
+	return result, stopped
+}
+
+// OptimalTour solves the travelling salesman problem exactly with the
+// Held–Karp dynamic programme over the completed weight matrix, so its cost
+// is comparable with the tours returned by Christofides. It returns the tour
+// starting at vertex 1 and its cost. If some vertices cannot be reached the
+// error wraps ErrDisconnected. If ctx is cancelled it returns a
+// nearest-neighbour tour instead, together with ctx.Err(). Progress goes to
+// progress, which may be nil.
+func (g *Graph) OptimalTour(ctx context.Context, progress ProgressFunc) ([]int, float64, error) {
+	if !g.Weighted {
+		return nil, 0, fmt.Errorf("OptimalTour requires a weighted graph")
+	}
+	if g.Directed {
+		return nil, 0, fmt.Errorf("OptimalTour: %w", ErrDirectedGraph)
+	}
+	n := len(g.WeightMatrix)
+	if n > MaxHeldKarpVertices {
+		return nil, 0, fmt.Errorf("OptimalTour: %w (%d vertices, at most %d)", ErrTooLarge, n, MaxHeldKarpVertices)
+	}
+	if err := g.requireNonNegative("OptimalTour"); err != nil {
+		return nil, 0, err
+	}
+	switch n {
+	case 0:
+		return nil, 0, nil
+	case 1:
+		return []int{1}, 0, nil
+	}
+	dist, err := g.completedWeightMatrix(ctx, progress)
+	if err != nil {
+		return nil, 0, err
+	}
+	rep := newReporter(ctx, progress, "tsp-exact")
+	fallback, fallbackCost := nearestNeighbourTour(dist)
+
+	// cost[S][j]: najkrótsza ścieżka z 0 przez zbiór S (bez 0) kończąca się w j
+	full := 1 << (n - 1)
+	cost := make([][]float64, full)
+	parent := make([][]int8, full)
+	for s := range cost {
+		cost[s] = make([]float64, n-1)
+		parent[s] = make([]int8, n-1)
+		for j := range cost[s] {
+			cost[s][j] = math.Inf(1)
+		}
+	}
+	for j := 0; j < n-1; j++ {
+		cost[1<<j][j] = dist[0][j+1]
+		parent[1<<j][j] = -1
+	}
+	for s := 1; s < full; s++ {
+		if s%checkEvery == 0 {
+			rep.report("Held-Karp", s, full, fallbackCost)
+			if err := ctx.Err(); err != nil {
+				return fallback, fallbackCost, err
+			}
+		}
+		for j := 0; j < n-1; j++ {
+			if s&(1<<j) == 0 || math.IsInf(cost[s][j], 1) {
+				continue
+			}
+			for k := 0; k < n-1; k++ {
+				if s&(1<<k) != 0 {
+					continue
+				}
+				next := s | 1<<k
+				if c := cost[s][j] + dist[j+1][k+1]; c < cost[next][k] {
+					cost[next][k] = c
+					parent[next][k] = int8(j)
+				}
+			}
+		}
+	}
+
+	last, total := 0, math.Inf(1)
+	for j := 0; j < n-1; j++ {
+		if c := cost[full-1][j] + dist[j+1][0]; c < total {
+			last, total = j, c
+		}
+	}
+	if math.IsInf(total, 1) {
+		// Żaden stan nie jest osiągalny, więc parent nie opisuje trasy
+		return nil, 0, fmt.Errorf("OptimalTour: %w", ErrDisconnected)
+	}
+
+	tour := make([]int, n)
+	tour[0] = 1
+	for s, j, i := full-1, last, n-1; j >= 0; i-- {
+		tour[i] = j + 2
+		s, j = s&^(1<<j), int(parent[s][j])
+	}
+	return tour, total, nil
+}
+
+// nearestNeighbourTour builds a tour from vertex 1 by always moving to the
+// closest unvisited vertex.
+func nearestNeighbourTour(dist [][]float64) ([]int, float64) {
+	n := len(dist)
+	visited := make([]bool, n)
+	tour := []int{1}
+	visited[0] = true
+	cost, last := 0.0, 0
+	for len(tour) < n {
+		next := -1
+		for v := range dist {
+			if !visited[v] && (next < 0 || dist[last][v] < dist[last][next]) {
+				next = v
+			}
+		}
+		visited[next] = true
+		tour = append(tour, next+1)
+		cost += dist[last][next]
+		last = next
+	}
+	return tour, cost + dist[last][0]
+}
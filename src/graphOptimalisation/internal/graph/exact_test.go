@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestOptimalTour(t *testing.T) {
+	square := NewGraph(4, false, true)
+	square.AddEdge(1, 2, 1).AddEdge(2, 3, 1).AddEdge(3, 4, 1).AddEdge(4, 1, 1).AddEdge(1, 3, 5)
+	disconnected := NewGraph(4, false, true)
+	disconnected.AddEdge(1, 2, 1).AddEdge(3, 4, 1)
+	empty := NewGraph(0, false, true)
+	single := NewGraph(1, false, true)
+
+	tests := []struct {
+		name  string
+		graph Graph
+		cost  float64
+		err   error
+	}{
+		{"square", square, 4, nil},
+		{"disconnected", disconnected, 0, ErrDisconnected},
+		{"empty", empty, 0, nil},
+		{"single vertex", single, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tour, cost, err := tt.graph.OptimalTour(context.Background(), nil)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if cost != tt.cost || len(tour) != len(tt.graph.AdjMatrix) {
+				t.Errorf("got tour %v of cost %g, want cost %g", tour, cost, tt.cost)
+			}
+		})
+	}
+}
+
+func TestMinimumVertexCover(t *testing.T) {
+	star := NewGraph(5, false, false)
+	star.AddEdge(1, 2).AddEdge(1, 3).AddEdge(1, 4).AddEdge(1, 5)
+	cycle := NewGraph(5, false, false)
+	cycle.AddEdge(1, 2).AddEdge(2, 3).AddEdge(3, 4).AddEdge(4, 5).AddEdge(5, 1)
+	loop := NewGraph(2, false, false)
+	loop.AddEdge(1, 1).AddEdge(1, 2)
+
+	tests := []struct {
+		name  string
+		graph Graph
+		size  int
+	}{
+		{"star", star, 1},
+		{"odd cycle", cycle, 3},
+		{"loop", loop, 1},
+		{"empty", NewGraph(0, false, false), 0},
+		{"no edges", NewGraph(3, false, false), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cover, err := tt.graph.MinimumVertexCover(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cover) != tt.size {
+				t.Errorf("got cover %v, want %d vertices", cover, tt.size)
+			}
+			if !covers(&tt.graph, cover) {
+				t.Errorf("%v does not cover every edge", cover)
+			}
+		})
+	}
+}
+
+// covers reports whether every edge of g has an endpoint in cover.
+func covers(g *Graph, cover []int) bool {
+	in := make(map[int]bool)
+	for _, v := range cover {
+		in[v] = true
+	}
+	for _, e := range g.Edges {
+		if !in[e[0]] && !in[e[1]] {
+			return false
+		}
+	}
+	return true
+}
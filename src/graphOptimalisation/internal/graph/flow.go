@@ -0,0 +1,364 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// flowEpsilon is the residual capacity below which an arc counts as full.
+const flowEpsilon = 1e-9
+
+// MaxFlowAlgorithm selects how MaxFlow works.
+type MaxFlowAlgorithm int
+
+const (
+	// FlowDinic augments along blocking flows in the level graph: O(n²m).
+	FlowDinic MaxFlowAlgorithm = iota
+	// FlowEdmondsKarp augments along shortest paths found by BFS: O(nm²).
+	FlowEdmondsKarp
+	// FlowPushRelabel pushes excess downhill in FIFO order with the gap
+	// heuristic: O(n³).
+	FlowPushRelabel
+)
+
+func (a MaxFlowAlgorithm) String() string {
+	switch a {
+	case FlowDinic:
+		return "dinic"
+	case FlowEdmondsKarp:
+		return "edmonds-karp"
+	case FlowPushRelabel:
+		return "push-relabel"
+	}
+	return "unknown"
+}
+
+// MaxFlowResult is a maximum flow from Source to Sink together with a
+// minimum cut.
+type MaxFlowResult struct {
+	Source int     `json:"source"`
+	Sink   int     `json:"sink"`
+	Value  float64 `json:"value"`
+	// Flow[u-1][v-1] is the flow on the edge (u, v). In an undirected graph
+	// only the direction the flow actually takes is non-zero.
+	Flow [][]float64 `json:"flow"`
+	// SourceSide lists the vertices still reachable from Source in the
+	// residual network; the rest of the vertices form the sink side.
+	SourceSide []int `json:"source_side"`
+	// CutEdges are the edges from the source side to the sink side; their
+	// capacities add up to Value.
+	CutEdges [][2]int `json:"cut_edges"`
+}
+
+// flowArc is an arc of the residual network; the arc at rev in the list of
+// to is its reverse.
+type flowArc struct {
+	to, rev   int
+	cap, flow float64
+	cost      float64 // łuk odwrotny ma koszt przeciwny
+	original  bool    // łuk z grafu, nie dodany jako odwrotny
+}
+
+type flowNetwork struct {
+	adj [][]flowArc
+}
+
+// addArc adds the arc (u, v) and its reverse of capacity zero.
+func (net *flowNetwork) addArc(u, v int, capacity, cost float64, original bool) {
+	ru, rv := len(net.adj[u]), len(net.adj[v])
+	net.adj[u] = append(net.adj[u], flowArc{to: v, rev: rv, cap: capacity, cost: cost, original: original})
+	net.adj[v] = append(net.adj[v], flowArc{to: u, rev: ru, cost: -cost})
+}
+
+func (net *flowNetwork) residual(a *flowArc) float64 { return a.cap - a.flow }
+
+func (net *flowNetwork) push(a *flowArc, amount float64) {
+	a.flow += amount
+	net.adj[a.to][a.rev].flow -= amount
+}
+
+// flowNetwork builds the residual network with the edge weights as
+// capacities; an undirected edge can carry flow either way.
+func (g *Graph) flowNetwork() (*flowNetwork, error) {
+	net := &flowNetwork{adj: make([][]flowArc, len(g.AdjMatrix))}
+	for u, arcs := range g.arcs() {
+		for _, a := range arcs {
+			if a.weight < 0 {
+				return nil, fmt.Errorf("%w (%d, %d): %g is not a capacity", ErrNegativeWeight, u+1, a.to+1, a.weight)
+			}
+			if u == a.to {
+				continue // pętla nie przenosi przepływu
+			}
+			net.addArc(u, a.to, a.weight, 0, true)
+		}
+	}
+	return net, nil
+}
+
+// MaxFlow computes a maximum flow from source to sink treating edge weights
+// as capacities (1 in an unweighted graph) and a minimum cut separating
+// them. Capacities must not be negative. If ctx is cancelled it returns nil
+// and ctx.Err(). Progress goes to progress, which may be nil.
+func (g *Graph) MaxFlow(ctx context.Context, source, sink int, algorithm MaxFlowAlgorithm, progress ProgressFunc) (*MaxFlowResult, error) {
+	for _, v := range []int{source, sink} {
+		if err := g.CheckVertex(v); err != nil {
+			return nil, fmt.Errorf("MaxFlow: %w", err)
+		}
+	}
+	if source == sink {
+		return nil, fmt.Errorf("MaxFlow: source and sink are both %d", source)
+	}
+	net, err := g.flowNetwork()
+	if err != nil {
+		return nil, fmt.Errorf("MaxFlow: %w", err)
+	}
+	s, t := source-1, sink-1
+	switch algorithm {
+	case FlowEdmondsKarp:
+		err = net.edmondsKarp(newReporter(ctx, progress, "edmonds-karp"), s, t)
+	case FlowPushRelabel:
+		err = net.pushRelabel(newReporter(ctx, progress, "push-relabel"), s, t)
+	default:
+		err = net.dinic(newReporter(ctx, progress, "dinic"), s, t)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return g.maxFlowResult(net, source, sink), nil
+}
+
+func (g *Graph) maxFlowResult(net *flowNetwork, source, sink int) *MaxFlowResult {
+	n := len(g.AdjMatrix)
+	res := &MaxFlowResult{Source: source, Sink: sink, Flow: make([][]float64, n)}
+	for u := range res.Flow {
+		res.Flow[u] = make([]float64, n)
+	}
+	for u, arcs := range net.adj {
+		for _, a := range arcs {
+			if a.original {
+				res.Flow[u][a.to] += a.flow
+			}
+		}
+	}
+	if !g.Directed {
+		// Przepływy w przeciwnych kierunkach tej samej krawędzi się znoszą
+		for u := range res.Flow {
+			for v := u + 1; v < n; v++ {
+				net := res.Flow[u][v] - res.Flow[v][u]
+				res.Flow[u][v], res.Flow[v][u] = max(net, 0), max(-net, 0)
+			}
+		}
+	}
+	for v := range res.Flow {
+		res.Value += res.Flow[source-1][v] - res.Flow[v][source-1]
+	}
+
+	reachable := net.reachable(source - 1)
+	for v, ok := range reachable {
+		if ok {
+			res.SourceSide = append(res.SourceSide, v+1)
+		}
+	}
+	for _, e := range g.Edges {
+		u, v := e[0]-1, e[1]-1
+		switch {
+		case reachable[u] && !reachable[v]:
+			res.CutEdges = append(res.CutEdges, [2]int{u + 1, v + 1})
+		case !g.Directed && reachable[v] && !reachable[u]:
+			res.CutEdges = append(res.CutEdges, [2]int{v + 1, u + 1})
+		}
+	}
+	return res
+}
+
+// reachable marks the vertices reachable from s over arcs with residual
+// capacity.
+func (net *flowNetwork) reachable(s int) []bool {
+	seen := make([]bool, len(net.adj))
+	seen[s] = true
+	stack := []int{s}
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for i := range net.adj[u] {
+			a := &net.adj[u][i]
+			if !seen[a.to] && net.residual(a) > flowEpsilon {
+				seen[a.to] = true
+				stack = append(stack, a.to)
+			}
+		}
+	}
+	return seen
+}
+
+func (net *flowNetwork) edmondsKarp(rep reporter, s, t int) error {
+	n := len(net.adj)
+	// predArc[v]: indeks łuku w liście poprzednika, którym BFS dotarł do v
+	pred, predArc := make([]int, n), make([]int, n)
+	for round := 0; ; round++ {
+		if err := rep.step("augmenting paths", round, 0); err != nil {
+			return err
+		}
+		for i := range pred {
+			pred[i] = -1
+		}
+		pred[s] = s
+		queue := []int{s}
+		for len(queue) > 0 && pred[t] < 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for i := range net.adj[u] {
+				a := &net.adj[u][i]
+				if pred[a.to] < 0 && net.residual(a) > flowEpsilon {
+					pred[a.to], predArc[a.to] = u, i
+					queue = append(queue, a.to)
+				}
+			}
+		}
+		if pred[t] < 0 {
+			return nil
+		}
+		amount := math.Inf(1)
+		for v := t; v != s; v = pred[v] {
+			amount = min(amount, net.residual(&net.adj[pred[v]][predArc[v]]))
+		}
+		for v := t; v != s; v = pred[v] {
+			net.push(&net.adj[pred[v]][predArc[v]], amount)
+		}
+	}
+}
+
+func (net *flowNetwork) dinic(rep reporter, s, t int) error {
+	n := len(net.adj)
+	level := make([]int, n)
+	next := make([]int, n) // pierwszy łuk jeszcze do sprawdzenia w DFS
+
+	var augment func(u int, limit float64) float64
+	augment = func(u int, limit float64) float64 {
+		if u == t {
+			return limit
+		}
+		for ; next[u] < len(net.adj[u]); next[u]++ {
+			a := &net.adj[u][next[u]]
+			if level[a.to] != level[u]+1 || net.residual(a) <= flowEpsilon {
+				continue
+			}
+			if pushed := augment(a.to, min(limit, net.residual(a))); pushed > 0 {
+				net.push(a, pushed)
+				return pushed
+			}
+		}
+		return 0
+	}
+
+	for phase := 0; ; phase++ {
+		if err := rep.step("blocking flows", phase, 0); err != nil {
+			return err
+		}
+		// Graf warstwowy: odległości BFS w sieci residualnej
+		for i := range level {
+			level[i] = -1
+		}
+		level[s] = 0
+		queue := []int{s}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for i := range net.adj[u] {
+				a := &net.adj[u][i]
+				if level[a.to] < 0 && net.residual(a) > flowEpsilon {
+					level[a.to] = level[u] + 1
+					queue = append(queue, a.to)
+				}
+			}
+		}
+		if level[t] < 0 {
+			return nil
+		}
+		for i := range next {
+			next[i] = 0
+		}
+		for augment(s, math.Inf(1)) > 0 {
+		}
+	}
+}
+
+func (net *flowNetwork) pushRelabel(rep reporter, s, t int) error {
+	n := len(net.adj)
+	height := make([]int, n)
+	excess := make([]float64, n)
+	count := make([]int, 2*n+1) // liczba wierzchołków na każdej wysokości
+	next := make([]int, n)
+	active := make([]bool, n)
+	var queue []int
+
+	enqueue := func(v int) {
+		if !active[v] && v != s && v != t && excess[v] > flowEpsilon {
+			active[v] = true
+			queue = append(queue, v)
+		}
+	}
+	height[s] = n
+	count[0], count[n] = n-1, 1
+	for i := range net.adj[s] {
+		a := &net.adj[s][i]
+		if amount := net.residual(a); amount > 0 {
+			net.push(a, amount)
+			excess[a.to] += amount
+			excess[s] -= amount
+			enqueue(a.to)
+		}
+	}
+
+	for ops := 0; len(queue) > 0; ops++ {
+		if ops%checkEvery == 0 {
+			if err := rep.step("discharging vertices", ops, 0); err != nil {
+				return err
+			}
+		}
+		u := queue[0]
+		queue = queue[1:]
+		active[u] = false
+
+		// Rozładowanie: pchamy w dół, a gdy nie ma dokąd, podnosimy wierzchołek
+		for excess[u] > flowEpsilon {
+			if next[u] == len(net.adj[u]) {
+				old := height[u]
+				height[u] = 2 * n
+				for i := range net.adj[u] {
+					a := &net.adj[u][i]
+					if net.residual(a) > flowEpsilon {
+						height[u] = min(height[u], height[a.to]+1)
+					}
+				}
+				count[old]--
+				count[height[u]]++
+				next[u] = 0
+				// Luka: wierzchołki powyżej pustej wysokości nie dotrą już do ujścia
+				if count[old] == 0 && old < n {
+					for v := range height {
+						if v != s && height[v] > old && height[v] < n {
+							count[height[v]]--
+							height[v] = n + 1
+							count[height[v]]++
+							next[v] = 0
+						}
+					}
+				}
+				continue
+			}
+			a := &net.adj[u][next[u]]
+			if net.residual(a) > flowEpsilon && height[u] == height[a.to]+1 {
+				amount := min(excess[u], net.residual(a))
+				net.push(a, amount)
+				excess[u] -= amount
+				excess[a.to] += amount
+				enqueue(a.to)
+			} else {
+				next[u]++
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Simikao/graphOptimalisation/internal/generate"
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+var flowAlgorithms = []g.MaxFlowAlgorithm{g.FlowDinic, g.FlowEdmondsKarp, g.FlowPushRelabel}
+
+func capacity(graph *g.Graph, u, v int) float64 {
+	if !graph.HasEdge(u, v) {
+		return 0
+	}
+	if graph.Weighted {
+		return graph.WeightMatrix[u-1][v-1]
+	}
+	return 1
+}
+
+// checkFlow verifies capacities, conservation and the value of the flow,
+// and that the cut separates source from sink with capacity equal to it.
+func checkFlow(t *testing.T, graph *g.Graph, res *g.MaxFlowResult) {
+	t.Helper()
+	const eps = 1e-9
+	n := len(graph.AdjMatrix)
+	for u := 1; u <= n; u++ {
+		for v := 1; v <= n; v++ {
+			if f := res.Flow[u-1][v-1]; f < -eps || f > capacity(graph, u, v)+eps {
+				t.Errorf("flow %g on (%d, %d) exceeds capacity %g", f, u, v, capacity(graph, u, v))
+			}
+		}
+	}
+	for v := 1; v <= n; v++ {
+		balance := 0.0
+		for u := 1; u <= n; u++ {
+			balance += res.Flow[v-1][u-1] - res.Flow[u-1][v-1]
+		}
+		want := 0.0
+		switch v {
+		case res.Source:
+			want = res.Value
+		case res.Sink:
+			want = -res.Value
+		}
+		if math.Abs(balance-want) > eps {
+			t.Errorf("vertex %d sends out %g, want %g", v, balance, want)
+		}
+	}
+
+	sourceSide := make([]bool, n+1)
+	for _, v := range res.SourceSide {
+		sourceSide[v] = true
+	}
+	if !sourceSide[res.Source] || sourceSide[res.Sink] {
+		t.Errorf("source side %v does not separate %d from %d", res.SourceSide, res.Source, res.Sink)
+	}
+	cut := 0.0
+	for _, e := range res.CutEdges {
+		if !sourceSide[e[0]] || sourceSide[e[1]] {
+			t.Errorf("cut edge %v does not cross the cut", e)
+		}
+		cut += capacity(graph, e[0], e[1])
+	}
+	if math.Abs(cut-res.Value) > eps {
+		t.Errorf("cut capacity %g, flow value %g", cut, res.Value)
+	}
+}
+
+// bruteMinCut tries every set of vertices holding source but not sink and
+// returns the least capacity of the edges leaving it.
+func bruteMinCut(graph *g.Graph, source, sink int) float64 {
+	n := len(graph.AdjMatrix)
+	best := math.Inf(1)
+	for mask := 0; mask < 1<<n; mask++ {
+		in := func(v int) bool { return mask&(1<<(v-1)) != 0 }
+		if !in(source) || in(sink) {
+			continue
+		}
+		cut := 0.0
+		for u := 1; u <= n; u++ {
+			for v := 1; v <= n; v++ {
+				if in(u) && !in(v) {
+					cut += capacity(graph, u, v)
+				}
+			}
+		}
+		best = min(best, cut)
+	}
+	return best
+}
+
+func TestMaxFlow(t *testing.T) {
+	tests := []struct {
+		name         string
+		n            int
+		directed     bool
+		weighted     bool
+		edges        [][3]float64
+		source, sink int
+		value        float64
+	}{
+		{
+			// Przykład z Cormena: s = 1, t = 6
+			"CLRS network", 6, true, true,
+			[][3]float64{{1, 2, 16}, {1, 3, 13}, {2, 4, 12}, {3, 2, 4}, {3, 5, 14}, {4, 3, 9}, {4, 6, 20}, {5, 4, 7}, {5, 6, 4}},
+			1, 6, 23,
+		},
+		{"edge-disjoint paths", 4, true, false, [][3]float64{{1, 2}, {1, 3}, {2, 4}, {3, 4}, {2, 3}}, 1, 4, 2},
+		{"undirected", 4, false, true, [][3]float64{{1, 2, 3}, {1, 3, 2}, {2, 3, 5}, {2, 4, 2}, {3, 4, 3}}, 1, 4, 5},
+		{"sink unreachable", 4, true, true, [][3]float64{{1, 2, 3}, {4, 3, 1}}, 1, 4, 0},
+		{"disconnected", 4, false, true, [][3]float64{{1, 2, 3}, {3, 4, 1}}, 1, 3, 0},
+		{"loop and zero capacity", 3, true, true, [][3]float64{{1, 1, 5}, {1, 2, 0}, {2, 3, 4}, {1, 3, 1}}, 1, 3, 1},
+	}
+	for _, tt := range tests {
+		graph := g.NewGraph(tt.n, tt.directed, tt.weighted)
+		for _, e := range tt.edges {
+			if tt.weighted {
+				graph.AddEdge(int(e[0]), int(e[1]), e[2])
+			} else {
+				graph.AddEdge(int(e[0]), int(e[1]))
+			}
+		}
+		for _, alg := range flowAlgorithms {
+			t.Run(tt.name+"/"+alg.String(), func(t *testing.T) {
+				res, err := graph.MaxFlow(context.Background(), tt.source, tt.sink, alg, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if res.Value != tt.value {
+					t.Errorf("value %g, want %g", res.Value, tt.value)
+				}
+				checkFlow(t, &graph, res)
+			})
+		}
+	}
+}
+
+func TestMaxFlowErrors(t *testing.T) {
+	ctx := context.Background()
+	negative := g.NewGraph(2, true, true)
+	negative.AddEdge(1, 2, -1)
+	single := g.NewGraph(1, true, false)
+	empty := g.NewGraph(0, true, false)
+	for _, alg := range flowAlgorithms {
+		if _, err := negative.MaxFlow(ctx, 1, 2, alg, nil); !errors.Is(err, g.ErrNegativeWeight) {
+			t.Errorf("%s with a negative capacity: got %v, want %v", alg, err, g.ErrNegativeWeight)
+		}
+		if _, err := single.MaxFlow(ctx, 1, 1, alg, nil); err == nil {
+			t.Errorf("%s accepted the same source and sink", alg)
+		}
+		if _, err := empty.MaxFlow(ctx, 1, 2, alg, nil); !errors.Is(err, g.ErrVertexOutOfRange) {
+			t.Errorf("%s on the empty graph: got %v, want %v", alg, err, g.ErrVertexOutOfRange)
+		}
+	}
+}
+
+func TestMaxFlowAlgorithmsAgree(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 30; seed++ {
+		gen := generate.New(seed)
+		gen.Weighted, gen.Directed = seed%3 != 0, seed%2 == 0
+		gen.MinWeight = 0
+		graph, err := gen.ErdosRenyi(8, 0.2+float64(seed%4)*0.15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := bruteMinCut(&graph, 1, 8)
+		for _, alg := range flowAlgorithms {
+			res, err := graph.MaxFlow(ctx, 1, 8, alg, nil)
+			if err != nil {
+				t.Fatalf("seed %d: %s: %v", seed, alg, err)
+			}
+			if math.Abs(res.Value-want) > 1e-9 {
+				t.Errorf("seed %d: %s finds flow %g, minimum cut is %g", seed, alg, res.Value, want)
+			}
+			checkFlow(t, &graph, res)
+		}
+	}
+}
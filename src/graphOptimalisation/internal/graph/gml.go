@@ -0,0 +1,310 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// gmlValue is a GML value: a number, a string or a nested list of pairs.
+type gmlValue struct {
+	num    float64
+	str    string
+	list   []gmlPair
+	isNum  bool
+	isList bool
+}
+
+type gmlPair struct {
+	key   string
+	value gmlValue
+	line  int
+}
+
+// maxGMLDepth bounds how deeply GML lists may nest; a graph needs three
+// levels (graph, node, graphics), and unbounded recursion would let a small
+// input exhaust the stack.
+const maxGMLDepth = 64
+
+type gmlLexer struct {
+	r    *bufio.Reader
+	line int
+}
+
+// next returns the next token: "[", "]", a key, a number or a quoted string
+// (returned with its quotes). An empty token means end of input.
+func (l *gmlLexer) next() (string, error) {
+	for {
+		c, _, err := l.r.ReadRune()
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case c == '\n':
+			l.line++
+		case unicode.IsSpace(c):
+		case c == '#':
+			if _, err := l.r.ReadString('\n'); err != nil && err != io.EOF {
+				return "", err
+			}
+			l.line++
+		case c == '[' || c == ']':
+			return string(c), nil
+		case c == '"':
+			s, err := l.r.ReadString('"')
+			if err != nil {
+				return "", parseErr(l.line, ErrMissingField, "unterminated string")
+			}
+			l.line += strings.Count(s, "\n")
+			return "\"" + s, nil
+		default:
+			var sb strings.Builder
+			sb.WriteRune(c)
+			for {
+				c, _, err := l.r.ReadRune()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return "", err
+				}
+				if unicode.IsSpace(c) || c == '[' || c == ']' || c == '"' {
+					l.r.UnreadRune()
+					break
+				}
+				sb.WriteRune(c)
+			}
+			return sb.String(), nil
+		}
+	}
+}
+
+// parseList reads key/value pairs until "]" (nested lists, depth > 0) or end
+// of input (the top level, depth 0).
+func (l *gmlLexer) parseList(depth int) ([]gmlPair, error) {
+	nested := depth > 0
+	var pairs []gmlPair
+	for {
+		key, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "":
+			if nested {
+				return nil, parseErr(l.line+1, ErrMissingField, "missing ']'")
+			}
+			return pairs, nil
+		case "]":
+			if !nested {
+				return nil, parseErr(l.line+1, ErrInvalidHeader, "unexpected ']'")
+			}
+			return pairs, nil
+		case "[":
+			return nil, parseErr(l.line+1, ErrInvalidHeader, "unexpected '['")
+		}
+
+		pair := gmlPair{key: key, line: l.line + 1}
+		token, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case token == "":
+			return nil, parseErr(l.line+1, ErrMissingField, "key %q has no value", key)
+		case token == "[":
+			if depth >= maxGMLDepth {
+				return nil, parseErr(l.line+1, ErrInvalidHeader, "lists nested deeper than %d", maxGMLDepth)
+			}
+			pair.value.isList = true
+			if pair.value.list, err = l.parseList(depth + 1); err != nil {
+				return nil, err
+			}
+		case token == "]":
+			return nil, parseErr(l.line+1, ErrMissingField, "key %q has no value", key)
+		case token[0] == '"':
+			pair.value.str = token[1 : len(token)-1]
+		default:
+			num, err := strconv.ParseFloat(token, 64)
+			if err != nil {
+				return nil, parseErr(l.line+1, ErrInvalidEntry, "%q is neither a number nor a string", token)
+			}
+			pair.value.num, pair.value.isNum = num, true
+		}
+		pairs = append(pairs, pair)
+	}
+}
+
+// ReadGML reads the first "graph" of a GML file. Node ids may be arbitrary
+// integers; they are renumbered 1..n in order of appearance. Node "label"
+// becomes the vertex label and "graphics [ x y ]" its coordinates; an edge
+// "weight" (or "value") becomes the edge weight.
+func ReadGML(r io.Reader, opts ReadOptions) (Graph, error) {
+	lexer := &gmlLexer{r: bufio.NewReader(r)}
+	top, err := lexer.parseList(0)
+	if err != nil {
+		return Graph{}, err
+	}
+
+	var body []gmlPair
+	for _, pair := range top {
+		if pair.key == "graph" && pair.value.isList {
+			body = pair.value.list
+			break
+		}
+	}
+	if body == nil {
+		return Graph{}, parseErr(0, ErrInvalidHeader, "no graph [ ... ] block")
+	}
+
+	type rawEdge struct {
+		u, v   int
+		weight float64
+	}
+
+	var (
+		directed bool
+		ids      = make(map[int]int)
+		labels   []string
+		coords   [][2]float64
+		hasXY    bool
+		edges    []rawEdge
+		weighted bool
+	)
+	for _, pair := range body {
+		switch pair.key {
+		case "directed":
+			directed = pair.value.isNum && pair.value.num != 0
+		case "node":
+			id, hasID := 0, false
+			label := ""
+			var xy [2]float64
+			for _, attr := range pair.value.list {
+				switch attr.key {
+				case "id":
+					if !attr.value.isNum {
+						return Graph{}, parseErr(attr.line, ErrInvalidVertex, "node id must be a number")
+					}
+					id, hasID = int(attr.value.num), true
+				case "label":
+					if attr.value.isNum {
+						label = strconv.FormatFloat(attr.value.num, 'g', -1, 64)
+					} else {
+						label = attr.value.str
+					}
+				case "graphics":
+					for _, g := range attr.value.list {
+						if !g.value.isNum {
+							continue
+						}
+						switch g.key {
+						case "x":
+							xy[0], hasXY = g.value.num, true
+						case "y":
+							xy[1], hasXY = g.value.num, true
+						}
+					}
+				}
+			}
+			if !hasID {
+				return Graph{}, parseErr(pair.line, ErrMissingField, "node without id")
+			}
+			if _, dup := ids[id]; dup {
+				return Graph{}, parseErr(pair.line, ErrInvalidVertex, "duplicate node id %d", id)
+			}
+			ids[id] = len(labels) + 1
+			labels = append(labels, label)
+			coords = append(coords, xy)
+		case "edge":
+			e := rawEdge{weight: 1}
+			var hasSource, hasTarget bool
+			for _, attr := range pair.value.list {
+				switch attr.key {
+				case "source", "target":
+					if !attr.value.isNum {
+						return Graph{}, parseErr(attr.line, ErrInvalidVertex, "%s must be a number", attr.key)
+					}
+					v, ok := ids[int(attr.value.num)]
+					if !ok {
+						return Graph{}, parseErr(attr.line, ErrInvalidVertex, "unknown node %v", attr.value.num)
+					}
+					if attr.key == "source" {
+						e.u, hasSource = v, true
+					} else {
+						e.v, hasTarget = v, true
+					}
+				case "weight", "value":
+					if !attr.value.isNum {
+						return Graph{}, parseErr(attr.line, ErrInvalidWeight, "%s must be a number", attr.key)
+					}
+					e.weight, weighted = attr.value.num, true
+				}
+			}
+			if !hasSource || !hasTarget {
+				return Graph{}, parseErr(pair.line, ErrMissingField, "edge needs source and target")
+			}
+			edges = append(edges, e)
+		}
+	}
+
+	if err := checkVertexLimit(opts.MaxVertices, len(labels), 0); err != nil {
+		return Graph{}, err
+	}
+	graph := NewGraph(len(labels), directed, weighted)
+	for _, e := range edges {
+		graph.AddEdge(e.u, e.v, e.weight)
+	}
+	for _, label := range labels {
+		if label != "" {
+			graph.Labels = labels
+			break
+		}
+	}
+	if hasXY {
+		graph.Coords = coords
+	}
+	return graph, nil
+}
+
+// LoadGML opens filename and reads it with ReadGML.
+func LoadGML(filename string) (Graph, error) {
+	return loadFile(filename, func(r io.Reader) (Graph, error) {
+		return ReadGML(r, ReadOptions{})
+	})
+}
+
+// WriteGML writes g as a GML graph with 1-based node ids.
+func (g *Graph) WriteGML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	directed := 0
+	if g.Directed {
+		directed = 1
+	}
+	fmt.Fprintf(bw, "graph [\n  directed %d\n", directed)
+	for v := 1; v <= len(g.AdjMatrix); v++ {
+		fmt.Fprintf(bw, "  node [\n    id %d\n", v)
+		if v-1 < len(g.Labels) && g.Labels[v-1] != "" {
+			fmt.Fprintf(bw, "    label \"%s\"\n", strings.ReplaceAll(g.Labels[v-1], "\"", "'"))
+		}
+		if g.Coords != nil {
+			fmt.Fprintf(bw, "    graphics [ x %g y %g ]\n", g.Coords[v-1][0], g.Coords[v-1][1])
+		}
+		bw.WriteString("  ]\n")
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(bw, "  edge [\n    source %d\n    target %d\n", edge[0], edge[1])
+		if g.Weighted {
+			weight := g.WeightMatrix[edge[0]-1][edge[1]-1]
+			fmt.Fprintf(bw, "    weight %s\n", strconv.FormatFloat(weight, 'g', -1, 64))
+		}
+		bw.WriteString("  ]\n")
+	}
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
@@ -0,0 +1,27 @@
+package graph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadGMLNestingLimit(t *testing.T) {
+	// Bez limitu taka lista przepełnia stos i zabija cały proces
+	_, err := ReadGML(strings.NewReader(strings.Repeat("a[", 1<<20)), ReadOptions{})
+	var perr *ParseError
+	if !errors.As(err, &perr) || !errors.Is(err, ErrInvalidHeader) {
+		t.Fatalf("got %v, want a *ParseError wrapping %v", err, ErrInvalidHeader)
+	}
+
+	// Głębokie, ale dopuszczalne zagnieżdżenie wciąż się wczytuje
+	deep := strings.Repeat("a [ ", maxGMLDepth-3) + strings.Repeat("] ", maxGMLDepth-3)
+	input := "graph [ node [ id 1 " + deep + "] node [ id 2 ] edge [ source 1 target 2 ] ]"
+	g, err := ReadGML(strings.NewReader(input), ReadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.AdjMatrix) != 2 || len(g.Edges) != 1 {
+		t.Errorf("got %d vertices and %d edges, want 2 and 1", len(g.AdjMatrix), len(g.Edges))
+	}
+}
@@ -1,21 +1,41 @@
 package graph
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 )
 
-var ErrDirectedGraph = errors.New("Cannot use a directed graph in this algorithm")
+var (
+	ErrDirectedGraph  = errors.New("Cannot use a directed graph in this algorithm")
+	ErrNoEdge         = errors.New("no such edge")
+	ErrNegativeWeight = errors.New("negative edge weight")
+	ErrNegativeCycle  = errors.New("negative cycle")
+	ErrNoResources    = errors.New("graph has no edge resources")
+)
 
 type Graph struct {
-	AdjMatrix    [][]int
+	// AdjMatrix decides which edges exist (AdjMatrix[u-1][v-1] > 0).
+	AdjMatrix [][]int
+	// WeightMatrix holds the weight of every existing edge; the weight may be
+	// zero or negative. Entries of missing edges carry no meaning.
 	WeightMatrix [][]float64
 	Directed     bool
 	Weighted     bool
 	Edges        [][2]int
+	// Opcjonalne etykiety wierzchołków (Labels[v-1]); nil jeśli brak
+	Labels []string
+	// Opcjonalne współrzędne wierzchołków (Coords[v-1] = {x, y}); nil jeśli brak
+	Coords [][2]float64
+	// Opcjonalna druga waga krawędzi, np. czas przejazdu (Resources[u-1][v-1]);
+	// nil jeśli brak
+	Resources [][]float64
 }
 
 func getEdges(vertices [][]int, directed bool) [][2]int {
@@ -36,10 +56,18 @@ func getEdges(vertices [][]int, directed bool) [][2]int {
 	return edges
 }
 
+// HasEdge reports whether the edge (u, v) exists.
+func (g *Graph) HasEdge(u, v int) bool {
+	return g.AdjMatrix[u-1][v-1] > 0
+}
+
 func (g *Graph) SetWeight(u, v int, weight float64) error {
 	if !g.Weighted {
 		return fmt.Errorf("cannot set weight on an unweighted graph")
 	}
+	if !g.HasEdge(u, v) {
+		return fmt.Errorf("SetWeight(%d, %d): %w", u, v, ErrNoEdge)
+	}
 
 	u, v = u-1, v-1
 	g.WeightMatrix[u][v] = weight
@@ -53,11 +81,35 @@ func (g *Graph) GetWeight(u, v int) (float64, error) {
 	if !g.Weighted {
 		return 0, fmt.Errorf("graph is unweighted")
 	}
+	if !g.HasEdge(u, v) {
+		return math.Inf(1), fmt.Errorf("GetWeight(%d, %d): %w", u, v, ErrNoEdge)
+	}
 
 	u, v = u-1, v-1
 	return g.WeightMatrix[u][v], nil
 }
 
+// SetResource sets the second weight of the edge (u, v), allocating
+// Resources on first use.
+func (g *Graph) SetResource(u, v int, resource float64) error {
+	if !g.HasEdge(u, v) {
+		return fmt.Errorf("SetResource(%d, %d): %w", u, v, ErrNoEdge)
+	}
+	if g.Resources == nil {
+		g.Resources = make([][]float64, len(g.AdjMatrix))
+		for i := range g.Resources {
+			g.Resources[i] = make([]float64, len(g.AdjMatrix))
+		}
+	}
+
+	u, v = u-1, v-1
+	g.Resources[u][v] = resource
+	if !g.Directed {
+		g.Resources[v][u] = resource
+	}
+	return nil
+}
+
 func NewGraphWithMatrix(vertices [][]int, directed bool) Graph {
 	edges := getEdges(vertices, directed)
 	return Graph{
@@ -99,6 +151,35 @@ func internalNewGraph(matrix [][]int, directed bool, edges [][2]int) Graph {
 	}
 }
 
+// Clone returns a deep copy of g, so algorithms that modify the graph
+// (e.g. ChinesePostmanProblem) can run without touching the original.
+func (g *Graph) Clone() Graph {
+	clone := Graph{
+		Directed: g.Directed,
+		Weighted: g.Weighted,
+		Edges:    append([][2]int(nil), g.Edges...),
+		Labels:   append([]string(nil), g.Labels...),
+		Coords:   append([][2]float64(nil), g.Coords...),
+	}
+	clone.AdjMatrix = make([][]int, len(g.AdjMatrix))
+	for i, row := range g.AdjMatrix {
+		clone.AdjMatrix[i] = append([]int(nil), row...)
+	}
+	if g.WeightMatrix != nil {
+		clone.WeightMatrix = make([][]float64, len(g.WeightMatrix))
+		for i, row := range g.WeightMatrix {
+			clone.WeightMatrix[i] = append([]float64(nil), row...)
+		}
+	}
+	if g.Resources != nil {
+		clone.Resources = make([][]float64, len(g.Resources))
+		for i, row := range g.Resources {
+			clone.Resources[i] = append([]float64(nil), row...)
+		}
+	}
+	return clone
+}
+
 func (g *Graph) UpdateEdges() {
 	g.Edges = getEdges(g.AdjMatrix, g.Directed)
 }
@@ -132,6 +213,18 @@ func (g *Graph) RemoveEdge(u, v int) *Graph {
 	if !g.Directed {
 		g.AdjMatrix[v][u] = 0
 	}
+	if g.Weighted {
+		g.WeightMatrix[u][v] = 0
+		if !g.Directed {
+			g.WeightMatrix[v][u] = 0
+		}
+	}
+	if g.Resources != nil {
+		g.Resources[u][v] = 0
+		if !g.Directed {
+			g.Resources[v][u] = 0
+		}
+	}
 	// Remove the edge from edge slice as well
 	for i, edge := range g.Edges {
 		if (edge[0] == u+1 && edge[1] == v+1) || (!g.Directed && edge[0] == v+1 && edge[1] == u+1) {
@@ -148,13 +241,31 @@ func (g *Graph) AddVertex() *Graph {
 	}
 	newRow := make([]int, len(g.AdjMatrix)+1)
 	g.AdjMatrix = append(g.AdjMatrix, newRow)
+	if g.Weighted {
+		for i := range g.WeightMatrix {
+			g.WeightMatrix[i] = append(g.WeightMatrix[i], 0)
+		}
+		g.WeightMatrix = append(g.WeightMatrix, make([]float64, len(g.AdjMatrix)))
+	}
+	if g.Resources != nil {
+		for i := range g.Resources {
+			g.Resources[i] = append(g.Resources[i], 0)
+		}
+		g.Resources = append(g.Resources, make([]float64, len(g.AdjMatrix)))
+	}
+	if g.Labels != nil {
+		g.Labels = append(g.Labels, "")
+	}
+	if g.Coords != nil {
+		g.Coords = append(g.Coords, [2]float64{})
+	}
 	return g
 }
 
 func (g *Graph) RemoveVertex(v int) *Graph {
 	v -= 1
 	// check if the vertex exists
-	if v >= len(g.AdjMatrix) {
+	if v < 0 || v >= len(g.AdjMatrix) {
 		return g
 	}
 
@@ -166,10 +277,36 @@ func (g *Graph) RemoveVertex(v int) *Graph {
 		g.AdjMatrix[i] = append(g.AdjMatrix[i][:v], g.AdjMatrix[i][v+1:]...)
 	}
 
+	if g.Weighted {
+		g.WeightMatrix = append(g.WeightMatrix[:v], g.WeightMatrix[v+1:]...)
+		for i := range g.WeightMatrix {
+			g.WeightMatrix[i] = append(g.WeightMatrix[i][:v], g.WeightMatrix[i][v+1:]...)
+		}
+	}
+	if g.Resources != nil {
+		g.Resources = append(g.Resources[:v], g.Resources[v+1:]...)
+		for i := range g.Resources {
+			g.Resources[i] = append(g.Resources[i][:v], g.Resources[i][v+1:]...)
+		}
+	}
+
+	if g.Labels != nil {
+		g.Labels = append(g.Labels[:v], g.Labels[v+1:]...)
+	}
+	if g.Coords != nil {
+		g.Coords = append(g.Coords[:v], g.Coords[v+1:]...)
+	}
+
 	// Update edges of the graph by removing all edges with the removed vertex from the list
+	// and shifting the numbers of the vertices after it
 	var updatedEdges [][2]int
 	for _, edge := range g.Edges {
 		if edge[0] != v+1 && edge[1] != v+1 {
+			for i := range edge {
+				if edge[i] > v+1 {
+					edge[i]--
+				}
+			}
 			updatedEdges = append(updatedEdges, edge)
 		}
 	}
@@ -178,6 +315,14 @@ func (g *Graph) RemoveVertex(v int) *Graph {
 	return g
 }
 
+// Label returns the label of vertex v, or its number if it has none.
+func (g *Graph) Label(v int) string {
+	if v-1 < len(g.Labels) && g.Labels[v-1] != "" {
+		return g.Labels[v-1]
+	}
+	return strconv.Itoa(v)
+}
+
 func (g *Graph) GetOutDegree(v int) int {
 	v--
 	degree := 0
@@ -240,24 +385,27 @@ func (g *Graph) SortedByDegrees() []int {
 	return degrees
 }
 
-func (g *Graph) ApproximateVertexCover(logs *string) ([]int, error) {
+// ApproximateVertexCover returns a vertex cover at most twice the minimum. If
+// ctx is cancelled it returns the vertices chosen so far, which need not
+// cover every edge, together with ctx.Err().
+func (g *Graph) ApproximateVertexCover(ctx context.Context, t Tracer) ([]int, error) {
 	errWrap := func(err error) error {
 		return fmt.Errorf("ApproximateVertexCover: %w", err)
 	}
-	var doLogs bool
-	if logs != nil {
-		doLogs = true
-	}
+	tr := newTracer(t, "cover")
+	rep := reporter{ctx: ctx}
 
 	if g.Directed {
 		return nil, errWrap(ErrDirectedGraph)
 	}
-	var log strings.Builder
 	edgesInternal := g.Edges
 	cover := make(map[int]struct{})
 
 	// Step 1: while there are edges in graph...
 	for len(edgesInternal) > 0 {
+		if err := rep.step("covering edges", len(g.Edges)-len(edgesInternal), len(g.Edges)); err != nil {
+			return sortedVertices(cover), err
+		}
 		// pick an edge
 		edge := edgesInternal[0]
 		u, v := edge[0], edge[1]
@@ -265,10 +413,6 @@ func (g *Graph) ApproximateVertexCover(logs *string) ([]int, error) {
 		cover[u] = struct{}{}
 		cover[v] = struct{}{}
 
-		if doLogs {
-			log.WriteString(fmt.Sprintf("Adding vertices %d and %d to the cover and removing them from the clone.\n", u, v))
-		}
-
 		// Step 3: remove both endpoints and their adjecent edges
 		var updatedEdges [][2]int
 		for _, e := range edgesInternal {
@@ -278,24 +422,21 @@ func (g *Graph) ApproximateVertexCover(logs *string) ([]int, error) {
 		}
 
 		edgesInternal = updatedEdges
-		if doLogs {
-			log.WriteString(fmt.Sprintf("Current cover set: %v\n", cover))
-			log.WriteString(fmt.Sprintf("Remaining edges after removal: %v\n", edgesInternal))
-		}
-
+		tr.emit(EventCoverAdd, float64(len(edgesInternal)), u, v)
 	}
 
-	result := make([]int, 0, len(cover))
-	for vertex := range cover {
+	result := sortedVertices(cover)
+	tr.emit(EventResult, float64(len(result)), result...)
+	return result, nil
+}
+
+func sortedVertices(set map[int]struct{}) []int {
+	result := make([]int, 0, len(set))
+	for vertex := range set {
 		result = append(result, vertex)
 	}
 	sort.Ints(result)
-
-	if doLogs {
-		log.WriteString(fmt.Sprintf("Approximate Vertex Cover: %v\n", result))
-		*logs = log.String()
-	}
-	return result, nil
+	return result
 }
 
 func (g *Graph) String() string {
@@ -347,173 +488,168 @@ func (g *Graph) ToDOT(filename string) error {
 	}
 	defer file.Close()
 
-	// Ustaw nagłówek grafu w zależności od typu (skierowany/nieskierowany)
-	var graphType string
-	var edgeConnector string
-	if g.Directed {
-		graphType = "digraph"
-		edgeConnector = "->"
-	} else {
-		graphType = "graph"
-		edgeConnector = "--"
-	}
-
-	fmt.Fprintf(file, "%s G {\n", graphType)
-
-	// Dodanie krawędzi
-	for i := range g.AdjMatrix {
-		for j := range g.AdjMatrix[i] {
-			if g.AdjMatrix[i][j] == 1 {
-				indexingFixI := i + 1
-				indexingFixJ := j + 1
-				if g.Directed || i <= j {
-					if g.Weighted {
-						fmt.Fprintf(file, "  %d %s %d [label=\"%.2f\"];\n", indexingFixI, edgeConnector, indexingFixJ, g.WeightMatrix[i][j])
-					} else {
-						fmt.Fprintf(file, "  %d %s %d;\n", indexingFixI, edgeConnector, indexingFixJ)
-					}
-				}
-			}
-		}
-	}
-
-	// Zakończenie
-	fmt.Fprintf(file, "}\n")
-	return nil
+	return g.WriteDOT(file)
 }
 
-func (g *Graph) Inspect() *Graph {
-	fmt.Println(g)
+func (g *Graph) Inspect(w io.Writer) *Graph {
+	fmt.Fprintln(w, g)
 	return g
 }
 
-func (g *Graph) InspectEdges() *Graph {
-	fmt.Println(g.Edges)
+func (g *Graph) InspectEdges(w io.Writer) *Graph {
+	fmt.Fprintln(w, g.Edges)
 	return g
 }
 
-func (g *Graph) isMetric() bool {
+// triangleViolation finds vertices i, j, k (1-based) with
+// d(i, j) > d(i, k) + d(k, j) among existing edges and returns by how much
+// the inequality is violated. ok is false if the weights are metric.
+func (g *Graph) triangleViolation(rep reporter) (i, j, k int, excess float64, ok bool, err error) {
 	for i := 0; i < len(g.WeightMatrix); i++ {
+		if err := rep.step("checking triangle inequality", i, len(g.WeightMatrix)); err != nil {
+			return 0, 0, 0, 0, false, err
+		}
 		for j := 0; j < len(g.WeightMatrix); j++ {
-			if i == j || g.WeightMatrix[i][j] == 0 {
+			if i == j || g.AdjMatrix[i][j] == 0 {
 				continue // Ignoruj przypadki, gdy i == j lub brak krawędzi
 			}
 			for k := 0; k < len(g.WeightMatrix); k++ {
-				if i == k || j == k || g.WeightMatrix[i][k] == 0 || g.WeightMatrix[k][j] == 0 {
+				if i == k || j == k || g.AdjMatrix[i][k] == 0 || g.AdjMatrix[k][j] == 0 {
 					continue // Ignoruj przypadki, gdy krawędzie są nieistniejące
 				}
 				// Sprawdź zasadę trójkąta
-				if g.WeightMatrix[i][j] > g.WeightMatrix[i][k]+g.WeightMatrix[k][j] {
-					// Debugowanie: Wypisz szczegóły naruszenia
-					fmt.Printf("Triangle inequality violated: d(%d, %d) = %.2f, d(%d, %d) + d(%d, %d) = %.2f\n",
-						i+1, j+1, g.WeightMatrix[i][j],
-						i+1, k+1, k+1, j+1, g.WeightMatrix[i][k]+g.WeightMatrix[k][j])
-					return false
+				if via := g.WeightMatrix[i][k] + g.WeightMatrix[k][j]; g.WeightMatrix[i][j] > via {
+					return i + 1, j + 1, k + 1, g.WeightMatrix[i][j] - via, true, nil
 				}
 			}
 		}
 	}
-	return true
+	return 0, 0, 0, 0, false, nil
 }
 
+// GetCompletedWeightMatrix is CompletedWeightMatrix without cancellation.
+// The error about a negative cycle is dropped; check for negative weights
+// first where it matters.
 func (g *Graph) GetCompletedWeightMatrix() [][]float64 {
-	n := len(g.WeightMatrix)
-
-	// Stwórz kopię WeightMatrix
-	dist := make([][]float64, n)
-	for i := range dist {
-		dist[i] = make([]float64, n)
-		for j := range dist[i] {
-			if i == j {
-				dist[i][j] = 0 // Odległość do siebie
-			} else if g.WeightMatrix[i][j] > 0 {
-				dist[i][j] = g.WeightMatrix[i][j] // Istniejąca waga
-			} else {
-				dist[i][j] = 1e9 // Brak krawędzi = "nieskończoność"
-			}
-		}
+	dist, _ := g.CompletedWeightMatrix(context.Background())
+	return dist
+}
+
+// CompletedWeightMatrix returns the shortest-path distances between all
+// pairs of vertices (see AllPairsShortestPaths), with +Inf between vertices
+// that are not connected. Negative weights are allowed; if they form a
+// negative cycle the error wraps ErrNegativeCycle. In an undirected graph
+// every negative edge is such a cycle. If ctx is cancelled it returns nil and
+// ctx.Err().
+func (g *Graph) CompletedWeightMatrix(ctx context.Context) ([][]float64, error) {
+	return g.completedWeightMatrix(ctx, nil)
+}
+
+func (g *Graph) completedWeightMatrix(ctx context.Context, progress ProgressFunc) ([][]float64, error) {
+	ap, err := g.AllPairsShortestPaths(ctx, APSPOptions{Progress: progress})
+	if ap == nil {
+		return nil, err
 	}
+	return ap.Dist, err
+}
 
-	// Algorytm Floyd-Warshall
-	for k := 0; k < n; k++ {
-		for i := 0; i < n; i++ {
-			for j := 0; j < n; j++ {
-				if dist[i][j] > dist[i][k]+dist[k][j] {
-					dist[i][j] = dist[i][k] + dist[k][j]
-				}
-			}
+// negativeEdge returns an edge with negative weight, if there is one.
+func (g *Graph) negativeEdge() (u, v int, ok bool) {
+	if !g.Weighted {
+		return 0, 0, false
+	}
+	for _, e := range g.Edges {
+		if g.WeightMatrix[e[0]-1][e[1]-1] < 0 {
+			return e[0], e[1], true
 		}
 	}
+	return 0, 0, false
+}
 
-	return dist
+// requireNonNegative fails with ErrNegativeWeight for algorithms that are
+// only defined for non-negative weights.
+func (g *Graph) requireNonNegative(algorithm string) error {
+	if u, v, ok := g.negativeEdge(); ok {
+		return fmt.Errorf("%s: %w on edge (%d, %d): %g", algorithm, ErrNegativeWeight, u, v, g.WeightMatrix[u-1][v-1])
+	}
+	return nil
 }
 
-func (g *Graph) Christofides(logs *string) ([]int, error) {
+// Christofides returns a tour of a metric graph built like Christofides'
+// tour from an MST, but the odd-degree vertices are matched greedily rather
+// than by a minimum-weight perfect matching, so the 1.5 guarantee does not
+// hold. A disconnected graph has no tour; the error wraps ErrDisconnected.
+// It has no partial tour to offer, so if ctx is cancelled it returns nil and
+// ctx.Err(). Progress goes to progress, which may be nil.
+func (g *Graph) Christofides(ctx context.Context, t Tracer, progress ProgressFunc) ([]int, error) {
 	if !g.Weighted {
 		return nil, fmt.Errorf("Christofides algorithm requires a weighted graph")
 	}
 	if g.Directed {
 		return nil, fmt.Errorf("Christofides algorithm requires an undirected graph")
 	}
+	if len(g.AdjMatrix) == 0 {
+		return nil, nil
+	}
+	tr := newTracer(t, "tsp")
+	rep := newReporter(ctx, progress, "tsp")
 
-	// Warunek trójkąta
-	if !g.isMetric() {
-		return nil, fmt.Errorf("Graph does not satisfy the triangle inequality")
+	// Metryka wymaga nieujemnych wag
+	if err := g.requireNonNegative("Christofides algorithm"); err != nil {
+		return nil, err
 	}
 
-	var log strings.Builder
-	doLogs := logs != nil
+	// Warunek trójkąta
+	i, j, k, excess, ok, err := g.triangleViolation(rep)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		tr.emit(EventTriangleViolation, excess, i, j, k)
+		return nil, fmt.Errorf("Graph does not satisfy the triangle inequality: d(%d, %d) > d(%d, %d) + d(%d, %d)", i, j, i, k, k, j)
+	}
 
 	// Uzupełnij brakujące wagi
-	completeWeightMatrix := g.GetCompletedWeightMatrix()
+	completeWeightMatrix, err := g.completedWeightMatrix(ctx, progress)
+	if err != nil {
+		return nil, err
+	}
 
 	// Minimalne drzewo rozpinające
-	if doLogs {
-		log.WriteString("Step 1: Generating Minimum Spanning Tree (MST) using Kruskal's algorithm.\n")
-	}
-	mstEdges, err := g.KruskalMST(&log)
+	tr.phase("Step 1: Generating Minimum Spanning Tree (MST) using Kruskal's algorithm.")
+	mstEdges, err := g.kruskal(tr, rep)
 	if err != nil {
 		return nil, err
 	}
-	if doLogs {
-		log.WriteString(fmt.Sprintf("MST Edges: %v\n", mstEdges))
+	if len(mstEdges) < len(g.AdjMatrix)-1 {
+		return nil, fmt.Errorf("Christofides algorithm: %w", ErrDisconnected)
 	}
 
 	// Wierzchołki o nieparzystym stopniu
-	if doLogs {
-		log.WriteString("Step 2: Finding odd-degree vertices in the MST.\n")
-	}
-	oddVertices := g.FindOddDegreeVertices(mstEdges, &log)
-	if doLogs {
-		log.WriteString(fmt.Sprintf("Odd vertices: %v\n", oddVertices))
-	}
+	tr.phase("Step 2: Finding odd-degree vertices in the MST.")
+	oddVertices := findOddDegreeVertices(mstEdges, tr)
 
 	// Minimalne dopasowanie wierzchołków
-	if doLogs {
-		log.WriteString("Step 3: Finding minimum weight matching for odd-degree vertices.\n")
-	}
-	matching := g.FindMinimumWeightMatching(oddVertices, completeWeightMatrix, &log)
-	if doLogs {
-		log.WriteString(fmt.Sprintf("Matching edges: %v\n", matching))
-	}
+	tr.phase("Step 3: Finding minimum weight matching for odd-degree vertices.")
+	matching := findMinimumWeightMatching(oddVertices, completeWeightMatrix, tr)
 
 	// Cykl Eulera -> Cykl Hamiltona
-	if doLogs {
-		log.WriteString("Step 4: Creating Eulerian circuit and converting to Hamiltonian cycle.\n")
-	}
-	hamiltonianCycle := g.CreateHamiltonianCycle(mstEdges, matching, &log)
-	if doLogs {
-		log.WriteString(fmt.Sprintf("Hamiltonian cycle: %v\n", hamiltonianCycle))
-	}
+	tr.phase("Step 4: Creating Eulerian circuit and converting to Hamiltonian cycle.")
+	hamiltonianCycle := createHamiltonianCycle(mstEdges, matching)
 
-	if logs != nil {
-		*logs = log.String()
+	cost := 0.0
+	for i := range hamiltonianCycle {
+		cost += completeWeightMatrix[hamiltonianCycle[i]-1][hamiltonianCycle[(i+1)%len(hamiltonianCycle)]-1]
 	}
-
+	tr.emit(EventResult, cost, hamiltonianCycle...)
 	return hamiltonianCycle, nil
 }
 
-func (g *Graph) FindOddDegreeVertices(edges [][2]int, logs *strings.Builder) []int {
+func (g *Graph) FindOddDegreeVertices(edges [][2]int, t Tracer) []int {
+	return findOddDegreeVertices(edges, newTracer(t, "odd-vertices"))
+}
+
+func findOddDegreeVertices(edges [][2]int, tr tracer) []int {
 	degree := make(map[int]int)
 	for _, edge := range edges {
 		degree[edge[0]]++
@@ -524,16 +660,22 @@ func (g *Graph) FindOddDegreeVertices(edges [][2]int, logs *strings.Builder) []i
 	for vertex, deg := range degree {
 		if deg%2 != 0 {
 			oddVertices = append(oddVertices, vertex)
-			if logs != nil {
-				logs.WriteString(fmt.Sprintf("Vertex %d has odd degree (%d).\n", vertex, deg))
-			}
 		}
 	}
+	// Kolejność z mapy jest losowa; sortujemy, żeby wynik i zdarzenia były powtarzalne
+	sort.Ints(oddVertices)
+	for _, vertex := range oddVertices {
+		tr.emit(EventOddVertex, float64(degree[vertex]), vertex)
+	}
 
 	return oddVertices
 }
 
-func (g *Graph) FindMinimumWeightMatching(oddVertices []int, weightMatrix [][]float64, logs *strings.Builder) [][2]int {
+func (g *Graph) FindMinimumWeightMatching(oddVertices []int, weightMatrix [][]float64, t Tracer) [][2]int {
+	return findMinimumWeightMatching(oddVertices, weightMatrix, newTracer(t, "matching"))
+}
+
+func findMinimumWeightMatching(oddVertices []int, weightMatrix [][]float64, tr tracer) [][2]int {
 	var matching [][2]int
 	visited := make(map[int]bool)
 
@@ -542,21 +684,17 @@ func (g *Graph) FindMinimumWeightMatching(oddVertices []int, weightMatrix [][]fl
 			continue
 		}
 
-		minWeight := 1e9
-		var bestMatch int
-		if logs != nil {
-			logs.WriteString(fmt.Sprintf("Processing vertex %d. Available pairs: %v\n", oddVertices[i], oddVertices[i+1:]))
-		}
+		minWeight := math.Inf(1)
+		bestMatch := 0
 		for j := i + 1; j < len(oddVertices); j++ {
 			if visited[oddVertices[j]] {
 				continue
 			}
 
+			// Niepołączone pary mają wagę +Inf, ale i tak trzeba kogoś wybrać
 			weight := weightMatrix[oddVertices[i]-1][oddVertices[j]-1]
-			if logs != nil {
-				logs.WriteString(fmt.Sprintf("Checking pair (%d, %d) with weight %.2f.\n", oddVertices[i], oddVertices[j], weight))
-			}
-			if weight < minWeight {
+			tr.emit(EventMatchCandidate, weight, oddVertices[i], oddVertices[j])
+			if bestMatch == 0 || weight < minWeight {
 				minWeight = weight
 				bestMatch = oddVertices[j]
 			}
@@ -565,15 +703,17 @@ func (g *Graph) FindMinimumWeightMatching(oddVertices []int, weightMatrix [][]fl
 		matching = append(matching, [2]int{oddVertices[i], bestMatch})
 		visited[oddVertices[i]] = true
 		visited[bestMatch] = true
-		if logs != nil {
-			logs.WriteString(fmt.Sprintf("Matched vertices %d and %d with weight %.2f.\n", oddVertices[i], bestMatch, minWeight))
-		}
+		tr.emit(EventMatched, minWeight, oddVertices[i], bestMatch)
 	}
 
 	return matching
 }
 
-func (g *Graph) CreateHamiltonianCycle(mstEdges [][2]int, matching [][2]int, logs *strings.Builder) []int {
+func (g *Graph) CreateHamiltonianCycle(mstEdges [][2]int, matching [][2]int) []int {
+	return createHamiltonianCycle(mstEdges, matching)
+}
+
+func createHamiltonianCycle(mstEdges [][2]int, matching [][2]int) []int {
 	// Połączenie MST i dopasowania
 	var combinedEdges [][2]int
 	combinedEdges = append(combinedEdges, mstEdges...)
@@ -599,10 +739,6 @@ func (g *Graph) CreateHamiltonianCycle(mstEdges [][2]int, matching [][2]int, log
 	}
 	dfs(1) // Rozpocznij DFS od dowolnego wierzchołka
 
-	if logs != nil {
-		logs.WriteString(fmt.Sprintf("Generated Hamiltonian cycle: %v\n", cycle))
-	}
-
 	return cycle
 }
 
@@ -649,44 +785,91 @@ func (uf *UnionFind) Union(x, y int) {
 	}
 }
 
-func (g *Graph) KruskalMST(logs *strings.Builder) ([][2]int, error) {
-	type edge struct {
-		u, v   int
-		weight float64
+// KruskalMST returns a minimum spanning tree of an undirected graph. If the
+// graph is disconnected it returns a minimum spanning forest and an error
+// wrapping ErrDisconnected. If ctx is cancelled it returns the edges chosen
+// so far and ctx.Err().
+func (g *Graph) KruskalMST(ctx context.Context, t Tracer) ([][2]int, error) {
+	forest, err := g.MinimumSpanningTree(ctx, MSTOptions{Algorithm: MSTKruskal}, t)
+	if forest == nil {
+		return nil, err
 	}
-	var edges []edge
+	return forest.Edges, err
+}
+
+// edgeWeight is the weight of edge (u, v), or 1 in an unweighted graph.
+func (g *Graph) edgeWeight(u, v int) float64 {
+	if !g.Weighted {
+		return 1
+	}
+	return g.WeightMatrix[u-1][v-1]
+}
+
+// flattenEdges lists the endpoints of edges pairwise, as EventResult
+// carries them.
+func flattenEdges(edges [][2]int) []int {
+	flat := make([]int, 0, 2*len(edges))
+	for _, e := range edges {
+		flat = append(flat, e[0], e[1])
+	}
+	return flat
+}
+
+func (g *Graph) kruskal(tr tracer, rep reporter) ([][2]int, error) {
+	var edges []weightedEdge
 
 	// Zbierz wszystkie krawędzie z wagami
-	for i := 0; i < len(g.WeightMatrix); i++ {
-		for j := i + 1; j < len(g.WeightMatrix[i]); j++ {
-			if g.AdjMatrix[i][j] == 1 {
-				edges = append(edges, edge{i, j, g.WeightMatrix[i][j]})
+	for i := 0; i < len(g.AdjMatrix); i++ {
+		for j := i + 1; j < len(g.AdjMatrix[i]); j++ {
+			if g.AdjMatrix[i][j] == 0 {
+				continue
 			}
+			edges = append(edges, weightedEdge{i, j, g.edgeWeight(i+1, j+1)})
 		}
 	}
 
+	chosen, err := kruskal(tr, rep, len(g.AdjMatrix), edges)
+	mstEdges := make([][2]int, len(chosen))
+	for i, e := range chosen {
+		mstEdges[i] = [2]int{e.u + 1, e.v + 1} // Indeksy zaczynają się od 1
+	}
+	return mstEdges, err
+}
+
+// weightedEdge is an undirected edge between 0-based vertices.
+type weightedEdge struct {
+	u, v   int
+	weight float64
+}
+
+// kruskal returns the edges of a minimum spanning forest of the n vertices,
+// sorting edges in place.
+func kruskal(tr tracer, rep reporter, n int, edges []weightedEdge) ([]weightedEdge, error) {
 	// Posortuj krawędzie według wag
 	sort.Slice(edges, func(i, j int) bool {
 		return edges[i].weight < edges[j].weight
 	})
 
 	// UnionFind do zarządzania zbiorami
-	uf := NewUnionFind(len(g.AdjMatrix))
+	uf := NewUnionFind(n)
 
-	var mstEdges [][2]int
+	var mstEdges []weightedEdge
 
 	// Przetwarzanie krawędzi
-	for _, e := range edges {
+	for i, e := range edges {
+		if i%checkEvery == 0 {
+			if err := rep.step("Kruskal", i, len(edges)); err != nil {
+				return mstEdges, err
+			}
+		}
 		if uf.Find(e.u) != uf.Find(e.v) {
 			uf.Union(e.u, e.v)
-			mstEdges = append(mstEdges, [2]int{e.u + 1, e.v + 1}) // Indeksy zaczynają się od 1
-			if logs != nil {
-				logs.WriteString(fmt.Sprintf("Added edge (%d, %d) to MST.\n", e.u+1, e.v+1))
-			}
+			mstEdges = append(mstEdges, e)
+			tr.emit(EventMSTEdge, e.weight, e.u+1, e.v+1)
 		}
 
 		// Jeśli mamy wystarczającą liczbę krawędzi, kończymy
-		if len(mstEdges) == len(g.AdjMatrix)-1 {
+		if len(mstEdges) == n-1 {
 			break
 		}
 	}
@@ -694,53 +877,83 @@ func (g *Graph) KruskalMST(logs *strings.Builder) ([][2]int, error) {
 	return mstEdges, nil
 }
 
-func (g *Graph) ChinesePostmanProblem(logs *string) ([]int, float64, error) {
-	var log strings.Builder
-	doLogs := logs != nil
+// ChinesePostmanProblem returns a closed walk through every edge and its
+// cost. Odd-degree vertices are paired up and the edges of the shortest path
+// between each pair are duplicated in g, so the walk only uses real edges.
+// The walk starts at the first edge; if some edge lies in another component
+// the error wraps ErrDisconnected. If ctx is cancelled it returns nil and
+// ctx.Err(). Progress goes to progress, which may be nil.
+func (g *Graph) ChinesePostmanProblem(ctx context.Context, t Tracer, progress ProgressFunc) ([]int, float64, error) {
+	tr := newTracer(t, "cpp")
 
 	if g.Directed {
 		return nil, 0, fmt.Errorf("problem chińskiego listonosza nie obsługuje grafów skierowanych")
 	}
+	if !g.Weighted {
+		return nil, 0, fmt.Errorf("problem chińskiego listonosza wymaga grafu ważonego")
+	}
+	// Krawędź o ujemnej wadze opłaca się przechodzić w nieskończoność
+	if err := g.requireNonNegative("ChinesePostmanProblem"); err != nil {
+		return nil, 0, err
+	}
 
-	// Uzupełnij brakujące wagi
-	completeWeightMatrix := g.GetCompletedWeightMatrix()
-	fmt.Println(completeWeightMatrix)
+	if len(g.AdjMatrix) == 0 {
+		return nil, 0, nil
+	}
+	// Obchód zaczyna się przy pierwszej krawędzi, więc wszystkie krawędzie
+	// muszą leżeć w jej składowej
+	start := 0
+	if len(g.Edges) > 0 {
+		start = g.Edges[0][0] - 1
+		uf := NewUnionFind(len(g.AdjMatrix))
+		for _, e := range g.Edges {
+			uf.Union(e[0]-1, e[1]-1)
+		}
+		for _, e := range g.Edges {
+			if uf.Find(e[0]-1) != uf.Find(start) {
+				return nil, 0, fmt.Errorf("ChinesePostmanProblem: %w: edge (%d, %d) is not reachable from vertex %d", ErrDisconnected, e[0], e[1], start+1)
+			}
+		}
+	}
+
+	// Najkrótsze ścieżki między wszystkimi parami, razem z samymi ścieżkami
+	paths, err := g.AllPairsShortestPaths(ctx, APSPOptions{Progress: progress})
+	if err != nil {
+		return nil, 0, err
+	}
 
 	// Krok 1: Znajdź wierzchołki o nieparzystym stopniu
-	oddVertices := g.FindOddDegreeVertices(g.Edges, &log)
-	if len(oddVertices) == 0 && doLogs {
-		log.WriteString("Graf jest już Eulerowski.\n")
+	tr.phase("Step 1: Finding odd-degree vertices.")
+	oddVertices := findOddDegreeVertices(g.Edges, tr)
+	if len(oddVertices) == 0 {
+		tr.phase("The graph is already Eulerian.")
 	}
 
 	// Krok 2: Dopasowanie wierzchołków o nieparzystym stopniu
 	if len(oddVertices) > 0 {
-		if doLogs {
-			log.WriteString(fmt.Sprintf("Odd-degree vertices: %v\n", oddVertices))
-		}
-		matching := g.FindMinimumWeightMatching(oddVertices, completeWeightMatrix, &log)
-		for _, edge := range matching {
-			u, v := edge[0]-1, edge[1]-1
-			g.WeightMatrix[u][v] += g.WeightMatrix[u][v]
-			g.WeightMatrix[v][u] += g.WeightMatrix[v][u]
-			g.AdjMatrix[u][v]++
-			g.AdjMatrix[v][u]++
-			g.Edges = append(g.Edges, [2]int{u + 1, v + 1})
-
-			if doLogs {
-				log.WriteString(fmt.Sprintf("Added edge (%d, %d) with weight %.2f to the graph.\n", u+1, v+1, g.WeightMatrix[u][v]))
-				log.WriteString(fmt.Sprintf("Updated adjacency matrix: %v\n", g.AdjMatrix))
-				log.WriteString(fmt.Sprintf("Updated weight matrix:\n"))
-				for _, row := range g.WeightMatrix {
-					log.WriteString(fmt.Sprintf("%v\n", row))
-				}
+		tr.phase("Step 2: Matching odd-degree vertices.")
+		matching := findMinimumWeightMatching(oddVertices, paths.Dist, tr)
+		for _, pair := range matching {
+			// Para odpowiada najkrótszej ścieżce; dublujemy każdą jej krawędź
+			path := paths.Path(pair[0], pair[1])
+			if path == nil {
+				return nil, 0, fmt.Errorf("ChinesePostmanProblem: vertices %d and %d are not connected", pair[0], pair[1])
+			}
+			for i := 0; i+1 < len(path); i++ {
+				u, v := path[i]-1, path[i+1]-1
+				g.AdjMatrix[u][v]++
+				g.AdjMatrix[v][u]++
+				g.Edges = append(g.Edges, [2]int{u + 1, v + 1})
+				tr.emit(EventEdgeDuplicated, g.WeightMatrix[u][v], u+1, v+1)
 			}
 		}
 	}
 
 	// Krok 3: Znajdź cykl Eulera
-	eulerianCircuit := g.FleurysAlgorithm()
-	if doLogs {
-		log.WriteString(fmt.Sprintf("Eulerian circuit: %v\n", eulerianCircuit))
+	tr.phase("Step 3: Finding an Eulerian circuit.")
+	eulerianCircuit, err := g.fleury(start, tr, newReporter(ctx, progress, "cpp"))
+	if err != nil {
+		return nil, 0, err
 	}
 
 	// Krok 4: Oblicz koszt
@@ -750,20 +963,29 @@ func (g *Graph) ChinesePostmanProblem(logs *string) ([]int, float64, error) {
 		totalCost += g.WeightMatrix[u][v]
 	}
 
-	if logs != nil {
-		*logs = log.String()
-	}
-
+	tr.emit(EventResult, totalCost, eulerianCircuit...)
 	return eulerianCircuit, totalCost, nil
 }
 
 // Znajdowanie cyklu Eulera za pomocą algorytmu Fleury’ego
-func (g *Graph) FleurysAlgorithm() []int {
+// FleurysAlgorithm returns an Eulerian circuit starting at vertex 1, removing
+// the edges it traverses from the adjacency matrix. If ctx is cancelled it
+// returns the part of the circuit closed so far and ctx.Err().
+func (g *Graph) FleurysAlgorithm(ctx context.Context, t Tracer) ([]int, error) {
+	return g.fleury(0, newTracer(t, "fleury"), reporter{ctx: ctx})
+}
+
+// fleury walks the circuit from start (0-based).
+func (g *Graph) fleury(start int, tr tracer, rep reporter) ([]int, error) {
 	circuit := []int{}
-	current := 0 // Start od dowolnego wierzchołka
-	stack := []int{current}
+	stack := []int{start}
 
-	for len(stack) > 0 {
+	for steps := 0; len(stack) > 0; steps++ {
+		if steps%checkEvery == 0 {
+			if err := rep.step("Eulerian circuit", len(circuit), 0); err != nil {
+				return circuit, err
+			}
+		}
 		node := stack[len(stack)-1]
 		hasEdges := false
 		for i := 0; i < len(g.AdjMatrix[node]); i++ {
@@ -773,6 +995,7 @@ func (g *Graph) FleurysAlgorithm() []int {
 				g.AdjMatrix[node][i]--
 				g.AdjMatrix[i][node]--
 				stack = append(stack, i)
+				tr.emit(EventEulerStep, 0, node+1, i+1)
 				break
 			}
 		}
@@ -783,5 +1006,5 @@ func (g *Graph) FleurysAlgorithm() []int {
 		}
 	}
 
-	return circuit
+	return circuit, nil
 }
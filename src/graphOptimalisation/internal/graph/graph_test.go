@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestEdgeExistenceIgnoresWeight(t *testing.T) {
+	for _, w := range []float64{0, -2, 3.5} {
+		graph := NewGraph(3, false, true)
+		graph.AddEdge(1, 2, w)
+		if !graph.HasEdge(1, 2) || !graph.HasEdge(2, 1) || graph.HasEdge(2, 3) {
+			t.Errorf("weight %g: HasEdge reports the wrong edges", w)
+		}
+		if got, err := graph.GetWeight(2, 1); err != nil || got != w {
+			t.Errorf("weight %g: GetWeight = %g, %v", w, got, err)
+		}
+		if len(graph.Edges) != 1 {
+			t.Errorf("weight %g: edges %v, want one", w, graph.Edges)
+		}
+	}
+}
+
+func TestMissingEdge(t *testing.T) {
+	graph := NewGraph(3, true, true)
+	graph.AddEdge(1, 2, 4)
+	if _, err := graph.GetWeight(2, 1); !errors.Is(err, ErrNoEdge) {
+		t.Errorf("GetWeight of a reversed arc: got %v, want %v", err, ErrNoEdge)
+	}
+	if err := graph.SetWeight(2, 3, 1); !errors.Is(err, ErrNoEdge) {
+		t.Errorf("SetWeight: got %v, want %v", err, ErrNoEdge)
+	}
+	if err := graph.SetResource(2, 3, 1); !errors.Is(err, ErrNoEdge) {
+		t.Errorf("SetResource: got %v, want %v", err, ErrNoEdge)
+	}
+	// Zmiana wagi na 0 nie usuwa krawędzi
+	if err := graph.SetWeight(1, 2, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !graph.HasEdge(1, 2) {
+		t.Error("edge 1-2 disappeared after setting its weight to 0")
+	}
+	graph.RemoveEdge(1, 2)
+	if _, err := graph.GetWeight(1, 2); !errors.Is(err, ErrNoEdge) {
+		t.Errorf("GetWeight after RemoveEdge: got %v, want %v", err, ErrNoEdge)
+	}
+}
+
+func TestZeroWeightDistances(t *testing.T) {
+	graph := NewGraph(4, false, true)
+	graph.AddEdge(1, 2, 0).AddEdge(2, 3, 2)
+	dist, err := graph.CompletedWeightMatrix(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dist[0][1] != 0 || dist[0][2] != 2 {
+		t.Errorf("distances from 1 are %v, want 0 to 2 and 2 to 3", dist[0])
+	}
+	if !math.IsInf(dist[0][3], 1) {
+		t.Errorf("distance 1 -> 4 is %g, want +Inf", dist[0][3])
+	}
+}
+
+func TestNegativeWeights(t *testing.T) {
+	ctx := context.Background()
+	graph := NewGraph(3, false, true)
+	graph.AddEdge(1, 2, 1).AddEdge(2, 3, -1).AddEdge(1, 3, 2)
+
+	if _, err := graph.CompletedWeightMatrix(ctx); !errors.Is(err, ErrNegativeCycle) {
+		t.Errorf("CompletedWeightMatrix: got %v, want %v", err, ErrNegativeCycle)
+	}
+	if _, err := graph.Christofides(ctx, nil, nil); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("Christofides: got %v, want %v", err, ErrNegativeWeight)
+	}
+	if _, _, err := graph.OptimalTour(ctx, nil); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("OptimalTour: got %v, want %v", err, ErrNegativeWeight)
+	}
+	work := graph.Clone()
+	if _, _, err := work.ChinesePostmanProblem(ctx, nil, nil); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("ChinesePostmanProblem: got %v, want %v", err, ErrNegativeWeight)
+	}
+
+	// W grafie skierowanym bez cyklu ujemne wagi są w porządku
+	dag := NewGraph(3, true, true)
+	dag.AddEdge(1, 2, 1).AddEdge(2, 3, -3).AddEdge(1, 3, 2)
+	dist, err := dag.CompletedWeightMatrix(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dist[0][2] != -2 {
+		t.Errorf("distance 1 -> 3 is %g, want -2", dist[0][2])
+	}
+}
@@ -0,0 +1,213 @@
+package graph
+
+// HeapKind selects the priority queue used by Dijkstra.
+type HeapKind int
+
+const (
+	// BinaryHeap is an indexed binary heap: O(log n) for every operation.
+	BinaryHeap HeapKind = iota
+	// PairingHeap has O(1) insert and amortised o(log n) decrease-key, which
+	// pays off on dense graphs with many relaxations.
+	PairingHeap
+)
+
+func (k HeapKind) String() string {
+	switch k {
+	case BinaryHeap:
+		return "binary"
+	case PairingHeap:
+		return "pairing"
+	}
+	return "unknown"
+}
+
+// priorityQueue holds vertices (0-based) keyed by tentative distance. Every
+// vertex is in the queue at most once; push on a queued vertex lowers its key.
+type priorityQueue interface {
+	push(v int, key float64)
+	pop() (v int, key float64)
+	len() int
+}
+
+func newPriorityQueue(kind HeapKind, n int) priorityQueue {
+	if kind == PairingHeap {
+		return newPairingHeap(n)
+	}
+	return newBinaryHeap(n)
+}
+
+// binaryHeap keeps the position of every vertex so its key can be lowered
+// in place.
+type binaryHeap struct {
+	items []int
+	keys  []float64
+	pos   []int // -1 poza kopcem
+}
+
+func newBinaryHeap(n int) *binaryHeap {
+	h := &binaryHeap{keys: make([]float64, n), pos: make([]int, n)}
+	for i := range h.pos {
+		h.pos[i] = -1
+	}
+	return h
+}
+
+func (h *binaryHeap) len() int { return len(h.items) }
+
+func (h *binaryHeap) push(v int, key float64) {
+	if h.pos[v] < 0 {
+		h.pos[v] = len(h.items)
+		h.items = append(h.items, v)
+	} else if key >= h.keys[v] {
+		return
+	}
+	h.keys[v] = key
+	h.up(h.pos[v])
+}
+
+func (h *binaryHeap) pop() (int, float64) {
+	v := h.items[0]
+	last := len(h.items) - 1
+	h.swap(0, last)
+	h.items = h.items[:last]
+	h.pos[v] = -1
+	if last > 0 {
+		h.down(0)
+	}
+	return v, h.keys[v]
+}
+
+func (h *binaryHeap) swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.pos[h.items[i]] = i
+	h.pos[h.items[j]] = j
+}
+
+func (h *binaryHeap) less(i, j int) bool {
+	return h.keys[h.items[i]] < h.keys[h.items[j]]
+}
+
+func (h *binaryHeap) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(i, parent) {
+			return
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+func (h *binaryHeap) down(i int) {
+	for {
+		smallest := i
+		for _, c := range [2]int{2*i + 1, 2*i + 2} {
+			if c < len(h.items) && h.less(c, smallest) {
+				smallest = c
+			}
+		}
+		if smallest == i {
+			return
+		}
+		h.swap(i, smallest)
+		i = smallest
+	}
+}
+
+// pairingNode is a node of a pairing heap: a multiway tree stored as
+// leftmost child and sibling pointers. prev is the parent for a leftmost
+// child and the left sibling otherwise.
+type pairingNode struct {
+	v                    int
+	key                  float64
+	child, sibling, prev *pairingNode
+}
+
+type pairingHeap struct {
+	root  *pairingNode
+	nodes []*pairingNode // nil poza kopcem
+	size  int
+}
+
+func newPairingHeap(n int) *pairingHeap {
+	return &pairingHeap{nodes: make([]*pairingNode, n)}
+}
+
+func (h *pairingHeap) len() int { return h.size }
+
+// meld links two roots, the larger key becoming the leftmost child.
+func meld(a, b *pairingNode) *pairingNode {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if b.key < a.key {
+		a, b = b, a
+	}
+	b.prev = a
+	b.sibling = a.child
+	if a.child != nil {
+		a.child.prev = b
+	}
+	a.child = b
+	a.sibling, a.prev = nil, nil
+	return a
+}
+
+func (h *pairingHeap) push(v int, key float64) {
+	node := h.nodes[v]
+	if node == nil {
+		node = &pairingNode{v: v, key: key}
+		h.nodes[v] = node
+		h.root = meld(h.root, node)
+		h.size++
+		return
+	}
+	if key >= node.key {
+		return
+	}
+	node.key = key
+	if node == h.root {
+		return
+	}
+	// Odcinamy poddrzewo od rodzica i łączymy je z korzeniem
+	if node.prev.child == node {
+		node.prev.child = node.sibling
+	} else {
+		node.prev.sibling = node.sibling
+	}
+	if node.sibling != nil {
+		node.sibling.prev = node.prev
+	}
+	node.sibling, node.prev = nil, nil
+	h.root = meld(h.root, node)
+}
+
+func (h *pairingHeap) pop() (int, float64) {
+	root := h.root
+	h.nodes[root.v] = nil
+	h.size--
+
+	// Dwuprzebiegowe łączenie dzieci: parami od lewej, potem od prawej
+	var pairs []*pairingNode
+	for c := root.child; c != nil; {
+		a := c
+		b := a.sibling
+		if b == nil {
+			a.prev, a.sibling = nil, nil
+			pairs = append(pairs, a)
+			break
+		}
+		c = b.sibling
+		a.prev, a.sibling, b.prev, b.sibling = nil, nil, nil, nil
+		pairs = append(pairs, meld(a, b))
+	}
+	var merged *pairingNode
+	for i := len(pairs) - 1; i >= 0; i-- {
+		merged = meld(pairs[i], merged)
+	}
+	h.root = merged
+	return root.v, root.key
+}
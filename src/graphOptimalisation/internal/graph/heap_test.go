@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	for _, kind := range []HeapKind{BinaryHeap, PairingHeap} {
+		t.Run(kind.String(), func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			const n = 200
+			q := newPriorityQueue(kind, n)
+			if q.len() != 0 {
+				t.Fatalf("new queue has length %d", q.len())
+			}
+			keys := make([]float64, n)
+			for v := range keys {
+				keys[v] = float64(rng.Intn(1000))
+				q.push(v, keys[v])
+			}
+			// Obniżanie klucza; wyższy klucz dla wierzchołka w kolejce nic nie zmienia
+			for i := 0; i < n; i++ {
+				v := rng.Intn(n)
+				key := float64(rng.Intn(1000))
+				q.push(v, key)
+				keys[v] = min(keys[v], key)
+			}
+			if q.len() != n {
+				t.Fatalf("queue has length %d after decreasing keys, want %d", q.len(), n)
+			}
+
+			want := append([]float64(nil), keys...)
+			sort.Float64s(want)
+			seen := make([]bool, n)
+			for i := range want {
+				v, key := q.pop()
+				if seen[v] {
+					t.Fatalf("vertex %d popped twice", v)
+				}
+				seen[v] = true
+				if key != want[i] || key != keys[v] {
+					t.Fatalf("pop %d gave vertex %d with key %g, want key %g (vertex key %g)", i, v, key, want[i], keys[v])
+				}
+			}
+			if q.len() != 0 {
+				t.Errorf("queue has length %d after popping everything", q.len())
+			}
+		})
+	}
+}
+
+func TestPriorityQueueReinsert(t *testing.T) {
+	for _, kind := range []HeapKind{BinaryHeap, PairingHeap} {
+		q := newPriorityQueue(kind, 3)
+		q.push(0, 5)
+		q.push(1, 3)
+		if v, _ := q.pop(); v != 1 {
+			t.Fatalf("%s: popped %d, want 1", kind, v)
+		}
+		// Zdjęty wierzchołek może wrócić do kolejki z dowolnym kluczem
+		q.push(1, 7)
+		q.push(2, 6)
+		for _, want := range []int{0, 2, 1} {
+			if v, _ := q.pop(); v != want {
+				t.Errorf("%s: popped %d, want %d", kind, v, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+)
+
+// jsonGraph is the JSON form of a graph:
+//
+//	{"vertices": 3, "directed": false, "weighted": true,
+//	 "edges": [[1, 2, 4.5], [2, 3, 1]], "labels": ["a", "b", "c"]}
+//
+// Edges are [u, v], [u, v, weight] or [u, v, weight, resource] with 1-based
+// vertices; resource is the second weight kept in Graph.Resources.
+type jsonGraph struct {
+	Vertices int          `json:"vertices"`
+	Directed bool         `json:"directed"`
+	Weighted bool         `json:"weighted"`
+	Edges    [][]float64  `json:"edges"`
+	Labels   []string     `json:"labels,omitempty"`
+	Coords   [][2]float64 `json:"coords,omitempty"`
+}
+
+// DecodeJSON builds a graph from its JSON form. Vertices may be omitted, in
+// which case the largest vertex number in edges is used; weighted defaults
+// to true if any edge has a third element.
+func DecodeJSON(data []byte, opts ReadOptions) (Graph, error) {
+	var jg jsonGraph
+	if err := json.Unmarshal(data, &jg); err != nil {
+		return Graph{}, err
+	}
+
+	if jg.Vertices < 0 {
+		return Graph{}, parseErr(0, ErrInvalidHeader, "vertices must be non-negative, got %d", jg.Vertices)
+	}
+	n := jg.Vertices
+	weighted := jg.Weighted
+	resources := 0
+	for i, e := range jg.Edges {
+		if len(e) < 2 || len(e) > 4 {
+			return Graph{}, parseErr(0, ErrMissingField, "edge %d: expected [u, v], [u, v, weight] or [u, v, weight, resource]", i)
+		}
+		// Porównujemy jeszcze jako float64: int(1e300) nie ma określonej wartości
+		for _, x := range e[:2] {
+			switch {
+			case x != math.Trunc(x) || x < 1:
+				return Graph{}, parseErr(0, ErrInvalidVertex, "edge %d: %v is not a positive integer", i, x)
+			case jg.Vertices > 0 && x > float64(jg.Vertices):
+				return Graph{}, parseErr(0, ErrVertexOutOfRange, "edge %d: %v exceeds vertices=%d", i, x, jg.Vertices)
+			case x > math.MaxInt32:
+				return Graph{}, parseErr(0, ErrVertexOutOfRange, "edge %d: vertex %v is too large", i, x)
+			}
+			if jg.Vertices == 0 {
+				n = max(n, int(x))
+			}
+		}
+		if len(e) >= 3 {
+			weighted = true
+		}
+		if len(e) == 4 {
+			resources++
+		}
+	}
+	if jg.Labels != nil && len(jg.Labels) != n {
+		return Graph{}, parseErr(0, ErrHeaderMismatch, "%d labels for %d vertices", len(jg.Labels), n)
+	}
+	if jg.Coords != nil && len(jg.Coords) != n {
+		return Graph{}, parseErr(0, ErrHeaderMismatch, "%d coordinates for %d vertices", len(jg.Coords), n)
+	}
+
+	if err := checkVertexLimit(opts.MaxVertices, n, 0); err != nil {
+		return Graph{}, err
+	}
+	graph := NewGraph(n, jg.Directed, weighted)
+	for i, e := range jg.Edges {
+		if weighted && len(e) < 3 {
+			return Graph{}, parseErr(0, ErrInconsistentWeight, "edge %d has no weight", i)
+		}
+		if resources > 0 && len(e) != 4 {
+			return Graph{}, parseErr(0, ErrInconsistentWeight, "edge %d has no resource", i)
+		}
+		if weighted {
+			graph.AddEdge(int(e[0]), int(e[1]), e[2])
+		} else {
+			graph.AddEdge(int(e[0]), int(e[1]))
+		}
+		if len(e) == 4 {
+			graph.SetResource(int(e[0]), int(e[1]), e[3])
+		}
+	}
+	graph.Labels = jg.Labels
+	graph.Coords = jg.Coords
+	return graph, nil
+}
+
+// ReadJSON reads a graph in the format described by DecodeJSON.
+func ReadJSON(r io.Reader, opts ReadOptions) (Graph, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Graph{}, err
+	}
+	return DecodeJSON(data, opts)
+}
+
+// EncodeJSON encodes g in the format read by DecodeJSON.
+func (g *Graph) EncodeJSON() ([]byte, error) {
+	jg := jsonGraph{
+		Vertices: len(g.AdjMatrix),
+		Directed: g.Directed,
+		Weighted: g.Weighted,
+		Edges:    make([][]float64, 0, len(g.Edges)),
+		Labels:   g.Labels,
+		Coords:   g.Coords,
+	}
+	for _, e := range g.Edges {
+		edge := []float64{float64(e[0]), float64(e[1])}
+		switch {
+		case g.Weighted:
+			edge = append(edge, g.WeightMatrix[e[0]-1][e[1]-1])
+		case g.Resources != nil:
+			edge = append(edge, 1) // zasób jest czwartym elementem
+		}
+		if g.Resources != nil {
+			edge = append(edge, g.Resources[e[0]-1][e[1]-1])
+		}
+		jg.Edges = append(jg.Edges, edge)
+	}
+	return json.Marshal(jg)
+}
+
+// WriteJSON writes g in the format read by ReadJSON.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	data, err := g.EncodeJSON()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
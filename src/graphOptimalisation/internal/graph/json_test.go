@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	g, err := DecodeJSON([]byte(`{"directed": true, "edges": [[1, 2, 3], [2, 4, 1.5]]}`), ReadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.AdjMatrix) != 4 || !g.Directed || !g.Weighted || g.WeightMatrix[1][3] != 1.5 {
+		t.Errorf("got %d vertices, directed=%t, weighted=%t", len(g.AdjMatrix), g.Directed, g.Weighted)
+	}
+}
+
+func TestDecodeJSONErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"negative vertices", `{"vertices": -2, "edges": []}`, ErrInvalidHeader},
+		{"vertex out of range", `{"vertices": 2, "edges": [[1, 3]]}`, ErrVertexOutOfRange},
+		{"fractional vertex", `{"edges": [[1, 2.5]]}`, ErrInvalidVertex},
+		{"huge vertex", `{"edges": [[1, 1e300]]}`, ErrVertexOutOfRange},
+		{"huge vertex with a count", `{"vertices": 3, "edges": [[1e300, 1]]}`, ErrVertexOutOfRange},
+		{"short edge", `{"edges": [[1]]}`, ErrMissingField},
+		{"labels mismatch", `{"vertices": 2, "edges": [], "labels": ["a"]}`, ErrHeaderMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeJSON([]byte(tt.input), ReadOptions{})
+			var perr *ParseError
+			if !errors.Is(err, tt.want) || !errors.As(err, &perr) {
+				t.Errorf("got %v, want a ParseError wrapping %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,449 @@
+package graph
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrNoPath = errors.New("no path")
+
+// Path is a walk through the graph with its total weight.
+type Path struct {
+	Vertices []int   `json:"vertices"`
+	Cost     float64 `json:"cost"`
+}
+
+// pathCost sums the weights along vertices.
+func (g *Graph) pathCost(vertices []int) float64 {
+	cost := 0.0
+	for i := 0; i+1 < len(vertices); i++ {
+		cost += g.edgeWeight(vertices[i], vertices[i+1])
+	}
+	return cost
+}
+
+func pathKey(vertices []int) string {
+	var sb strings.Builder
+	for _, v := range vertices {
+		sb.WriteString(strconv.Itoa(v))
+		sb.WriteByte(',')
+	}
+	return sb.String()
+}
+
+// KShortestPaths returns up to k loopless paths from source to target in
+// order of increasing cost (Yen's algorithm, O(kn) runs of Dijkstra). It
+// requires non-negative weights. If ctx is cancelled it returns the paths
+// found so far and ctx.Err(). A k below 1 gives no paths. Progress goes to
+// progress, which may be nil.
+func (g *Graph) KShortestPaths(ctx context.Context, source, target, k int, progress ProgressFunc) ([]Path, error) {
+	for _, v := range []int{source, target} {
+		if err := g.CheckVertex(v); err != nil {
+			return nil, fmt.Errorf("KShortestPaths: %w", err)
+		}
+	}
+	if err := g.requireNonNegative("KShortestPaths"); err != nil {
+		return nil, err
+	}
+	if k < 1 {
+		return nil, nil
+	}
+	rep := newReporter(ctx, progress, "yen")
+	adj := g.arcs()
+	quiet := reporter{ctx: ctx}
+
+	first := search(quiet, adj, source, target, BinaryHeap, nil).PathTo(target)
+	if first == nil {
+		return nil, ErrNoPath
+	}
+	found := []Path{{first, g.pathCost(first)}}
+	var candidates []Path
+	seen := map[string]bool{pathKey(first): true}
+
+	for len(found) < k {
+		if err := rep.step("finding paths", len(found), k); err != nil {
+			return found, err
+		}
+		prev := found[len(found)-1].Vertices
+		for i := 0; i+1 < len(prev); i++ {
+			spur, root := prev[i], prev[:i+1]
+
+			// Blokujemy krawędzie, którymi znalezione ścieżki o tym samym korzeniu
+			// wychodzą z wierzchołka spur, i wierzchołki korzenia przed nim
+			bannedArc := make(map[[2]int]bool)
+			for _, p := range found {
+				if len(p.Vertices) > i+1 && equalInts(p.Vertices[:i+1], root) {
+					bannedArc[[2]int{p.Vertices[i], p.Vertices[i+1]}] = true
+				}
+			}
+			bannedVertex := make([]bool, len(adj))
+			for _, v := range root[:i] {
+				bannedVertex[v-1] = true
+			}
+			filtered := make([][]arc, len(adj))
+			for u := range adj {
+				if bannedVertex[u] {
+					continue
+				}
+				for _, a := range adj[u] {
+					if !bannedVertex[a.to] && !bannedArc[[2]int{u + 1, a.to + 1}] {
+						filtered[u] = append(filtered[u], a)
+					}
+				}
+			}
+
+			spurPath := search(quiet, filtered, spur, target, BinaryHeap, nil).PathTo(target)
+			if spurPath == nil {
+				continue
+			}
+			total := append(append([]int(nil), root[:i]...), spurPath...)
+			if key := pathKey(total); !seen[key] {
+				seen[key] = true
+				candidates = append(candidates, Path{total, g.pathCost(total)})
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		// Najtańszy kandydat, przy remisie ten z mniejszą liczbą krawędzi
+		sort.SliceStable(candidates, func(a, b int) bool {
+			if candidates[a].Cost != candidates[b].Cost {
+				return candidates[a].Cost < candidates[b].Cost
+			}
+			return len(candidates[a].Vertices) < len(candidates[b].Vertices)
+		})
+		found = append(found, candidates[0])
+		candidates = candidates[1:]
+	}
+	return found, nil
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sidetrack is an arc off the shortest-path tree and how much taking it
+// costs compared with staying on the tree.
+type sidetrack struct {
+	from, to int // 1-based
+	delta    float64
+}
+
+// walkState is a node of the implicit tree of walks Eppstein's algorithm
+// explores: the walk takes the sidetracks on the chain ending at last, where
+// last is list[index] of the sidetracks reachable from some vertex.
+type walkState struct {
+	cost   float64
+	list   []sidetrack
+	index  int
+	parent *walkState
+}
+
+type walkQueue []*walkState
+
+func (q walkQueue) Len() int           { return len(q) }
+func (q walkQueue) Less(i, j int) bool { return q[i].cost < q[j].cost }
+func (q walkQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *walkQueue) Push(x any)        { *q = append(*q, x.(*walkState)) }
+func (q *walkQueue) Pop() any {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
+
+// KShortestWalks returns the k cheapest walks from source to target, which
+// may repeat vertices and edges, in order of increasing cost. Like
+// Eppstein's algorithm it computes one tree of shortest paths into target and
+// describes every other walk by the sidetracks (arcs off the tree) it takes,
+// enumerating them best-first. It requires non-negative weights. If ctx is
+// cancelled it returns the walks found so far and ctx.Err(). A k below 1
+// gives no walks. Progress goes to progress, which may be nil.
+func (g *Graph) KShortestWalks(ctx context.Context, source, target, k int, progress ProgressFunc) ([]Path, error) {
+	for _, v := range []int{source, target} {
+		if err := g.CheckVertex(v); err != nil {
+			return nil, fmt.Errorf("KShortestWalks: %w", err)
+		}
+	}
+	if err := g.requireNonNegative("KShortestWalks"); err != nil {
+		return nil, err
+	}
+	if k < 1 {
+		return nil, nil
+	}
+	rep := newReporter(ctx, progress, "eppstein")
+	n := len(g.AdjMatrix)
+	adj := g.arcs()
+
+	// Drzewo najkrótszych ścieżek do celu: Dijkstra na odwróconym grafie
+	reversed := make([][]arc, n)
+	for u := range adj {
+		for _, a := range adj[u] {
+			reversed[a.to] = append(reversed[a.to], arc{u, a.weight})
+		}
+	}
+	tree := search(reporter{ctx: ctx}, reversed, target, 0, BinaryHeap, nil)
+	dist, next := tree.Dist, tree.Pred
+	if math.IsInf(dist[source-1], 1) {
+		return nil, ErrNoPath
+	}
+
+	// own[u]: posortowane objazdy wychodzące z u
+	own := make([][]sidetrack, n)
+	for u := range adj {
+		if math.IsInf(dist[u], 1) {
+			continue
+		}
+		for _, a := range adj[u] {
+			if math.IsInf(dist[a.to], 1) || next[u] == a.to+1 {
+				continue
+			}
+			own[u] = append(own[u], sidetrack{u + 1, a.to + 1, a.weight + dist[a.to] - dist[u]})
+		}
+		sort.Slice(own[u], func(i, j int) bool { return own[u][i].delta < own[u][j].delta })
+	}
+	// reachable(u): objazdy z wierzchołków na ścieżce drzewa od u do celu,
+	// liczone leniwie przez scalanie z listą następnika
+	memo := make([][]sidetrack, n)
+	done := make([]bool, n)
+	var reachable func(u int) []sidetrack
+	reachable = func(u int) []sidetrack {
+		if done[u] {
+			return memo[u]
+		}
+		var rest []sidetrack
+		if next[u] != 0 {
+			rest = reachable(next[u] - 1)
+		}
+		memo[u], done[u] = mergeSidetracks(own[u], rest), true
+		return memo[u]
+	}
+
+	walks := []Path{{g.treeWalk(source, target, next), dist[source-1]}}
+	queue := &walkQueue{}
+	if list := reachable(source - 1); len(list) > 0 {
+		heap.Push(queue, &walkState{cost: dist[source-1] + list[0].delta, list: list})
+	}
+	for len(walks) < k && queue.Len() > 0 {
+		if err := rep.step("enumerating walks", len(walks), k); err != nil {
+			return walks, err
+		}
+		state := heap.Pop(queue).(*walkState)
+		walks = append(walks, Path{g.sidetrackWalk(source, target, next, state), state.cost})
+
+		last := state.list[state.index]
+		// Ten sam łańcuch z następnym objazdem z tej samej listy
+		if state.index+1 < len(state.list) {
+			heap.Push(queue, &walkState{
+				cost:   state.cost - last.delta + state.list[state.index+1].delta,
+				list:   state.list,
+				index:  state.index + 1,
+				parent: state.parent,
+			})
+		}
+		// Łańcuch dłuższy o jeden objazd osiągalny z końca ostatniego
+		if list := reachable(last.to - 1); len(list) > 0 {
+			heap.Push(queue, &walkState{cost: state.cost + list[0].delta, list: list, parent: state})
+		}
+	}
+	return walks, nil
+}
+
+func mergeSidetracks(a, b []sidetrack) []sidetrack {
+	if len(a) == 0 {
+		return b
+	}
+	out := make([]sidetrack, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i].delta <= b[j].delta {
+			out = append(out, a[i])
+			i++
+		} else {
+			out = append(out, b[j])
+			j++
+		}
+	}
+	out = append(out, a[i:]...)
+	return append(out, b[j:]...)
+}
+
+// treeWalk follows the tree of shortest paths from u to target.
+func (g *Graph) treeWalk(u, target int, next []int) []int {
+	walk := []int{u}
+	for u != target {
+		u = next[u-1]
+		walk = append(walk, u)
+	}
+	return walk
+}
+
+// sidetrackWalk expands a walk state into the vertices of the walk.
+func (g *Graph) sidetrackWalk(source, target int, next []int, state *walkState) []int {
+	var chain []sidetrack
+	for s := state; s != nil; s = s.parent {
+		chain = append(chain, s.list[s.index])
+	}
+	walk := []int{source}
+	for i := len(chain) - 1; i >= 0; i-- {
+		// Po drzewie do początku objazdu, potem sam objazd
+		st := chain[i]
+		walk = append(walk, g.treeWalk(walk[len(walk)-1], st.from, next)[1:]...)
+		walk = append(walk, st.to)
+	}
+	return append(walk, g.treeWalk(walk[len(walk)-1], target, next)[1:]...)
+}
+
+// ConstrainedPath is a path that respects a limit on a second weight.
+type ConstrainedPath struct {
+	Path
+	Resource float64 `json:"resource"`
+}
+
+// ConstrainedShortestPath finds the cheapest path from source to target whose
+// total resource (the second edge weight in g.Resources, e.g. travel time) is
+// at most limit. Both weights must be non-negative. The problem is NP-hard;
+// this is an exact label-setting search that keeps only Pareto-optimal
+// (cost, resource) labels per vertex and prunes with lower bounds on both
+// weights, so it is fast when few labels survive. If no path fits the limit
+// the error is ErrNoPath. If ctx is cancelled it returns the zero value and
+// ctx.Err(). Progress goes to progress, which may be nil.
+func (g *Graph) ConstrainedShortestPath(ctx context.Context, source, target int, limit float64, progress ProgressFunc) (ConstrainedPath, error) {
+	for _, v := range []int{source, target} {
+		if err := g.CheckVertex(v); err != nil {
+			return ConstrainedPath{}, fmt.Errorf("ConstrainedShortestPath: %w", err)
+		}
+	}
+	if g.Resources == nil {
+		return ConstrainedPath{}, fmt.Errorf("ConstrainedShortestPath: %w", ErrNoResources)
+	}
+	if err := g.requireNonNegative("ConstrainedShortestPath"); err != nil {
+		return ConstrainedPath{}, err
+	}
+	n := len(g.AdjMatrix)
+	adj := g.arcs()
+	res := make([][]float64, n)
+	for u := range adj {
+		res[u] = make([]float64, len(adj[u]))
+		for i, a := range adj[u] {
+			r := g.Resources[u][a.to]
+			if r < 0 || math.IsNaN(r) {
+				return ConstrainedPath{}, fmt.Errorf("ConstrainedShortestPath: %w on edge (%d, %d): %g", ErrNegativeWeight, u+1, a.to+1, r)
+			}
+			res[u][i] = r
+		}
+	}
+
+	// Dolne ograniczenia: najkrótsze odległości do celu osobno po koszcie i po zasobie
+	costTo := make([][]arc, n)
+	resTo := make([][]arc, n)
+	for u := range adj {
+		for i, a := range adj[u] {
+			costTo[a.to] = append(costTo[a.to], arc{u, a.weight})
+			resTo[a.to] = append(resTo[a.to], arc{u, res[u][i]})
+		}
+	}
+	quiet := reporter{ctx: ctx}
+	minCost := search(quiet, costTo, target, 0, BinaryHeap, nil).Dist
+	minRes := search(quiet, resTo, target, 0, BinaryHeap, nil).Dist
+	if minRes[source-1] > limit {
+		return ConstrainedPath{}, ErrNoPath
+	}
+
+	labels := make([][]*rcspLabel, n) // niezdominowane etykiety każdego wierzchołka
+	dominated := func(l *rcspLabel) bool {
+		for _, o := range labels[l.vertex] {
+			if o.cost <= l.cost && o.resource <= l.resource {
+				return true
+			}
+		}
+		return false
+	}
+
+	rep := newReporter(ctx, progress, "constrained-path")
+	queue := &labelQueue{}
+	start := &rcspLabel{vertex: source - 1}
+	labels[source-1] = append(labels[source-1], start)
+	heap.Push(queue, labelItem{start, minCost[source-1]})
+	for popped := 0; queue.Len() > 0; popped++ {
+		if popped%checkEvery == 0 {
+			if err := rep.step("label setting", popped, 0); err != nil {
+				return ConstrainedPath{}, err
+			}
+		}
+		l := heap.Pop(queue).(labelItem).label
+		if l.removed {
+			continue
+		}
+		if l.vertex == target-1 {
+			// Kolejka jest uporządkowana po koszcie plus dolnym ograniczeniu,
+			// więc pierwsza etykieta celu jest optymalna
+			var vertices []int
+			for x := l; x != nil; x = x.prev {
+				vertices = append(vertices, x.vertex+1)
+			}
+			for i, j := 0, len(vertices)-1; i < j; i, j = i+1, j-1 {
+				vertices[i], vertices[j] = vertices[j], vertices[i]
+			}
+			return ConstrainedPath{Path{vertices, l.cost}, l.resource}, nil
+		}
+		for i, a := range adj[l.vertex] {
+			next := &rcspLabel{vertex: a.to, cost: l.cost + a.weight, resource: l.resource + res[l.vertex][i], prev: l}
+			if next.resource+minRes[a.to] > limit || dominated(next) {
+				continue
+			}
+			// Usuń etykiety, które nowa dominuje
+			kept := labels[a.to][:0]
+			for _, o := range labels[a.to] {
+				if !(next.cost <= o.cost && next.resource <= o.resource) {
+					kept = append(kept, o)
+				} else {
+					o.removed = true // wpis w kolejce zostanie pominięty
+				}
+			}
+			labels[a.to] = append(kept, next)
+			heap.Push(queue, labelItem{next, next.cost + minCost[a.to]})
+		}
+	}
+	return ConstrainedPath{}, ErrNoPath
+}
+
+// rcspLabel is a partial path in ConstrainedShortestPath.
+type rcspLabel struct {
+	vertex         int // 0-based
+	cost, resource float64
+	prev           *rcspLabel
+	removed        bool
+}
+
+type labelItem struct {
+	label *rcspLabel
+	key   float64
+}
+
+type labelQueue []labelItem
+
+func (q labelQueue) Len() int           { return len(q) }
+func (q labelQueue) Less(i, j int) bool { return q[i].key < q[j].key }
+func (q labelQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *labelQueue) Push(x any)        { *q = append(*q, x.(labelItem)) }
+func (q *labelQueue) Pop() any {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,233 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/Simikao/graphOptimalisation/internal/generate"
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+// yenExample is the directed graph from the usual presentation of Yen's
+// algorithm, with C..H numbered 1..6.
+func yenExample() g.Graph {
+	graph := g.NewGraph(6, true, true)
+	graph.AddEdge(1, 2, 3).AddEdge(1, 3, 2).AddEdge(2, 4, 4).AddEdge(3, 2, 1).AddEdge(3, 4, 2).
+		AddEdge(3, 5, 3).AddEdge(4, 5, 2).AddEdge(4, 6, 1).AddEdge(5, 6, 2)
+	return graph
+}
+
+func TestKShortestPaths(t *testing.T) {
+	graph := yenExample()
+	got, err := graph.KShortestPaths(context.Background(), 1, 6, 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []g.Path{
+		{Vertices: []int{1, 3, 4, 6}, Cost: 5},
+		{Vertices: []int{1, 3, 5, 6}, Cost: 7},
+		{Vertices: []int{1, 2, 4, 6}, Cost: 8},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d paths, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].Cost != want[i].Cost {
+			t.Errorf("path %d costs %g, want %g", i+1, got[i].Cost, want[i].Cost)
+		}
+	}
+	// Ścieżki 2 i 3 mają jednoznaczne koszty, więc i wierzchołki
+	if !equalInts(got[0].Vertices, want[0].Vertices) || !equalInts(got[1].Vertices, want[1].Vertices) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestKShortestWalks(t *testing.T) {
+	// Po jedynej krawędzi można chodzić tam i z powrotem
+	graph := g.NewGraph(2, false, true)
+	graph.AddEdge(1, 2, 1)
+	got, err := graph.KShortestWalks(context.Background(), 1, 2, 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []g.Path{
+		{Vertices: []int{1, 2}, Cost: 1},
+		{Vertices: []int{1, 2, 1, 2}, Cost: 3},
+		{Vertices: []int{1, 2, 1, 2, 1, 2}, Cost: 5},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d walks, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].Cost != want[i].Cost || !equalInts(got[i].Vertices, want[i].Vertices) {
+			t.Errorf("walk %d is %v, want %v", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestKShortestNoPath(t *testing.T) {
+	ctx := context.Background()
+	graph := g.NewGraph(4, false, true)
+	graph.AddEdge(1, 2, 1).AddEdge(3, 4, 1)
+	if _, err := graph.KShortestPaths(ctx, 1, 4, 2, nil); !errors.Is(err, g.ErrNoPath) {
+		t.Errorf("KShortestPaths: got %v, want %v", err, g.ErrNoPath)
+	}
+	if _, err := graph.KShortestWalks(ctx, 1, 4, 2, nil); !errors.Is(err, g.ErrNoPath) {
+		t.Errorf("KShortestWalks: got %v, want %v", err, g.ErrNoPath)
+	}
+	empty := g.NewGraph(0, false, false)
+	if _, err := empty.KShortestPaths(ctx, 1, 1, 1, nil); !errors.Is(err, g.ErrVertexOutOfRange) {
+		t.Errorf("empty graph: got %v, want %v", err, g.ErrVertexOutOfRange)
+	}
+}
+
+func TestKShortestNonPositiveK(t *testing.T) {
+	ctx := context.Background()
+	graph := g.NewGraph(3, false, true)
+	graph.AddEdge(1, 2, 1).AddEdge(2, 3, 1).AddEdge(1, 3, 3)
+	for _, k := range []int{0, -1} {
+		if paths, err := graph.KShortestPaths(ctx, 1, 3, k, nil); err != nil || len(paths) != 0 {
+			t.Errorf("KShortestPaths k=%d: got %v, %v, want no paths", k, paths, err)
+		}
+		if walks, err := graph.KShortestWalks(ctx, 1, 3, k, nil); err != nil || len(walks) != 0 {
+			t.Errorf("KShortestWalks k=%d: got %v, %v, want no walks", k, walks, err)
+		}
+	}
+}
+
+// simplePathCosts lists the costs of all loopless paths from source to
+// target, cheapest first.
+func simplePathCosts(graph *g.Graph, source, target int) []float64 {
+	var costs []float64
+	visited := make([]bool, len(graph.AdjMatrix)+1)
+	var dfs func(u int, cost float64)
+	dfs = func(u int, cost float64) {
+		if u == target {
+			costs = append(costs, cost)
+			return
+		}
+		visited[u] = true
+		for v := 1; v <= len(graph.AdjMatrix); v++ {
+			if !visited[v] && graph.HasEdge(u, v) {
+				dfs(v, cost+graph.WeightMatrix[u-1][v-1])
+			}
+		}
+		visited[u] = false
+	}
+	dfs(source, 0)
+	sort.Float64s(costs)
+	return costs
+}
+
+func TestKShortestPathsMatchBruteForce(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 15; seed++ {
+		gen := generate.New(seed)
+		gen.Weighted, gen.Directed = true, seed%2 == 0
+		graph, err := gen.ErdosRenyi(7, 0.4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		const k = 6
+		want := simplePathCosts(&graph, 1, 7)
+		paths, err := graph.KShortestPaths(ctx, 1, 7, k, nil)
+		if len(want) == 0 {
+			if !errors.Is(err, g.ErrNoPath) {
+				t.Errorf("seed %d: got %v, want %v", seed, err, g.ErrNoPath)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if len(paths) != min(k, len(want)) {
+			t.Fatalf("seed %d: got %d paths, want %d", seed, len(paths), min(k, len(want)))
+		}
+		seen := map[string]bool{}
+		for i, p := range paths {
+			if p.Cost != want[i] {
+				t.Errorf("seed %d: path %d costs %g, want %g", seed, i+1, p.Cost, want[i])
+			}
+			key := fmt.Sprint(p.Vertices)
+			if seen[key] {
+				t.Errorf("seed %d: path %v repeated", seed, p.Vertices)
+			}
+			seen[key] = true
+		}
+	}
+}
+
+func TestKShortestWalksMatchPathsOnDAGs(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 15; seed++ {
+		gen := generate.New(seed)
+		gen.Weighted = true
+		random, err := gen.ErdosRenyi(8, 0.5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// W grafie acyklicznym każdy spacer jest ścieżką prostą
+		graph := acyclic(random)
+		const k = 8
+		paths, perr := graph.KShortestPaths(ctx, 1, 8, k, nil)
+		walks, werr := graph.KShortestWalks(ctx, 1, 8, k, nil)
+		if (perr == nil) != (werr == nil) {
+			t.Fatalf("seed %d: Yen gives %v, Eppstein %v", seed, perr, werr)
+		}
+		if len(paths) != len(walks) {
+			t.Fatalf("seed %d: Yen finds %d paths, Eppstein %d walks", seed, len(paths), len(walks))
+		}
+		for i := range paths {
+			if paths[i].Cost != walks[i].Cost {
+				t.Errorf("seed %d: path %d costs %g, walk %g", seed, i+1, paths[i].Cost, walks[i].Cost)
+			}
+		}
+	}
+}
+
+func TestConstrainedShortestPath(t *testing.T) {
+	// Szybka droga przez 2 jest tania, ale zużywa dużo zasobu
+	graph := g.NewGraph(4, true, true)
+	graph.AddEdge(1, 2, 1).AddEdge(2, 4, 1).AddEdge(1, 3, 3).AddEdge(3, 4, 3)
+	graph.SetResource(1, 2, 5)
+	graph.SetResource(2, 4, 5)
+	graph.SetResource(1, 3, 1)
+	graph.SetResource(3, 4, 1)
+	tests := []struct {
+		limit float64
+		path  []int
+		cost  float64
+	}{
+		{math.Inf(1), []int{1, 2, 4}, 2},
+		{10, []int{1, 2, 4}, 2},
+		{9, []int{1, 3, 4}, 6},
+		{2, []int{1, 3, 4}, 6},
+		{1, nil, 0},
+	}
+	for _, tt := range tests {
+		got, err := graph.ConstrainedShortestPath(context.Background(), 1, 4, tt.limit, nil)
+		if tt.path == nil {
+			if !errors.Is(err, g.ErrNoPath) {
+				t.Errorf("limit %g: got %v, want %v", tt.limit, err, g.ErrNoPath)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("limit %g: %v", tt.limit, err)
+			continue
+		}
+		if got.Cost != tt.cost || !equalInts(got.Vertices, tt.path) || got.Resource > tt.limit {
+			t.Errorf("limit %g: got %v with resource %g, want %v costing %g", tt.limit, got.Vertices, got.Resource, tt.path, tt.cost)
+		}
+	}
+
+	plain := g.NewGraph(2, true, true)
+	plain.AddEdge(1, 2, 1)
+	if _, err := plain.ConstrainedShortestPath(context.Background(), 1, 2, 1, nil); !errors.Is(err, g.ErrNoResources) {
+		t.Errorf("graph without resources: got %v, want %v", err, g.ErrNoResources)
+	}
+}
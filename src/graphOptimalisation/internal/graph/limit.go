@@ -0,0 +1,24 @@
+package graph
+
+import "errors"
+
+var ErrTooManyVertices = errors.New("too many vertices")
+
+// ReadOptions controls the graph readers that have no options of their own.
+type ReadOptions struct {
+	// MaxVertices makes the reader fail with ErrTooManyVertices as soon as a
+	// header or vertex id asks for more vertices, before anything of that
+	// size is allocated. It protects services reading untrusted input, where
+	// a few bytes such as "1 100000000" would otherwise allocate n×n
+	// matrices. Zero means no limit.
+	MaxVertices int
+}
+
+// checkVertexLimit fails if n exceeds max; a max of zero means no limit.
+// line is the line the count comes from, 0 if none.
+func checkVertexLimit(max, n, line int) error {
+	if max > 0 && n > max {
+		return parseErr(line, ErrTooManyVertices, "%d vertices, at most %d allowed", n, max)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMaxVertices(t *testing.T) {
+	tests := []struct {
+		name string
+		read func(r io.Reader, max int) error
+		big  string
+		ok   string
+	}{
+		{"edge list id", func(r io.Reader, max int) error {
+			_, err := ReadEdgeList(r, EdgeListOptions{MaxVertices: max})
+			return err
+		}, "1 100000000\n", "1 2\n"},
+		{"edge list header", func(r io.Reader, max int) error {
+			_, err := ReadEdgeList(r, EdgeListOptions{MaxVertices: max})
+			return err
+		}, "n=100000000\n1 2\n", "n=3\n1 2\n"},
+		{"weight matrix", func(r io.Reader, max int) error {
+			_, err := ReadWeightMatrix(r, MatrixOptions{MaxVertices: max})
+			return err
+		}, strings.Repeat(strings.Repeat("0 ", 11)+"\n", 11), "0 1\n1 0\n"},
+		{"json", readWith(ReadJSON), `{"vertices": 100000000, "edges": []}`, `{"edges": [[1, 2]]}`},
+		{"pajek", readWith(ReadPajek), "*Vertices 100000000\n", "*Vertices 3\n*Edges\n1 2\n"},
+		{"gml", readWith(ReadGML), "graph [ " + gmlNodes(11) + "]", "graph [ " + gmlNodes(2) + "]"},
+		{"dot", readWith(ReadDOT), "graph {\n1 -- 100000000\n}\n", "graph {\n1 -- 2\n}\n"},
+		{"matrix market", readWith(ReadMatrixMarket),
+			"%%MatrixMarket matrix coordinate pattern general\n100000000 100000000 0\n",
+			"%%MatrixMarket matrix coordinate pattern general\n2 2 1\n1 2\n"},
+		{"metis", readWith(ReadMETIS), "100000000 0\n", "2 1\n2\n1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.read(strings.NewReader(tt.big), 10); !errors.Is(err, ErrTooManyVertices) {
+				t.Errorf("large graph: got %v, want %v", err, ErrTooManyVertices)
+			}
+			if err := tt.read(strings.NewReader(tt.ok), 10); err != nil {
+				t.Errorf("small graph: %v", err)
+			}
+			// Bez limitu mały graf też się wczytuje
+			if err := tt.read(strings.NewReader(tt.ok), 0); err != nil {
+				t.Errorf("small graph without a limit: %v", err)
+			}
+		})
+	}
+}
+
+func gmlNodes(n int) string {
+	var sb strings.Builder
+	for v := 1; v <= n; v++ {
+		fmt.Fprintf(&sb, "node [ id %d ] ", v)
+	}
+	return sb.String()
+}
+
+func readWith[T any](read func(io.Reader, ReadOptions) (T, error)) func(io.Reader, int) error {
+	return func(r io.Reader, max int) error {
+		_, err := read(r, ReadOptions{MaxVertices: max})
+		return err
+	}
+}
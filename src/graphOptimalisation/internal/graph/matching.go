@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+)
+
+// Matching is a set of edges no two of which share a vertex.
+type Matching struct {
+	// Mate[v-1] is the vertex matched with v, or 0.
+	Mate []int `json:"mate"`
+	// Edges are the matched pairs.
+	Edges [][2]int `json:"edges"`
+}
+
+// MaximumMatching finds a maximum cardinality matching of an undirected graph
+// with Edmonds' blossom algorithm in O(n³): from every free vertex it grows an
+// alternating tree by BFS, contracting odd cycles (blossoms) into their base,
+// until it reaches another free vertex and augments. Edges are listed as
+// (u, v) with u < v; loops are never matched. If ctx is cancelled it returns
+// the matching found so far and ctx.Err(). Progress goes to progress, which
+// may be nil.
+func (g *Graph) MaximumMatching(ctx context.Context, progress ProgressFunc) (*Matching, error) {
+	if g.Directed {
+		return nil, fmt.Errorf("MaximumMatching: %w", ErrDirectedGraph)
+	}
+	adj := g.undirectedAdjacency()
+	n := len(adj)
+	mate := make([]int, n)
+	for i := range mate {
+		mate[i] = -1
+	}
+	// Skojarzenie zachłanne na start oszczędza większość przeszukiwań
+	for u := range adj {
+		for _, v := range adj[u] {
+			if mate[u] < 0 && mate[v] < 0 && u != v {
+				mate[u], mate[v] = v, u
+			}
+		}
+	}
+
+	parent := make([]int, n) // poprzednik w drzewie naprzemiennym dla wierzchołków nieparzystych
+	base := make([]int, n)   // baza kwiatu zawierającego wierzchołek
+	used := make([]bool, n)  // wierzchołek parzysty (w kolejce)
+	inBlossom := make([]bool, n)
+	onPath := make([]bool, n)
+	var queue []int
+
+	// lca finds the base of the blossom closed by the edge (a, b): the first
+	// common base on the paths from a and b to the root.
+	lca := func(a, b int) int {
+		for i := range onPath {
+			onPath[i] = false
+		}
+		for {
+			a = base[a]
+			onPath[a] = true
+			if mate[a] < 0 {
+				break
+			}
+			a = parent[mate[a]]
+		}
+		for {
+			b = base[b]
+			if onPath[b] {
+				return b
+			}
+			b = parent[mate[b]]
+		}
+	}
+	// markPath marks the blossoms on the path from v down to the base b and
+	// points the parents of its odd vertices the other way round the cycle.
+	markPath := func(v, b, child int) {
+		for base[v] != b {
+			inBlossom[base[v]], inBlossom[base[mate[v]]] = true, true
+			parent[v] = child
+			child = mate[v]
+			v = parent[mate[v]]
+		}
+	}
+	// findPath returns the free vertex at the end of an augmenting path from
+	// root, or -1 if there is none.
+	findPath := func(root int) int {
+		for i := range used {
+			used[i], parent[i], base[i] = false, -1, i
+		}
+		used[root] = true
+		queue = append(queue[:0], root)
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			for _, to := range adj[v] {
+				if base[v] == base[to] || mate[v] == to {
+					continue
+				}
+				if to == root || mate[to] >= 0 && parent[mate[to]] >= 0 {
+					// Krawędź między dwoma parzystymi wierzchołkami zamyka kwiat
+					b := lca(v, to)
+					for i := range inBlossom {
+						inBlossom[i] = false
+					}
+					markPath(v, b, to)
+					markPath(to, b, v)
+					for i := range base {
+						if inBlossom[base[i]] {
+							base[i] = b
+							if !used[i] {
+								used[i] = true
+								queue = append(queue, i)
+							}
+						}
+					}
+				} else if parent[to] < 0 {
+					parent[to] = v
+					if mate[to] < 0 {
+						return to
+					}
+					used[mate[to]] = true
+					queue = append(queue, mate[to])
+				}
+			}
+		}
+		return -1
+	}
+
+	rep := newReporter(ctx, progress, "blossom")
+	var err error
+	for root := range adj {
+		if root%checkEvery == 0 {
+			if err = rep.step("augmenting paths", root, n); err != nil {
+				break
+			}
+		}
+		if mate[root] >= 0 {
+			continue
+		}
+		// Zamieniamy krawędzie wzdłuż ścieżki powiększającej
+		for v := findPath(root); v >= 0; {
+			pv := parent[v]
+			next := mate[pv]
+			mate[v], mate[pv] = pv, v
+			v = next
+		}
+	}
+
+	m := &Matching{Mate: make([]int, n)}
+	for v, w := range mate {
+		m.Mate[v] = w + 1
+		if w > v {
+			m.Edges = append(m.Edges, [2]int{v + 1, w + 1})
+		}
+	}
+	return m, err
+}
@@ -0,0 +1,155 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Simikao/graphOptimalisation/internal/generate"
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+	"github.com/Simikao/graphOptimalisation/internal/validate"
+)
+
+// checkMatching verifies that m is a matching of graph with edges (u, v),
+// u < v, that agree with Mate.
+func checkMatching(t *testing.T, graph *g.Graph, m *g.Matching) {
+	t.Helper()
+	if err := validate.VerifyMatching(graph, m.Edges); err != nil {
+		t.Error(err)
+	}
+	matched := 0
+	for v, w := range m.Mate {
+		if w != 0 {
+			matched++
+			if m.Mate[w-1] != v+1 {
+				t.Errorf("Mate of %d is %d, but Mate of %d is %d", v+1, w, w, m.Mate[w-1])
+			}
+		}
+	}
+	if matched != 2*len(m.Edges) {
+		t.Errorf("%d vertices matched by Mate, %d edges listed", matched, len(m.Edges))
+	}
+	for _, e := range m.Edges {
+		if e[0] >= e[1] || m.Mate[e[0]-1] != e[1] {
+			t.Errorf("edge %v is not (u, v) with u < v matched in Mate", e)
+		}
+	}
+}
+
+// bruteMatching returns the size of a maximum matching by trying, for the
+// first unmatched vertex, to leave it out or match it with each neighbour.
+func bruteMatching(graph *g.Graph) int {
+	n := len(graph.AdjMatrix)
+	used := make([]bool, n+1)
+	var best func(u int) int
+	best = func(u int) int {
+		for u <= n && used[u] {
+			u++
+		}
+		if u > n {
+			return 0
+		}
+		used[u] = true
+		size := best(u + 1)
+		for v := u + 1; v <= n; v++ {
+			if !used[v] && graph.HasEdge(u, v) {
+				used[v] = true
+				size = max(size, 1+best(u+1))
+				used[v] = false
+			}
+		}
+		used[u] = false
+		return size
+	}
+	return best(1)
+}
+
+func petersen() g.Graph {
+	graph := g.NewGraph(10, false, false)
+	for i := 1; i <= 5; i++ {
+		graph.AddEdge(i, i%5+1)       // zewnętrzny pięciokąt
+		graph.AddEdge(i, i+5)         // szprychy
+		graph.AddEdge(i+5, (i+1)%5+6) // wewnętrzny pentagram
+	}
+	return graph
+}
+
+func TestMaximumMatching(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph g.Graph
+		size  int
+	}{
+		{"empty", undirected(0, nil), 0},
+		{"single vertex", undirected(1, nil), 0},
+		{"loop", undirected(2, [][2]int{{1, 1}, {2, 2}}), 0},
+		{"odd cycle", undirected(5, [][2]int{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 1}}), 2},
+		{"triangle with a tail", undirected(4, [][2]int{{1, 2}, {2, 3}, {3, 1}, {3, 4}}), 2},
+		{"triangle inside a path", undirected(7, [][2]int{{2, 3}, {4, 5}, {1, 2}, {3, 4}, {5, 6}, {3, 7}, {2, 7}}), 3},
+		{"two triangles joined", undirected(6, [][2]int{{1, 2}, {2, 3}, {3, 1}, {4, 5}, {5, 6}, {6, 4}, {3, 4}}), 3},
+		{"Petersen graph", petersen(), 5},
+		{"disconnected", undirected(7, [][2]int{{1, 2}, {2, 3}, {3, 1}, {5, 6}}), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := tt.graph.MaximumMatching(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(m.Edges) != tt.size {
+				t.Errorf("matching %v has %d edges, want %d", m.Edges, len(m.Edges), tt.size)
+			}
+			checkMatching(t, &tt.graph, m)
+		})
+	}
+}
+
+func TestMaximumMatchingDirected(t *testing.T) {
+	graph := g.NewGraph(2, true, false)
+	graph.AddEdge(1, 2)
+	if _, err := graph.MaximumMatching(context.Background(), nil); !errors.Is(err, g.ErrDirectedGraph) {
+		t.Errorf("got %v, want %v", err, g.ErrDirectedGraph)
+	}
+}
+
+func TestMaximumMatchingMatchesHopcroftKarp(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 30; seed++ {
+		gen := generate.New(seed)
+		graph, err := gen.Bipartite(int(1+seed%7), int(1+seed%6), 0.1+float64(seed%5)*0.15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hk, err := graph.HopcroftKarp(ctx, nil)
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		m, err := graph.MaximumMatching(ctx, nil)
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if len(m.Edges) != len(hk.Edges) {
+			t.Errorf("seed %d: blossom finds %d edges, Hopcroft-Karp %d", seed, len(m.Edges), len(hk.Edges))
+		}
+		checkMatching(t, &graph, m)
+	}
+}
+
+func TestMaximumMatchingMatchesBruteForce(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 40; seed++ {
+		gen := generate.New(seed)
+		graph, err := gen.ErdosRenyi(int(1+seed%10), 0.15+float64(seed%4)*0.15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := graph.MaximumMatching(ctx, nil)
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if want := bruteMatching(&graph); len(m.Edges) != want {
+			t.Errorf("seed %d: blossom finds %d edges, maximum is %d", seed, len(m.Edges), want)
+		}
+		checkMatching(t, &graph, m)
+	}
+}
@@ -0,0 +1,275 @@
+package graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNotSquare    = errors.New("matrix is not square")
+	ErrNotSymmetric = errors.New("matrix is not symmetric")
+	ErrInvalidEntry = errors.New("invalid matrix entry")
+)
+
+const missingEdgeToken = "inf"
+
+// MatrixOptions controls how matrix files are read.
+type MatrixOptions struct {
+	// Directed forces the graph type. When nil, the graph is directed iff
+	// the matrix is not symmetric.
+	Directed *bool
+	// Delimiter separates entries. Zero detects ',' or '\t' from the first
+	// row and falls back to runs of whitespace.
+	Delimiter rune
+	// MaxVertices limits the size of the matrix as in ReadOptions; zero means
+	// no limit.
+	MaxVertices int
+}
+
+type matrixRow struct {
+	line   int
+	fields []string
+}
+
+// readMatrixRows splits r into rows of fields, skipping blank lines and '#'
+// comments, and checks that the result is square.
+func readMatrixRows(r io.Reader, delim rune, maxVertices int) ([]matrixRow, error) {
+	var rows []matrixRow
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if delim == 0 {
+			switch {
+			case strings.ContainsRune(line, ','):
+				delim = ','
+			case strings.ContainsRune(line, '\t'):
+				delim = '\t'
+			default:
+				delim = ' '
+			}
+		}
+		rows = append(rows, matrixRow{line: lineNo, fields: splitEdgeListLine(line, delim)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := checkVertexLimit(maxVertices, len(rows), 0); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if len(row.fields) != len(rows) {
+			return nil, parseErr(row.line, ErrNotSquare, "row has %d entries, expected %d", len(row.fields), len(rows))
+		}
+	}
+	return rows, nil
+}
+
+func isMissingToken(s string) bool {
+	switch strings.ToLower(s) {
+	case "-", "inf", "+inf", "infinity", "∞":
+		return true
+	}
+	return false
+}
+
+// graphFromMatrix builds a graph from a square matrix where present[i][j]
+// marks an edge and weights (if non-nil) holds its weight.
+func graphFromMatrix(present [][]bool, weights [][]float64, rows []matrixRow, opts MatrixOptions) (Graph, error) {
+	n := len(present)
+	// Pierwsza para (i, j), dla której macierz nie jest symetryczna
+	ai, aj := -1, -1
+	for i := 0; i < n && ai < 0; i++ {
+		for j := i + 1; j < n; j++ {
+			if present[i][j] != present[j][i] || (weights != nil && present[i][j] && weights[i][j] != weights[j][i]) {
+				ai, aj = i, j
+				break
+			}
+		}
+	}
+
+	directed := ai >= 0
+	if opts.Directed != nil {
+		directed = *opts.Directed
+		if !directed && ai >= 0 {
+			return Graph{}, parseErr(rows[ai].line, ErrNotSymmetric, "entry (%d, %d) differs from (%d, %d)", ai+1, aj+1, aj+1, ai+1)
+		}
+	}
+
+	graph := NewGraph(n, directed, weights != nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if !present[i][j] || (!directed && j < i) {
+				continue
+			}
+			if weights != nil {
+				graph.AddEdge(i+1, j+1, weights[i][j])
+			} else {
+				graph.AddEdge(i+1, j+1)
+			}
+		}
+	}
+	return graph, nil
+}
+
+// ReadAdjacencyMatrix reads a square 0/1 matrix, one row per line.
+func ReadAdjacencyMatrix(r io.Reader, opts MatrixOptions) (Graph, error) {
+	rows, err := readMatrixRows(r, opts.Delimiter, opts.MaxVertices)
+	if err != nil {
+		return Graph{}, err
+	}
+
+	present := make([][]bool, len(rows))
+	for i, row := range rows {
+		present[i] = make([]bool, len(rows))
+		for j, field := range row.fields {
+			switch field {
+			case "0":
+			case "1":
+				present[i][j] = true
+			default:
+				return Graph{}, parseErr(row.line, ErrInvalidEntry, "column %d: expected 0 or 1, got %q", j+1, field)
+			}
+		}
+	}
+	return graphFromMatrix(present, nil, rows, opts)
+}
+
+// ReadWeightMatrix reads a square matrix of edge weights (e.g. a distance
+// table). Missing edges are written as "inf" or "-"; zeros on the diagonal
+// are ignored, any other number is an edge of that weight.
+func ReadWeightMatrix(r io.Reader, opts MatrixOptions) (Graph, error) {
+	rows, err := readMatrixRows(r, opts.Delimiter, opts.MaxVertices)
+	if err != nil {
+		return Graph{}, err
+	}
+
+	n := len(rows)
+	present := make([][]bool, n)
+	weights := make([][]float64, n)
+	for i, row := range rows {
+		present[i] = make([]bool, n)
+		weights[i] = make([]float64, n)
+		for j, field := range row.fields {
+			if isMissingToken(field) {
+				continue
+			}
+			w, err := strconv.ParseFloat(field, 64)
+			if err != nil || math.IsNaN(w) || math.IsInf(w, 0) {
+				return Graph{}, parseErr(row.line, ErrInvalidWeight, "column %d: %q is not a finite number", j+1, field)
+			}
+			if i == j && w == 0 {
+				continue
+			}
+			present[i][j] = true
+			weights[i][j] = w
+		}
+	}
+	return graphFromMatrix(present, weights, rows, opts)
+}
+
+// LoadAdjacencyMatrix opens filename and reads it with ReadAdjacencyMatrix.
+func LoadAdjacencyMatrix(filename string, opts MatrixOptions) (Graph, error) {
+	return loadFile(filename, func(r io.Reader) (Graph, error) {
+		return ReadAdjacencyMatrix(r, opts)
+	})
+}
+
+// LoadWeightMatrix opens filename and reads it with ReadWeightMatrix.
+func LoadWeightMatrix(filename string, opts MatrixOptions) (Graph, error) {
+	return loadFile(filename, func(r io.Reader) (Graph, error) {
+		return ReadWeightMatrix(r, opts)
+	})
+}
+
+func loadFile[T any](filename string, read func(io.Reader) (T, error)) (T, error) {
+	var zero T
+	file, err := os.Open(filename)
+	if err != nil {
+		return zero, err
+	}
+	defer file.Close()
+
+	graph, err := read(file)
+	if err != nil {
+		return zero, fmt.Errorf("%s: %w", filename, err)
+	}
+	return graph, nil
+}
+
+func delimiterString(delim rune) string {
+	if delim == 0 {
+		return " "
+	}
+	return string(delim)
+}
+
+// WriteAdjacencyMatrix writes the 0/1 adjacency matrix, one row per line.
+func (g *Graph) WriteAdjacencyMatrix(w io.Writer, delim rune) error {
+	sep := delimiterString(delim)
+	bw := bufio.NewWriter(w)
+	for _, row := range g.AdjMatrix {
+		for j, val := range row {
+			if j > 0 {
+				bw.WriteString(sep)
+			}
+			if val > 0 {
+				bw.WriteString("1")
+			} else {
+				bw.WriteString("0")
+			}
+		}
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// WriteWeightMatrix writes the weight matrix in the format read by
+// ReadWeightMatrix, with "inf" for missing edges and 0 on the diagonal. A
+// loop of weight 0 would read back as no loop, so it is an error.
+func (g *Graph) WriteWeightMatrix(w io.Writer, delim rune) error {
+	if !g.Weighted {
+		return fmt.Errorf("cannot write weight matrix of an unweighted graph")
+	}
+	for v := range g.AdjMatrix {
+		if g.AdjMatrix[v][v] > 0 && g.WeightMatrix[v][v] == 0 {
+			return fmt.Errorf("weight matrix cannot represent a self-loop of weight 0 (vertex %d)", v+1)
+		}
+	}
+
+	sep := delimiterString(delim)
+	bw := bufio.NewWriter(w)
+	for i, row := range g.AdjMatrix {
+		for j, val := range row {
+			if j > 0 {
+				bw.WriteString(sep)
+			}
+			switch {
+			case val > 0:
+				bw.WriteString(strconv.FormatFloat(g.WeightMatrix[i][j], 'g', -1, 64))
+			case i == j:
+				bw.WriteString("0")
+			default:
+				bw.WriteString(missingEdgeToken)
+			}
+		}
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,40 @@
+package graph
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWeightMatrixRoundTrip(t *testing.T) {
+	g := NewGraph(3, true, true)
+	g.AddEdge(1, 2, 0)
+	g.AddEdge(2, 3, -1.5)
+	g.AddEdge(3, 3, 4)
+
+	var buf bytes.Buffer
+	if err := g.WriteWeightMatrix(&buf, 0); err != nil {
+		t.Fatal(err)
+	}
+	back, err := ReadWeightMatrix(&buf, MatrixOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range [][2]int{{1, 2}, {2, 3}, {3, 3}} {
+		if !back.HasEdge(e[0], e[1]) || back.WeightMatrix[e[0]-1][e[1]-1] != g.WeightMatrix[e[0]-1][e[1]-1] {
+			t.Errorf("edge %v lost or changed on round trip", e)
+		}
+	}
+	if len(back.Edges) != len(g.Edges) {
+		t.Errorf("got %d edges, want %d", len(back.Edges), len(g.Edges))
+	}
+}
+
+func TestWriteWeightMatrixZeroLoop(t *testing.T) {
+	g := NewGraph(2, false, true)
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(2, 2, 0)
+	var buf bytes.Buffer
+	if err := g.WriteWeightMatrix(&buf, 0); err == nil {
+		t.Fatalf("zero-weight loop written as\n%s", buf.String())
+	}
+}
@@ -0,0 +1,213 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ReadMETIS reads an undirected graph in the METIS/Chaco .graph format:
+// a header "n m [fmt [ncon]]" followed by one line per vertex listing its
+// neighbours. Vertex sizes and weights are parsed and dropped; edge weights
+// are kept when fmt enables them. Lines starting with '%' are comments; an
+// empty line is a vertex without neighbours.
+func ReadMETIS(r io.Reader, opts ReadOptions) (*SparseGraph, error) {
+	scanner := newLineScanner(r)
+	lineNo := 0
+
+	var (
+		graph         *SparseGraph
+		m             int
+		hasSizes      bool
+		vertexWeights int
+		edgeWeights   bool
+		vertex        int
+		halfEdges     int
+		// Wagi krawędzi (u, v), u < v, czekających na wpis v w liście u
+		unmatched = make(map[[2]int]float64)
+	)
+
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if strings.HasPrefix(line, "%") {
+			continue
+		}
+		fields := strings.Fields(line)
+
+		if graph == nil {
+			if len(fields) == 0 {
+				continue
+			}
+			if len(fields) < 2 || len(fields) > 4 {
+				return nil, parseErr(lineNo, ErrInvalidHeader, "expected \"n m [fmt [ncon]]\"")
+			}
+			nums := make([]int, len(fields))
+			for i, field := range fields {
+				num, err := strconv.Atoi(field)
+				if err != nil || num < 0 {
+					return nil, parseErr(lineNo, ErrInvalidHeader, "%q is not a non-negative integer", field)
+				}
+				nums[i] = num
+			}
+			m = nums[1]
+			if len(fields) >= 3 {
+				format := fields[2]
+				if len(format) > 3 || strings.Trim(format, "01") != "" {
+					return nil, parseErr(lineNo, ErrInvalidHeader, "fmt must be up to three 0/1 digits, got %q", fields[2])
+				}
+				format = strings.Repeat("0", 3-len(format)) + format
+				hasSizes = format[0] == '1'
+				if format[1] == '1' {
+					vertexWeights = 1
+				}
+				edgeWeights = format[2] == '1'
+			}
+			if len(fields) == 4 {
+				if vertexWeights == 0 {
+					return nil, parseErr(lineNo, ErrInvalidHeader, "ncon given without vertex weights")
+				}
+				vertexWeights = nums[3]
+			}
+			if err := checkVertexLimit(opts.MaxVertices, nums[0], lineNo); err != nil {
+				return nil, err
+			}
+			graph = NewSparseGraph(nums[0], false, edgeWeights)
+			continue
+		}
+
+		vertex++
+		if vertex > graph.N() {
+			if len(fields) == 0 {
+				continue
+			}
+			return nil, parseErr(lineNo, ErrVertexOutOfRange, "more vertex lines than n=%d", graph.N())
+		}
+
+		skip := vertexWeights
+		if hasSizes {
+			skip++
+		}
+		if len(fields) < skip {
+			return nil, parseErr(lineNo, ErrMissingField, "expected %d size/weight fields, got %d", skip, len(fields))
+		}
+		fields = fields[skip:]
+
+		step := 1
+		if edgeWeights {
+			step = 2
+		}
+		if len(fields)%step != 0 {
+			return nil, parseErr(lineNo, ErrMissingField, "neighbour list has a dangling entry")
+		}
+		for i := 0; i < len(fields); i += step {
+			v, err := strconv.Atoi(fields[i])
+			if err != nil || v < 1 || v > graph.N() {
+				return nil, parseErr(lineNo, ErrInvalidVertex, "%q is not in 1..%d", fields[i], graph.N())
+			}
+			if v == vertex {
+				return nil, parseErr(lineNo, ErrInvalidVertex, "self-loop on vertex %d", v)
+			}
+			halfEdges++
+			w := 1.0
+			if edgeWeights {
+				if w, err = strconv.ParseFloat(fields[i+1], 64); err != nil {
+					return nil, parseErr(lineNo, ErrInvalidWeight, "%q is not a number", fields[i+1])
+				}
+			}
+			// Każda krawędź występuje dwa razy; dodajemy ją przy pierwszym
+			// wpisie, a drugi musi mu odpowiadać
+			if v < vertex {
+				key := [2]int{v, vertex}
+				first, ok := unmatched[key]
+				switch {
+				case !ok:
+					return nil, parseErr(lineNo, ErrNotSymmetric, "vertex %d lists %d, but %d does not list %d", vertex, v, v, vertex)
+				case first != w:
+					return nil, parseErr(lineNo, ErrNotSymmetric, "edge %d-%d has weight %g, but %d-%d has %g", vertex, v, w, v, vertex, first)
+				}
+				delete(unmatched, key)
+				continue
+			}
+			key := [2]int{vertex, v}
+			if _, ok := unmatched[key]; ok {
+				return nil, parseErr(lineNo, ErrInvalidVertex, "vertex %d lists %d twice", vertex, v)
+			}
+			unmatched[key] = w
+			graph.AddEdge(vertex, v, w)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if graph == nil {
+		return nil, parseErr(0, ErrInvalidHeader, "missing header")
+	}
+	if vertex < graph.N() {
+		return nil, parseErr(0, ErrHeaderMismatch, "header declares n=%d but %d vertex lines were read", graph.N(), vertex)
+	}
+	if len(unmatched) > 0 {
+		// Zgłaszamy pierwszą brakującą krawędź, żeby błąd był powtarzalny
+		var e [2]int
+		for key := range unmatched {
+			if e[0] == 0 || key[0] < e[0] || key[0] == e[0] && key[1] < e[1] {
+				e = key
+			}
+		}
+		return nil, parseErr(0, ErrNotSymmetric, "vertex %d lists %d, but %d does not list %d", e[0], e[1], e[1], e[0])
+	}
+	if halfEdges != 2*graph.M || graph.M != m {
+		return nil, parseErr(0, ErrHeaderMismatch, "header declares m=%d but adjacency lists describe %d half-edges", m, halfEdges)
+	}
+	return graph, nil
+}
+
+// LoadMETIS opens filename and reads it with ReadMETIS.
+func LoadMETIS(filename string) (*SparseGraph, error) {
+	return loadFile(filename, func(r io.Reader) (*SparseGraph, error) {
+		return ReadMETIS(r, ReadOptions{})
+	})
+}
+
+// WriteMETIS writes s in the METIS .graph format. METIS only accepts
+// undirected graphs without self-loops and with positive integer weights.
+func (s *SparseGraph) WriteMETIS(w io.Writer) error {
+	if s.Directed {
+		return fmt.Errorf("METIS format does not support directed graphs")
+	}
+	for i, arcs := range s.Adj {
+		for _, arc := range arcs {
+			if arc.To == i+1 {
+				return fmt.Errorf("METIS format does not support self-loops (vertex %d)", arc.To)
+			}
+			if s.Weighted && (arc.Weight < 1 || arc.Weight != math.Trunc(arc.Weight)) {
+				return fmt.Errorf("METIS format requires positive integer weights, edge %d-%d has %g", i+1, arc.To, arc.Weight)
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	if s.Weighted {
+		fmt.Fprintf(bw, "%d %d 1\n", s.N(), s.M)
+	} else {
+		fmt.Fprintf(bw, "%d %d\n", s.N(), s.M)
+	}
+	for _, arcs := range s.Adj {
+		for j, arc := range arcs {
+			if j > 0 {
+				bw.WriteByte(' ')
+			}
+			if s.Weighted {
+				fmt.Fprintf(bw, "%d %d", arc.To, int64(arc.Weight))
+			} else {
+				fmt.Fprintf(bw, "%d", arc.To)
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,527 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrUnbalanced = errors.New("supplies do not sum to zero")
+	ErrInfeasible = errors.New("no feasible flow")
+	ErrUnbounded  = errors.New("cost is unbounded below")
+)
+
+// MinCostFlowAlgorithm selects how MinCostFlow works.
+type MinCostFlowAlgorithm int
+
+const (
+	// SuccessiveShortestPaths sends flow along cheapest paths found by
+	// Dijkstra on reduced costs. It needs a graph without negative cycles.
+	SuccessiveShortestPaths MinCostFlowAlgorithm = iota
+	// NetworkSimplex pivots on a spanning tree of the network; it handles
+	// negative cycles of bounded capacity.
+	NetworkSimplex
+)
+
+func (a MinCostFlowAlgorithm) String() string {
+	switch a {
+	case SuccessiveShortestPaths:
+		return "ssp"
+	case NetworkSimplex:
+		return "network-simplex"
+	}
+	return "unknown"
+}
+
+type MinCostFlowOptions struct {
+	Algorithm MinCostFlowAlgorithm
+	// Capacity[u-1][v-1] bounds the flow on the edge (u, v); nil leaves every
+	// edge unbounded.
+	Capacity [][]float64
+	// Progress, if set, receives progress reports.
+	Progress ProgressFunc
+}
+
+// MinCostFlowResult is a cheapest flow meeting the supplies together with
+// dual values that prove it optimal.
+type MinCostFlowResult struct {
+	// Flow[u-1][v-1] is the flow on the edge (u, v). An undirected edge is a
+	// pair of opposite arcs, each with its own flow.
+	Flow [][]float64 `json:"flow"`
+	Cost float64     `json:"cost"`
+	// Potential[v-1] is the dual value of v: for every edge (u, v) the
+	// reduced cost weight + Potential[u-1] - Potential[v-1] is non-negative
+	// if the edge has spare capacity and non-positive if it carries flow.
+	Potential []float64 `json:"potential"`
+}
+
+// MinCostFlow finds a flow of minimum total cost, where an edge costs its
+// weight per unit of flow. supply[v-1] > 0 units are produced at v and
+// supply[v-1] < 0 consumed; the supplies must sum to zero (ErrUnbalanced).
+// If they cannot be met the error wraps ErrInfeasible; if a negative cycle
+// of unbounded capacity exists it wraps ErrUnbounded. If ctx is cancelled it
+// returns nil and ctx.Err().
+func (g *Graph) MinCostFlow(ctx context.Context, supply []float64, opts MinCostFlowOptions) (*MinCostFlowResult, error) {
+	n := len(g.AdjMatrix)
+	if len(supply) != n {
+		return nil, fmt.Errorf("MinCostFlow: %d supplies for %d vertices", len(supply), n)
+	}
+	total := 0.0
+	for _, b := range supply {
+		total += b
+	}
+	if math.Abs(total) > flowEpsilon {
+		return nil, fmt.Errorf("MinCostFlow: %w (total %g)", ErrUnbalanced, total)
+	}
+	if opts.Capacity != nil && len(opts.Capacity) != n {
+		return nil, fmt.Errorf("MinCostFlow: capacity matrix has %d rows for %d vertices", len(opts.Capacity), n)
+	}
+
+	// Łuki grafu (bez pętli) z przepustowościami i kosztami
+	var arcs []costArc
+	for u, out := range g.arcs() {
+		for _, a := range out {
+			if u == a.to {
+				continue
+			}
+			capacity := math.Inf(1)
+			if opts.Capacity != nil {
+				capacity = opts.Capacity[u][a.to]
+			}
+			if capacity < 0 {
+				return nil, fmt.Errorf("MinCostFlow: negative capacity %g on edge (%d, %d)", capacity, u+1, a.to+1)
+			}
+			arcs = append(arcs, costArc{from: u, to: a.to, cap: capacity, cost: a.weight})
+		}
+	}
+
+	var err error
+	if opts.Algorithm == NetworkSimplex {
+		err = networkSimplex(newReporter(ctx, opts.Progress, "network-simplex"), n, arcs, supply)
+	} else {
+		err = successiveShortestPaths(newReporter(ctx, opts.Progress, "ssp"), n, arcs, supply)
+	}
+	if err != nil {
+		if errors.Is(err, ErrInfeasible) || errors.Is(err, ErrUnbounded) || errors.Is(err, ErrNegativeCycle) {
+			return nil, fmt.Errorf("MinCostFlow: %w", err)
+		}
+		return nil, err
+	}
+
+	res := &MinCostFlowResult{Flow: make([][]float64, n)}
+	for u := range res.Flow {
+		res.Flow[u] = make([]float64, n)
+	}
+	for _, a := range arcs {
+		res.Flow[a.from][a.to] += a.flow
+		res.Cost += a.flow * a.cost
+	}
+	if res.Potential, err = residualPotentials(reporter{ctx: ctx}, n, arcs); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// costArc is an arc of a min-cost flow problem (0-based endpoints).
+type costArc struct {
+	from, to        int
+	cap, cost, flow float64
+}
+
+// residualPotentials computes dual values as shortest distances in the
+// residual network from a virtual vertex joined to every vertex, so every
+// residual arc has a non-negative reduced cost.
+func residualPotentials(rep reporter, n int, arcs []costArc) ([]float64, error) {
+	adj := make([][]arc, n+1)
+	for _, a := range arcs {
+		if a.cap-a.flow > flowEpsilon {
+			adj[a.from] = append(adj[a.from], arc{a.to, a.cost})
+		}
+		if a.flow > flowEpsilon {
+			adj[a.to] = append(adj[a.to], arc{a.from, -a.cost})
+		}
+	}
+	for v := 0; v < n; v++ {
+		adj[n] = append(adj[n], arc{v, 0})
+	}
+	sp, err := bellmanFord(rep, adj, n+1)
+	if err != nil {
+		return nil, err
+	}
+	return sp.Dist[:n], nil
+}
+
+// successiveShortestPaths routes the supplies from a super source to a super
+// sink one cheapest augmenting path at a time, keeping potentials that make
+// every reduced cost non-negative so Dijkstra can find the paths.
+func successiveShortestPaths(rep reporter, n int, arcs []costArc, supply []float64) error {
+	source, sink := n, n+1
+	net := &flowNetwork{adj: make([][]flowArc, n+2)}
+	for _, a := range arcs {
+		net.addArc(a.from, a.to, a.cap, a.cost, true)
+	}
+	remaining := 0.0
+	for v, b := range supply {
+		if b > 0 {
+			net.addArc(source, v, b, 0, false)
+			remaining += b
+		} else if b < 0 {
+			net.addArc(v, sink, -b, 0, false)
+		}
+	}
+
+	// Początkowe potencjały z Bellmana-Forda, jeśli są ujemne koszty. Startujemy
+	// z wierzchołka połączonego ze wszystkimi, bo optymalny przepływ nasyca też
+	// ujemne cykle, do których nie dociera żadna podaż
+	potential := make([]float64, n+2)
+	negative := false
+	for _, a := range arcs {
+		negative = negative || a.cost < 0
+	}
+	if negative {
+		adj := make([][]arc, n+3)
+		for u := range net.adj {
+			for _, a := range net.adj[u] {
+				if net.residual(&a) > flowEpsilon {
+					adj[u] = append(adj[u], arc{a.to, a.cost})
+				}
+			}
+			adj[n+2] = append(adj[n+2], arc{u, 0})
+		}
+		sp, err := bellmanFord(reporter{ctx: rep.ctx}, adj, n+3)
+		if err != nil {
+			return fmt.Errorf("%w; successive shortest paths cannot handle it, use the network simplex", err)
+		}
+		copy(potential, sp.Dist)
+	}
+
+	dist := make([]float64, n+2)
+	pred, predArc := make([]int, n+2), make([]int, n+2)
+	for paths := 0; remaining > flowEpsilon; paths++ {
+		if err := rep.step("augmenting paths", paths, 0); err != nil {
+			return err
+		}
+		for i := range dist {
+			dist[i], pred[i] = math.Inf(1), -1
+		}
+		dist[source] = 0
+		queue := newPriorityQueue(BinaryHeap, n+2)
+		queue.push(source, 0)
+		for queue.len() > 0 {
+			u, d := queue.pop()
+			if d > dist[u] {
+				continue
+			}
+			for i := range net.adj[u] {
+				a := &net.adj[u][i]
+				if net.residual(a) <= flowEpsilon {
+					continue
+				}
+				// Koszt zredukowany; zaokrąglenia mogą dać minimalnie ujemny
+				reduced := max(a.cost+potential[u]-potential[a.to], 0)
+				if nd := dist[u] + reduced; nd < dist[a.to] {
+					dist[a.to], pred[a.to], predArc[a.to] = nd, u, i
+					queue.push(a.to, nd)
+				}
+			}
+		}
+		if math.IsInf(dist[sink], 1) {
+			return fmt.Errorf("%w: %g units cannot reach a demand", ErrInfeasible, remaining)
+		}
+		// Wierzchołki nieosiągnięte dostają odległość ujścia, co zachowuje nieujemność
+		for v := range potential {
+			potential[v] += min(dist[v], dist[sink])
+		}
+
+		amount := remaining
+		for v := sink; v != source; v = pred[v] {
+			amount = min(amount, net.residual(&net.adj[pred[v]][predArc[v]]))
+		}
+		for v := sink; v != source; v = pred[v] {
+			net.push(&net.adj[pred[v]][predArc[v]], amount)
+		}
+		remaining -= amount
+	}
+
+	// arcs są uporządkowane według początku, tak jak łuki w net.adj
+	i := 0
+	for u := 0; u < n; u++ {
+		for _, a := range net.adj[u] {
+			if a.original {
+				arcs[i].flow = a.flow
+				i++
+			}
+		}
+	}
+	return nil
+}
+
+// Stany łuków w sympleksie sieciowym
+const (
+	arcUpper int8 = -1 // przepływ równy przepustowości
+	arcTree  int8 = 0
+	arcLower int8 = 1 // przepływ zerowy
+)
+
+// networkSimplex solves the problem on a strongly feasible spanning tree:
+// an artificial root joined to every vertex by arcs of prohibitive cost
+// starts off carrying all the supply, and each pivot brings in an arc of
+// negative reduced cost, chosen best-in-block, and sends flow round the
+// cycle it closes. Keeping the tree strongly feasible prevents cycling on
+// degenerate pivots.
+func networkSimplex(rep reporter, n int, arcs []costArc, supply []float64) error {
+	m := len(arcs)
+	root := n
+	src, dst := make([]int, m+n), make([]int, m+n)
+	cost, capacity, flow := make([]float64, m+n), make([]float64, m+n), make([]float64, m+n)
+	state := make([]int8, m+n)
+	maxCost := 0.0
+	for i, a := range arcs {
+		src[i], dst[i], cost[i], capacity[i] = a.from, a.to, a.cost, a.cap
+		state[i] = arcLower
+		maxCost = max(maxCost, math.Abs(a.cost))
+	}
+	artificial := (maxCost + 1) * float64(n+1)
+
+	parent, pred := make([]int, n+1), make([]int, n+1)
+	up := make([]bool, n+1) // łuk pred[v] biegnie od v do rodzica
+	depth := make([]int, n+1)
+	pi := make([]float64, n+1)
+	parent[root], pred[root] = -1, -1
+	for v := 0; v < n; v++ {
+		e := m + v
+		cost[e], capacity[e], state[e] = artificial, math.Inf(1), arcTree
+		if supply[v] >= 0 {
+			src[e], dst[e], flow[e], up[v] = v, root, supply[v], true
+		} else {
+			src[e], dst[e], flow[e] = root, v, -supply[v]
+		}
+		parent[v], pred[v] = root, e
+	}
+
+	// Potencjały i głębokości liczymy od nowa po każdej wymianie: O(n)
+	children := make([][]int, n+1)
+	updateTree := func() {
+		for v := range children {
+			children[v] = children[v][:0]
+		}
+		for v := 0; v < n; v++ {
+			children[parent[v]] = append(children[parent[v]], v)
+		}
+		stack := []int{root}
+		for len(stack) > 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, v := range children[u] {
+				depth[v] = depth[u] + 1
+				if up[v] {
+					pi[v] = pi[u] - cost[pred[v]]
+				} else {
+					pi[v] = pi[u] + cost[pred[v]]
+				}
+				stack = append(stack, v)
+			}
+		}
+	}
+	updateTree()
+
+	block := max(int(math.Sqrt(float64(m))), 10)
+	next := 0
+	for pivots := 0; ; pivots++ {
+		if pivots%checkEvery == 0 {
+			if err := rep.step("pivots", pivots, 0); err != nil {
+				return err
+			}
+		}
+
+		// Wybór łuku wchodzącego: najbardziej ujemny koszt zredukowany w bloku
+		in, best := -1, -flowEpsilon
+		for scanned := 0; scanned < m; {
+			for end := min(scanned+block, m); scanned < end; scanned++ {
+				e := next
+				if next++; next == m {
+					next = 0
+				}
+				if violation := float64(state[e]) * (cost[e] + pi[src[e]] - pi[dst[e]]); violation < best {
+					in, best = e, violation
+				}
+			}
+			if in >= 0 {
+				break
+			}
+		}
+		if in < 0 {
+			break
+		}
+
+		// Cykl: łuk wchodzący i ścieżki w drzewie do wspólnego przodka
+		first, second := src[in], dst[in]
+		if state[in] == arcUpper {
+			first, second = second, first
+		}
+		join := first
+		for u, v := first, second; ; {
+			if u == v {
+				join = u
+				break
+			}
+			if depth[u] >= depth[v] {
+				u = parent[u]
+			} else {
+				v = parent[v]
+			}
+		}
+		// Łuk opuszczający: wąskie gardło cyklu, przy remisie ostatnie na obiegu
+		delta, out, side := capacity[in], -1, 0
+		for u := first; u != join; u = parent[u] {
+			d := flow[pred[u]]
+			if !up[u] {
+				d = capacity[pred[u]] - d
+			}
+			if d < delta {
+				delta, out, side = d, u, 1
+			}
+		}
+		for u := second; u != join; u = parent[u] {
+			d := flow[pred[u]]
+			if up[u] {
+				d = capacity[pred[u]] - d
+			}
+			if d <= delta {
+				delta, out, side = d, u, 2
+			}
+		}
+		if math.IsInf(delta, 1) {
+			return fmt.Errorf("%w: negative cycle of unbounded capacity", ErrUnbounded)
+		}
+
+		if delta > 0 {
+			val := float64(state[in]) * delta
+			flow[in] += val
+			for u := src[in]; u != join; u = parent[u] {
+				if up[u] {
+					flow[pred[u]] -= val
+				} else {
+					flow[pred[u]] += val
+				}
+			}
+			for u := dst[in]; u != join; u = parent[u] {
+				if up[u] {
+					flow[pred[u]] += val
+				} else {
+					flow[pred[u]] -= val
+				}
+			}
+		}
+		if side == 0 {
+			// Łuk wchodzący sam jest wąskim gardłem: przechodzi na drugie ograniczenie
+			state[in] = -state[in]
+			continue
+		}
+
+		leaving := pred[out]
+		if flow[leaving] <= capacity[leaving]-flow[leaving] {
+			flow[leaving], state[leaving] = 0, arcLower
+		} else {
+			flow[leaving], state[leaving] = capacity[leaving], arcUpper
+		}
+		state[in] = arcTree
+
+		// Odwracamy ścieżkę drzewa od końca łuku wchodzącego do out
+		uIn, vIn := first, second
+		if side == 2 {
+			uIn, vIn = second, first
+		}
+		prevNode, prevArc := vIn, in
+		for u := uIn; ; {
+			nextNode, nextArc := parent[u], pred[u]
+			parent[u], pred[u], up[u] = prevNode, prevArc, src[prevArc] == u
+			if u == out {
+				break
+			}
+			prevNode, prevArc, u = u, nextArc, nextNode
+		}
+		updateTree()
+	}
+
+	for v := 0; v < n; v++ {
+		if flow[m+v] > flowEpsilon {
+			return fmt.Errorf("%w: %g units of supply at or demand of vertex %d cannot be met", ErrInfeasible, flow[m+v], v+1)
+		}
+	}
+	for i := range arcs {
+		arcs[i].flow = flow[i]
+	}
+	return nil
+}
+
+// TransportationPlan is a solution of the transportation problem.
+type TransportationPlan struct {
+	// Ship[i][j] is the amount sent from source i to destination j (0-based).
+	Ship [][]float64 `json:"ship"`
+	Cost float64     `json:"cost"`
+	// SourcePotential[i] and DestinationPotential[j] are dual values with
+	// DestinationPotential[j] - SourcePotential[i] <= cost[i][j], with
+	// equality wherever something is shipped.
+	SourcePotential      []float64 `json:"source_potential"`
+	DestinationPotential []float64 `json:"destination_potential"`
+}
+
+// SolveTransportation ships supply[i] units from every source i to meet
+// demand[j] at every destination j at the least total cost, where a unit
+// from i to j costs cost[i][j] (+Inf if there is no route). Supply left over
+// when it exceeds the demand stays at the sources; a larger demand is
+// ErrInfeasible. Progress goes to progress, which may be nil.
+func SolveTransportation(ctx context.Context, supply, demand []float64, cost [][]float64, algorithm MinCostFlowAlgorithm, progress ProgressFunc) (*TransportationPlan, error) {
+	s, d := len(supply), len(demand)
+	if len(cost) != s {
+		return nil, fmt.Errorf("SolveTransportation: %d cost rows for %d sources", len(cost), s)
+	}
+	surplus := 0.0
+	for _, x := range supply {
+		surplus += x
+	}
+	for _, x := range demand {
+		surplus -= x
+	}
+	if surplus < -flowEpsilon {
+		return nil, fmt.Errorf("SolveTransportation: %w: demand exceeds supply by %g", ErrInfeasible, -surplus)
+	}
+
+	// Źródła 1..s, cele s+1..s+d i cel pozorny zbierający nadwyżkę
+	graph := NewGraph(s+d+1, true, true)
+	balance := make([]float64, s+d+1)
+	for i := range supply {
+		if len(cost[i]) != d {
+			return nil, fmt.Errorf("SolveTransportation: cost row %d has %d entries for %d destinations", i+1, len(cost[i]), d)
+		}
+		balance[i] = supply[i]
+		for j, c := range cost[i] {
+			if !math.IsInf(c, 1) {
+				graph.AddEdge(i+1, s+j+1, c)
+			}
+		}
+		graph.AddEdge(i+1, s+d+1, 0)
+	}
+	for j := range demand {
+		balance[s+j] = -demand[j]
+	}
+	balance[s+d] = -surplus
+
+	res, err := graph.MinCostFlow(ctx, balance, MinCostFlowOptions{Algorithm: algorithm, Progress: progress})
+	if err != nil {
+		return nil, err
+	}
+	plan := &TransportationPlan{
+		Ship:                 make([][]float64, s),
+		Cost:                 res.Cost,
+		SourcePotential:      res.Potential[:s],
+		DestinationPotential: res.Potential[s : s+d],
+	}
+	for i := range plan.Ship {
+		plan.Ship[i] = res.Flow[i][s : s+d]
+	}
+	return plan, nil
+}
@@ -0,0 +1,275 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Simikao/graphOptimalisation/internal/generate"
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+var minCostFlowAlgorithms = []g.MinCostFlowAlgorithm{g.SuccessiveShortestPaths, g.NetworkSimplex}
+
+// checkMinCostFlow verifies that res meets the supplies within capacity,
+// that its cost adds up and that the potentials prove it optimal.
+func checkMinCostFlow(t *testing.T, graph *g.Graph, supply []float64, capacity [][]float64, res *g.MinCostFlowResult) {
+	t.Helper()
+	const eps = 1e-9
+	n := len(graph.AdjMatrix)
+	cost := 0.0
+	for u := 1; u <= n; u++ {
+		for v := 1; v <= n; v++ {
+			f := res.Flow[u-1][v-1]
+			if f == 0 {
+				continue
+			}
+			if !graph.HasEdge(u, v) || u == v {
+				t.Errorf("flow %g on the non-edge (%d, %d)", f, u, v)
+				continue
+			}
+			limit := math.Inf(1)
+			if capacity != nil {
+				limit = capacity[u-1][v-1]
+			}
+			if f < -eps || f > limit+eps {
+				t.Errorf("flow %g on (%d, %d) outside [0, %g]", f, u, v, limit)
+			}
+			cost += f * graph.WeightMatrix[u-1][v-1]
+		}
+	}
+	if math.Abs(cost-res.Cost) > eps {
+		t.Errorf("flow costs %g, reported cost is %g", cost, res.Cost)
+	}
+	for v := 1; v <= n; v++ {
+		out := 0.0
+		for u := 1; u <= n; u++ {
+			out += res.Flow[v-1][u-1] - res.Flow[u-1][v-1]
+		}
+		if math.Abs(out-supply[v-1]) > eps {
+			t.Errorf("vertex %d sends out %g, supply is %g", v, out, supply[v-1])
+		}
+	}
+	// Komplementarność: łuk z wolną przepustowością ma nieujemny koszt zredukowany, łuk z przepływem niedodatni
+	for _, e := range graph.Edges {
+		for _, uv := range [][2]int{{e[0], e[1]}, {e[1], e[0]}} {
+			u, v := uv[0], uv[1]
+			if u == v || !graph.HasEdge(u, v) {
+				continue
+			}
+			reduced := graph.WeightMatrix[u-1][v-1] + res.Potential[u-1] - res.Potential[v-1]
+			limit := math.Inf(1)
+			if capacity != nil {
+				limit = capacity[u-1][v-1]
+			}
+			f := res.Flow[u-1][v-1]
+			if f < limit-eps && reduced < -eps {
+				t.Errorf("edge (%d, %d) has spare capacity and reduced cost %g", u, v, reduced)
+			}
+			if f > eps && reduced > eps {
+				t.Errorf("edge (%d, %d) carries flow at reduced cost %g", u, v, reduced)
+			}
+		}
+	}
+}
+
+func TestMinCostFlow(t *testing.T) {
+	// Dwie drogi z 1 do 4: tania przez 2 o przepustowości 3 i droga przez 3
+	graph := g.NewGraph(4, true, true)
+	graph.AddEdge(1, 2, 1).AddEdge(2, 4, 1).AddEdge(1, 3, 2).AddEdge(3, 4, 2).AddEdge(2, 3, 0)
+	capacity := [][]float64{
+		{0, 3, 10, 0},
+		{0, 0, 1, 2},
+		{0, 0, 0, 10},
+		{0, 0, 0, 0},
+	}
+	supply := []float64{5, 0, 0, -5}
+	// 2 jednostki 1-2-4 (4), 1 jednostka 1-2-3-4 (3), 2 jednostki 1-3-4 (8)
+	const want = 15
+	for _, alg := range minCostFlowAlgorithms {
+		t.Run(alg.String(), func(t *testing.T) {
+			res, err := graph.MinCostFlow(context.Background(), supply, g.MinCostFlowOptions{Algorithm: alg, Capacity: capacity})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Cost != want {
+				t.Errorf("cost %g, want %d", res.Cost, want)
+			}
+			checkMinCostFlow(t, &graph, supply, capacity, res)
+		})
+	}
+}
+
+func TestMinCostFlowTrivial(t *testing.T) {
+	empty := g.NewGraph(0, true, true)
+	single := g.NewGraph(1, true, true)
+	for _, alg := range minCostFlowAlgorithms {
+		for name, tt := range map[string]struct {
+			graph  *g.Graph
+			supply []float64
+		}{"empty": {&empty, nil}, "single vertex": {&single, []float64{0}}} {
+			res, err := tt.graph.MinCostFlow(context.Background(), tt.supply, g.MinCostFlowOptions{Algorithm: alg})
+			if err != nil {
+				t.Errorf("%s, %s: %v", alg, name, err)
+			} else if res.Cost != 0 {
+				t.Errorf("%s, %s: cost %g, want 0", alg, name, res.Cost)
+			}
+		}
+	}
+}
+
+func TestMinCostFlowErrors(t *testing.T) {
+	ctx := context.Background()
+	// 1 i 2 w jednej składowej, 3 i 4 w drugiej
+	disconnected := g.NewGraph(4, true, true)
+	disconnected.AddEdge(1, 2, 1).AddEdge(3, 4, 1)
+
+	unbounded := g.NewGraph(3, true, true)
+	unbounded.AddEdge(1, 2, 1).AddEdge(2, 3, -2).AddEdge(3, 2, 1)
+
+	tests := []struct {
+		name   string
+		graph  *g.Graph
+		supply []float64
+		want   error
+	}{
+		{"unbalanced", &disconnected, []float64{1, 0, 0, 0}, g.ErrUnbalanced},
+		{"across components", &disconnected, []float64{1, 0, 0, -1}, g.ErrInfeasible},
+		{"against the edges", &disconnected, []float64{0, 1, 0, -1}, g.ErrInfeasible},
+	}
+	for _, alg := range minCostFlowAlgorithms {
+		for _, tt := range tests {
+			_, err := tt.graph.MinCostFlow(ctx, tt.supply, g.MinCostFlowOptions{Algorithm: alg})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("%s, %s: got %v, want %v", alg, tt.name, err, tt.want)
+			}
+		}
+	}
+	// Ujemny cykl 2-3-2 bez ograniczenia przepustowości
+	if _, err := unbounded.MinCostFlow(ctx, []float64{1, 0, -1}, g.MinCostFlowOptions{Algorithm: g.NetworkSimplex}); !errors.Is(err, g.ErrUnbounded) {
+		t.Errorf("network simplex: got %v, want %v", err, g.ErrUnbounded)
+	}
+	if _, err := unbounded.MinCostFlow(ctx, []float64{1, 0, -1}, g.MinCostFlowOptions{}); err == nil {
+		t.Error("successive shortest paths accepted a negative cycle")
+	}
+}
+
+func TestNetworkSimplexNegativeCycle(t *testing.T) {
+	// Cykl 2-3-2 kosztuje -1 za jednostkę i mieści 4 jednostki
+	graph := g.NewGraph(3, true, true)
+	graph.AddEdge(1, 2, 1).AddEdge(2, 3, -2).AddEdge(3, 2, 1)
+	capacity := [][]float64{{0, 5, 0}, {0, 0, 4}, {0, 4, 0}}
+	supply := []float64{1, 0, -1}
+	res, err := graph.MinCostFlow(context.Background(), supply, g.MinCostFlowOptions{Algorithm: g.NetworkSimplex, Capacity: capacity})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1 -> 2 -> 3 za -1, a potem trzy obiegi cyklu po -1
+	if res.Cost != -4 {
+		t.Errorf("cost %g, want -4", res.Cost)
+	}
+	checkMinCostFlow(t, &graph, supply, capacity, res)
+}
+
+func TestMinCostFlowAlgorithmsAgree(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 30; seed++ {
+		gen := generate.New(seed)
+		gen.Weighted, gen.Directed = true, true
+		gen.MinWeight = 0
+		graph, err := gen.ErdosRenyi(8, 0.35)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Przepustowości i podaże z tego samego ziarna co graf
+		capacity := make([][]float64, 8)
+		for u := range capacity {
+			capacity[u] = make([]float64, 8)
+			for v := range capacity[u] {
+				capacity[u][v] = float64(1 + (u*7+v*3+int(seed))%5)
+			}
+		}
+		supply := make([]float64, 8)
+		for i := 0; i < 3; i++ {
+			amount := float64(1 + (int(seed)+i)%3)
+			supply[i] += amount
+			supply[7-i] -= amount
+		}
+
+		var results []*g.MinCostFlowResult
+		var errs []error
+		for _, alg := range minCostFlowAlgorithms {
+			res, err := graph.MinCostFlow(ctx, supply, g.MinCostFlowOptions{Algorithm: alg, Capacity: capacity})
+			if err == nil {
+				checkMinCostFlow(t, &graph, supply, capacity, res)
+			} else if !errors.Is(err, g.ErrInfeasible) {
+				t.Fatalf("seed %d: %s: %v", seed, alg, err)
+			}
+			results, errs = append(results, res), append(errs, err)
+		}
+		switch {
+		case (errs[0] == nil) != (errs[1] == nil):
+			t.Errorf("seed %d: successive shortest paths gives %v, network simplex %v", seed, errs[0], errs[1])
+		case errs[0] == nil && math.Abs(results[0].Cost-results[1].Cost) > 1e-9:
+			t.Errorf("seed %d: successive shortest paths costs %g, network simplex %g", seed, results[0].Cost, results[1].Cost)
+		}
+	}
+}
+
+func TestSolveTransportation(t *testing.T) {
+	supply := []float64{10, 15}
+	demand := []float64{5, 10, 10}
+	cost := [][]float64{
+		{2, 4, 5},
+		{3, 1, 7},
+	}
+	// Cel 3 najtaniej obsługuje źródło 1 całym zapasem, resztę źródło 2
+	want := [][]float64{
+		{0, 0, 10},
+		{5, 10, 0},
+	}
+	for _, alg := range minCostFlowAlgorithms {
+		t.Run(alg.String(), func(t *testing.T) {
+			plan, err := g.SolveTransportation(context.Background(), supply, demand, cost, alg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plan.Cost != 75 {
+				t.Errorf("cost %g, want 75", plan.Cost)
+			}
+			for i := range want {
+				for j := range want[i] {
+					if plan.Ship[i][j] != want[i][j] {
+						t.Errorf("ship %g from %d to %d, want %g", plan.Ship[i][j], i+1, j+1, want[i][j])
+					}
+					if d := cost[i][j] - plan.DestinationPotential[j] + plan.SourcePotential[i]; d < -1e-9 || plan.Ship[i][j] > 0 && d > 1e-9 {
+						t.Errorf("route %d -> %d has reduced cost %g", i+1, j+1, d)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSolveTransportationSurplusAndErrors(t *testing.T) {
+	ctx := context.Background()
+	inf := math.Inf(1)
+	for _, alg := range minCostFlowAlgorithms {
+		// Nadwyżka zostaje u droższego źródła
+		plan, err := g.SolveTransportation(ctx, []float64{5, 5}, []float64{4}, [][]float64{{3}, {1}}, alg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plan.Cost != 4 || plan.Ship[1][0] != 4 {
+			t.Errorf("%s: got plan %v costing %g, want 4 units from source 2", alg, plan.Ship, plan.Cost)
+		}
+
+		if _, err := g.SolveTransportation(ctx, []float64{3}, []float64{4}, [][]float64{{1}}, alg, nil); !errors.Is(err, g.ErrInfeasible) {
+			t.Errorf("%s, demand above supply: got %v, want %v", alg, err, g.ErrInfeasible)
+		}
+		if _, err := g.SolveTransportation(ctx, []float64{4, 4}, []float64{4, 4}, [][]float64{{1, 1}, {inf, inf}}, alg, nil); !errors.Is(err, g.ErrInfeasible) {
+			t.Errorf("%s, source without routes: got %v, want %v", alg, err, g.ErrInfeasible)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+)
+
+var ErrDisconnected = errors.New("graph is disconnected")
+
+// SpanningForest is a minimum spanning tree of every connected component.
+type SpanningForest struct {
+	// Edges are given as (u, v) with u < v.
+	Edges  [][2]int `json:"edges"`
+	Weight float64  `json:"weight"`
+	// Component[v-1] is the component of v; components are numbered from 1
+	// in the order of their smallest vertex.
+	Component  []int `json:"component"`
+	Components int   `json:"components"`
+}
+
+// Spanning reports whether the forest is a single tree over all vertices.
+func (f *SpanningForest) Spanning() bool {
+	return f.Components <= 1
+}
+
+// MSTAlgorithm selects how MinimumSpanningForest works.
+type MSTAlgorithm int
+
+const (
+	MSTKruskal MSTAlgorithm = iota
+	// MSTPrim grows one tree at a time from a priority queue; it suits dense
+	// graphs.
+	MSTPrim
+	// MSTBoruvka joins every component to its cheapest neighbour in each
+	// round; the search for those edges runs in parallel.
+	MSTBoruvka
+)
+
+func (a MSTAlgorithm) String() string {
+	switch a {
+	case MSTKruskal:
+		return "kruskal"
+	case MSTPrim:
+		return "prim"
+	case MSTBoruvka:
+		return "boruvka"
+	}
+	return "unknown"
+}
+
+type MSTOptions struct {
+	Algorithm MSTAlgorithm
+	// Heap is the priority queue used by Prim.
+	Heap HeapKind
+	// Workers is the number of goroutines Borůvka searches for edges with;
+	// zero means GOMAXPROCS.
+	Workers int
+	// Progress, if set, receives progress reports.
+	Progress ProgressFunc
+}
+
+// MinimumSpanningForest returns a minimum spanning tree of every connected
+// component of an undirected graph; in an unweighted graph every edge weighs
+// 1. Directed graphs are rejected; see MinimumArborescence. If ctx is
+// cancelled it returns the forest built so far and ctx.Err().
+func (g *Graph) MinimumSpanningForest(ctx context.Context, opts MSTOptions, t Tracer) (*SpanningForest, error) {
+	if g.Directed {
+		return nil, fmt.Errorf("MinimumSpanningForest: %w", ErrDirectedGraph)
+	}
+	tr := newTracer(t, "mst")
+	var (
+		edges [][2]int
+		err   error
+	)
+	switch opts.Algorithm {
+	case MSTPrim:
+		edges, err = g.prim(tr, newReporter(ctx, opts.Progress, "prim"), opts.Heap)
+	case MSTBoruvka:
+		edges, err = g.boruvka(tr, newReporter(ctx, opts.Progress, "boruvka"), opts.Workers)
+	default:
+		edges, err = g.kruskal(tr, newReporter(ctx, opts.Progress, "kruskal"))
+	}
+	forest := g.newSpanningForest(edges)
+	if err == nil {
+		tr.emit(EventResult, forest.Weight, flattenEdges(forest.Edges)...)
+	}
+	return forest, err
+}
+
+// MinimumSpanningTree is MinimumSpanningForest that fails with an error
+// wrapping ErrDisconnected, still returning the forest, unless the graph is
+// connected.
+func (g *Graph) MinimumSpanningTree(ctx context.Context, opts MSTOptions, t Tracer) (*SpanningForest, error) {
+	forest, err := g.MinimumSpanningForest(ctx, opts, t)
+	if err != nil {
+		return forest, err
+	}
+	if !forest.Spanning() {
+		return forest, fmt.Errorf("minimum spanning tree: %w (%d components)", ErrDisconnected, forest.Components)
+	}
+	return forest, nil
+}
+
+func (g *Graph) newSpanningForest(edges [][2]int) *SpanningForest {
+	weighted := make([]weightedEdge, len(edges))
+	for i, e := range edges {
+		weighted[i] = weightedEdge{e[0] - 1, e[1] - 1, g.edgeWeight(e[0], e[1])}
+	}
+	return newSpanningForest(len(g.AdjMatrix), weighted)
+}
+
+func newSpanningForest(n int, edges []weightedEdge) *SpanningForest {
+	forest := &SpanningForest{Edges: make([][2]int, 0, len(edges)), Component: make([]int, n)}
+	uf := NewUnionFind(n)
+	for _, e := range edges {
+		u, v := min(e.u, e.v)+1, max(e.u, e.v)+1
+		forest.Edges = append(forest.Edges, [2]int{u, v})
+		forest.Weight += e.weight
+		uf.Union(u-1, v-1)
+	}
+	// Numery składowych według najmniejszego wierzchołka
+	id := make(map[int]int)
+	for v := range forest.Component {
+		root := uf.Find(v)
+		if id[root] == 0 {
+			forest.Components++
+			id[root] = forest.Components
+		}
+		forest.Component[v] = id[root]
+	}
+	return forest
+}
+
+// prim grows a tree from the smallest vertex not yet in the forest until
+// every vertex is covered.
+func (g *Graph) prim(tr tracer, rep reporter, kind HeapKind) ([][2]int, error) {
+	n := len(g.AdjMatrix)
+	adj := g.arcs()
+	inTree := make([]bool, n)
+	key := make([]float64, n) // najtańsza krawędź łącząca wierzchołek z drzewem
+	parent := make([]int, n)  // jej drugi koniec (1-based), 0 dla korzenia
+	for i := range key {
+		key[i] = math.Inf(1)
+	}
+
+	var edges [][2]int
+	done := 0
+	for start := range adj {
+		if inTree[start] {
+			continue
+		}
+		queue := newPriorityQueue(kind, n)
+		key[start] = 0
+		queue.push(start, 0)
+		for queue.len() > 0 {
+			if done%checkEvery == 0 {
+				if err := rep.step("Prim", done, n); err != nil {
+					return edges, err
+				}
+			}
+			u, _ := queue.pop()
+			inTree[u] = true
+			done++
+			if parent[u] != 0 {
+				edges = append(edges, [2]int{parent[u], u + 1})
+				tr.emit(EventMSTEdge, key[u], parent[u], u+1)
+			}
+			for _, a := range adj[u] {
+				if !inTree[a.to] && a.weight < key[a.to] {
+					key[a.to] = a.weight
+					parent[a.to] = u + 1
+					queue.push(a.to, a.weight)
+				}
+			}
+		}
+	}
+	return edges, nil
+}
+
+// boruvkaEdge orders edges by weight and then by endpoints, so that ties
+// cannot make components pick edges that close a cycle.
+type boruvkaEdge struct {
+	u, v   int // 0-based, u < v
+	weight float64
+}
+
+func (a boruvkaEdge) less(b boruvkaEdge) bool {
+	if a.weight != b.weight {
+		return a.weight < b.weight
+	}
+	if a.u != b.u {
+		return a.u < b.u
+	}
+	return a.v < b.v
+}
+
+// boruvka joins every component to its cheapest neighbour in rounds; each
+// round at least halves the number of components that still have one. The
+// vertices are split between workers, which find the cheapest edges leaving
+// the components in parallel.
+func (g *Graph) boruvka(tr tracer, rep reporter, workers int) ([][2]int, error) {
+	n := len(g.AdjMatrix)
+	adj := g.arcs()
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, max(n, 1))
+	uf := NewUnionFind(n)
+	comp := make([]int, n)
+
+	var edges [][2]int
+	for {
+		if err := rep.step("Boruvka rounds", len(edges), n-1); err != nil {
+			return edges, err
+		}
+		for v := range comp {
+			comp[v] = uf.Find(v)
+		}
+
+		// Każdy wątek szuka najtańszych krawędzi wychodzących dla swoich wierzchołków
+		local := make([]map[int]boruvkaEdge, workers)
+		var wg sync.WaitGroup
+		for w := range local {
+			local[w] = make(map[int]boruvkaEdge)
+			wg.Add(1)
+			go func(best map[int]boruvkaEdge, from, to int) {
+				defer wg.Done()
+				for u := from; u < to; u++ {
+					for _, a := range adj[u] {
+						if comp[u] == comp[a.to] {
+							continue
+						}
+						e := boruvkaEdge{min(u, a.to), max(u, a.to), a.weight}
+						if cur, ok := best[comp[u]]; !ok || e.less(cur) {
+							best[comp[u]] = e
+						}
+					}
+				}
+			}(local[w], w*n/workers, (w+1)*n/workers)
+		}
+		wg.Wait()
+
+		cheapest := local[0]
+		for _, best := range local[1:] {
+			for c, e := range best {
+				if cur, ok := cheapest[c]; !ok || e.less(cur) {
+					cheapest[c] = e
+				}
+			}
+		}
+		if len(cheapest) == 0 {
+			return edges, nil
+		}
+		// Dwie składowe mogą wybrać tę samą krawędź; dodajemy ją raz
+		for v := 0; v < n; v++ {
+			e, ok := cheapest[v]
+			if !ok || uf.Find(e.u) == uf.Find(e.v) {
+				continue
+			}
+			uf.Union(e.u, e.v)
+			edges = append(edges, [2]int{e.u + 1, e.v + 1})
+			tr.emit(EventMSTEdge, e.weight, e.u+1, e.v+1)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Simikao/graphOptimalisation/internal/generate"
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+	"github.com/Simikao/graphOptimalisation/internal/validate"
+)
+
+var mstOptions = []g.MSTOptions{
+	{Algorithm: g.MSTKruskal},
+	{Algorithm: g.MSTPrim, Heap: g.BinaryHeap},
+	{Algorithm: g.MSTPrim, Heap: g.PairingHeap},
+	{Algorithm: g.MSTBoruvka, Workers: 1},
+	{Algorithm: g.MSTBoruvka, Workers: 4},
+}
+
+func mstName(opts g.MSTOptions) string {
+	switch opts.Algorithm {
+	case g.MSTPrim:
+		return opts.Algorithm.String() + "/" + opts.Heap.String()
+	case g.MSTBoruvka:
+		return fmt.Sprintf("%s/%d", opts.Algorithm, opts.Workers)
+	}
+	return opts.Algorithm.String()
+}
+
+func TestMinimumSpanningForest(t *testing.T) {
+	tests := []struct {
+		name       string
+		n          int
+		weighted   bool
+		edges      [][3]float64
+		weight     float64
+		components int
+	}{
+		{"empty", 0, true, nil, 0, 0},
+		{"single vertex", 1, true, nil, 0, 1},
+		{
+			// Krawędź 1-3 o wadze 3 przegrywa z 1-2 i 2-3
+			"weighted", 5, true,
+			[][3]float64{{1, 2, 1}, {2, 3, 2}, {1, 3, 3}, {3, 4, 4}, {2, 4, 5}, {4, 5, 1}, {3, 5, 6}},
+			8, 1,
+		},
+		{"unweighted", 4, false, [][3]float64{{1, 2}, {2, 3}, {3, 1}, {3, 4}}, 3, 1},
+		{"disconnected", 5, true, [][3]float64{{1, 2, 2}, {3, 4, 1}, {4, 5, 1}, {3, 5, 7}}, 4, 2},
+		{"negative weights and a loop", 3, true, [][3]float64{{1, 1, -9}, {1, 2, -2}, {2, 3, 4}, {1, 3, 0}}, -2, 1},
+	}
+	for _, tt := range tests {
+		graph := g.NewGraph(tt.n, false, tt.weighted)
+		for _, e := range tt.edges {
+			if tt.weighted {
+				graph.AddEdge(int(e[0]), int(e[1]), e[2])
+			} else {
+				graph.AddEdge(int(e[0]), int(e[1]))
+			}
+		}
+		for _, opts := range mstOptions {
+			t.Run(tt.name+"/"+mstName(opts), func(t *testing.T) {
+				forest, err := graph.MinimumSpanningForest(context.Background(), opts, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if forest.Weight != tt.weight || forest.Components != tt.components {
+					t.Errorf("got weight %g in %d components, want %g in %d", forest.Weight, forest.Components, tt.weight, tt.components)
+				}
+				if len(forest.Edges) != tt.n-tt.components {
+					t.Errorf("got %d edges, want %d", len(forest.Edges), tt.n-tt.components)
+				}
+				for _, e := range forest.Edges {
+					if e[0] >= e[1] || !graph.HasEdge(e[0], e[1]) {
+						t.Errorf("edge %v is not an edge (u, v) of the graph with u < v", e)
+					}
+					if forest.Component[e[0]-1] != forest.Component[e[1]-1] {
+						t.Errorf("edge %v joins components %d and %d", e, forest.Component[e[0]-1], forest.Component[e[1]-1])
+					}
+				}
+				if tt.components <= 1 {
+					if err := validate.VerifySpanningTree(&graph, forest.Edges); err != nil {
+						t.Error(err)
+					}
+				}
+
+				_, err = graph.MinimumSpanningTree(context.Background(), opts, nil)
+				if connected := tt.components <= 1; connected != (err == nil) {
+					t.Errorf("MinimumSpanningTree: got %v with %d components", err, tt.components)
+				} else if !connected && !errors.Is(err, g.ErrDisconnected) {
+					t.Errorf("MinimumSpanningTree: got %v, want %v", err, g.ErrDisconnected)
+				}
+			})
+		}
+	}
+}
+
+func TestMinimumSpanningForestDirected(t *testing.T) {
+	graph := g.NewGraph(2, true, false)
+	graph.AddEdge(1, 2)
+	for _, opts := range mstOptions {
+		if _, err := graph.MinimumSpanningForest(context.Background(), opts, nil); !errors.Is(err, g.ErrDirectedGraph) {
+			t.Errorf("%s: got %v, want %v", mstName(opts), err, g.ErrDirectedGraph)
+		}
+	}
+}
+
+func TestMinimumSpanningForestAlgorithmsAgree(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 20; seed++ {
+		gen := generate.New(seed)
+		gen.Weighted = true
+		// Małe wagi dają dużo remisów, w których algorytmy wybierają różne krawędzie
+		gen.MinWeight, gen.MaxWeight = -2, 3
+		graph, err := gen.ErdosRenyi(int(5+seed), 0.1+float64(seed%4)*0.15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := graph.MinimumSpanningForest(ctx, g.MSTOptions{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, opts := range mstOptions[1:] {
+			forest, err := graph.MinimumSpanningForest(ctx, opts, nil)
+			if err != nil {
+				t.Fatalf("seed %d: %s: %v", seed, mstName(opts), err)
+			}
+			if forest.Weight != want.Weight || forest.Components != want.Components {
+				t.Errorf("seed %d: %s gives weight %g in %d components, Kruskal %g in %d",
+					seed, mstName(opts), forest.Weight, forest.Components, want.Weight, want.Components)
+			}
+			for v := range want.Component {
+				if forest.Component[v] != want.Component[v] {
+					t.Errorf("seed %d: %s puts %d in component %d, Kruskal in %d", seed, mstName(opts), v+1, forest.Component[v], want.Component[v])
+					break
+				}
+			}
+			if forest.Spanning() {
+				if err := validate.VerifySpanningTree(&graph, forest.Edges); err != nil {
+					t.Errorf("seed %d: %s: %v", seed, mstName(opts), err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+const maxLineLength = 64 * 1024 * 1024
+
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	return scanner
+}
+
+// ReadMatrixMarket reads a coordinate Matrix Market (.mtx) file line by line.
+// "general" matrices become directed graphs, "symmetric" ones undirected;
+// "pattern" matrices are unweighted. Dense "array" files, complex values and
+// skew-symmetric/hermitian matrices are not graphs and are rejected.
+func ReadMatrixMarket(r io.Reader, opts ReadOptions) (*SparseGraph, error) {
+	scanner := newLineScanner(r)
+	lineNo := 0
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, parseErr(0, ErrInvalidHeader, "empty file")
+	}
+	lineNo++
+	banner := strings.Fields(strings.ToLower(scanner.Text()))
+	if len(banner) != 5 || banner[0] != "%%matrixmarket" || banner[1] != "matrix" {
+		return nil, parseErr(lineNo, ErrInvalidHeader, "expected %q banner", "%%MatrixMarket matrix coordinate <field> <symmetry>")
+	}
+	if banner[2] != "coordinate" {
+		return nil, parseErr(lineNo, ErrUnsupportedFormat, "only coordinate matrices are supported, got %q", banner[2])
+	}
+
+	var weighted bool
+	switch banner[3] {
+	case "real", "integer":
+		weighted = true
+	case "pattern":
+	default:
+		return nil, parseErr(lineNo, ErrUnsupportedFormat, "field %q", banner[3])
+	}
+
+	var directed bool
+	switch banner[4] {
+	case "general":
+		directed = true
+	case "symmetric":
+	default:
+		return nil, parseErr(lineNo, ErrUnsupportedFormat, "symmetry %q", banner[4])
+	}
+
+	var graph *SparseGraph
+	nnz, read := 0, 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "%") {
+			continue
+		}
+		fields := strings.Fields(line)
+
+		if graph == nil {
+			if len(fields) != 3 {
+				return nil, parseErr(lineNo, ErrInvalidHeader, "expected \"rows cols entries\"")
+			}
+			var size [3]int
+			for i, field := range fields {
+				num, err := strconv.Atoi(field)
+				if err != nil || num < 0 {
+					return nil, parseErr(lineNo, ErrInvalidHeader, "%q is not a non-negative integer", field)
+				}
+				size[i] = num
+			}
+			if size[0] != size[1] {
+				return nil, parseErr(lineNo, ErrNotSquare, "matrix is %dx%d", size[0], size[1])
+			}
+			if err := checkVertexLimit(opts.MaxVertices, size[0], lineNo); err != nil {
+				return nil, err
+			}
+			graph = NewSparseGraph(size[0], directed, weighted)
+			nnz = size[2]
+			continue
+		}
+
+		want := 2
+		if weighted {
+			want = 3
+		}
+		if len(fields) < want {
+			return nil, parseErr(lineNo, ErrMissingField, "expected %d fields, got %d", want, len(fields))
+		}
+		var ends [2]int
+		for i := range ends {
+			id, err := strconv.Atoi(fields[i])
+			if err != nil || id < 1 || id > graph.N() {
+				return nil, parseErr(lineNo, ErrInvalidVertex, "%q is not in 1..%d", fields[i], graph.N())
+			}
+			ends[i] = id
+		}
+		if weighted {
+			w, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, parseErr(lineNo, ErrInvalidWeight, "%q is not a number", fields[2])
+			}
+			graph.AddEdge(ends[0], ends[1], w)
+		} else {
+			graph.AddEdge(ends[0], ends[1])
+		}
+		read++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if graph == nil {
+		return nil, parseErr(0, ErrInvalidHeader, "missing size line")
+	}
+	if read != nnz {
+		return nil, parseErr(0, ErrHeaderMismatch, "size line declares %d entries but %d were read", nnz, read)
+	}
+	return graph, nil
+}
+
+// LoadMatrixMarket opens filename and reads it with ReadMatrixMarket.
+func LoadMatrixMarket(filename string) (*SparseGraph, error) {
+	return loadFile(filename, func(r io.Reader) (*SparseGraph, error) {
+		return ReadMatrixMarket(r, ReadOptions{})
+	})
+}
+
+// WriteMatrixMarket writes s as a coordinate Matrix Market file. Undirected
+// graphs are written as symmetric matrices (lower triangle only).
+func (s *SparseGraph) WriteMatrixMarket(w io.Writer) error {
+	field, symmetry := "pattern", "general"
+	if s.Weighted {
+		field = "real"
+	}
+	if !s.Directed {
+		symmetry = "symmetric"
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%%%%MatrixMarket matrix coordinate %s %s\n", field, symmetry)
+	fmt.Fprintf(bw, "%d %d %d\n", s.N(), s.N(), s.M)
+	for i, arcs := range s.Adj {
+		u := i + 1
+		for _, arc := range arcs {
+			row, col := u, arc.To
+			if !s.Directed && row < col {
+				continue
+			}
+			if s.Weighted {
+				fmt.Fprintf(bw, "%d %d %s\n", row, col, strconv.FormatFloat(arc.Weight, 'g', -1, 64))
+			} else {
+				fmt.Fprintf(bw, "%d %d\n", row, col)
+			}
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,136 @@
+package graph_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Simikao/graphOptimalisation/internal/generate"
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+// sparseEdges maps every edge of s to its weight.
+func sparseEdges(s *g.SparseGraph) map[[2]int]float64 {
+	edges := make(map[[2]int]float64, s.M)
+	for i, arcs := range s.Adj {
+		for _, arc := range arcs {
+			if s.Directed || i+1 <= arc.To {
+				edges[[2]int{i + 1, arc.To}] = arc.Weight
+			}
+		}
+	}
+	return edges
+}
+
+func checkSameSparse(t *testing.T, got, want *g.SparseGraph) {
+	t.Helper()
+	if got.N() != want.N() || got.M != want.M || got.Directed != want.Directed || got.Weighted != want.Weighted {
+		t.Fatalf("got n=%d m=%d directed=%t weighted=%t, want n=%d m=%d directed=%t weighted=%t",
+			got.N(), got.M, got.Directed, got.Weighted, want.N(), want.M, want.Directed, want.Weighted)
+	}
+	gotEdges := sparseEdges(got)
+	for e, w := range sparseEdges(want) {
+		if gw, ok := gotEdges[e]; !ok || gw != w {
+			t.Errorf("edge %d-%d: got weight %v (present %t), want %v", e[0], e[1], gw, ok, w)
+		}
+	}
+}
+
+func TestMatrixMarketRoundTrip(t *testing.T) {
+	for _, directed := range []bool{false, true} {
+		for _, weighted := range []bool{false, true} {
+			t.Run(fmt.Sprintf("directed=%t/weighted=%t", directed, weighted), func(t *testing.T) {
+				gen := generate.New(9)
+				gen.Directed, gen.Weighted = directed, weighted
+				graph, err := gen.ErdosRenyi(15, 0.3)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sparse := graph.ToSparse()
+				// Pętla i waga niecałkowita też muszą przetrwać zapis
+				sparse.AddEdge(4, 4, 0.1)
+				if weighted {
+					sparse.AddEdge(1, 15, 1.0/3)
+				}
+
+				var buf bytes.Buffer
+				if err := sparse.WriteMatrixMarket(&buf); err != nil {
+					t.Fatal(err)
+				}
+				back, err := g.ReadMatrixMarket(&buf, g.ReadOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				checkSameSparse(t, back, sparse)
+			})
+		}
+	}
+}
+
+func TestReadMatrixMarketErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"no banner", "3 3 1\n1 2 1\n", g.ErrInvalidHeader},
+		{"array", "%%MatrixMarket matrix array real general\n2 2\n1\n0\n0\n1\n", g.ErrUnsupportedFormat},
+		{"complex", "%%MatrixMarket matrix coordinate complex general\n2 2 0\n", g.ErrUnsupportedFormat},
+		{"skew-symmetric", "%%MatrixMarket matrix coordinate real skew-symmetric\n2 2 0\n", g.ErrUnsupportedFormat},
+		{"not square", "%%MatrixMarket matrix coordinate pattern general\n2 3 0\n", g.ErrNotSquare},
+		{"row out of range", "%%MatrixMarket matrix coordinate pattern general\n2 2 1\n3 1\n", g.ErrInvalidVertex},
+		{"missing value", "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2\n", g.ErrMissingField},
+		{"too few entries", "%%MatrixMarket matrix coordinate pattern general\n2 2 2\n1 2\n", g.ErrHeaderMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := g.ReadMatrixMarket(strings.NewReader(tt.input), g.ReadOptions{}); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMETISRoundTrip(t *testing.T) {
+	for _, weighted := range []bool{false, true} {
+		gen := generate.New(4)
+		gen.Weighted = weighted
+		graph, err := gen.BarabasiAlbert(30, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sparse := graph.ToSparse()
+		// Wierzchołek bez sąsiadów zapisuje się jako pusta linia
+		sparse.Adj = append(sparse.Adj, nil)
+
+		var buf bytes.Buffer
+		if err := sparse.WriteMETIS(&buf); err != nil {
+			t.Fatal(err)
+		}
+		back, err := g.ReadMETIS(&buf, g.ReadOptions{})
+		if err != nil {
+			t.Fatalf("weighted=%t: %v", weighted, err)
+		}
+		checkSameSparse(t, back, sparse)
+	}
+}
+
+func TestWriteMETISRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph *g.SparseGraph
+	}{
+		{"directed", g.NewSparseGraph(2, true, false).AddEdge(1, 2)},
+		{"self-loop", g.NewSparseGraph(2, false, false).AddEdge(2, 2)},
+		{"fractional weight", g.NewSparseGraph(2, false, true).AddEdge(1, 2, 1.5)},
+		{"zero weight", g.NewSparseGraph(2, false, true).AddEdge(1, 2, 0)},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := tt.graph.WriteMETIS(&buf); err == nil {
+			t.Errorf("%s: written as\n%s", tt.name, buf.String())
+		}
+	}
+}
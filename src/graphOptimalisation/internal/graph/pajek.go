@@ -0,0 +1,228 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// splitQuoted splits line on whitespace, keeping double-quoted strings
+// (without the quotes) as single fields.
+func splitQuoted(line string) ([]string, error) {
+	var fields []string
+	for {
+		line = strings.TrimLeft(line, " \t\r")
+		if line == "" {
+			return fields, nil
+		}
+		if line[0] == '"' {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string %s", line)
+			}
+			fields = append(fields, line[1:end+1])
+			line = line[end+2:]
+			continue
+		}
+		end := strings.IndexAny(line, " \t\r")
+		if end < 0 {
+			end = len(line)
+		}
+		fields = append(fields, line[:end])
+		line = line[end:]
+	}
+}
+
+// ReadPajek reads a Pajek .net file. Vertex labels and x/y coordinates come
+// from the *Vertices section; *Arcs are directed and *Edges undirected edges. A file with arcs
+// yields a directed graph in which every *Edges entry becomes two arcs. The
+// graph is weighted if any edge line carries a value; edges without one get
+// Pajek's default weight 1.
+func ReadPajek(r io.Reader, opts ReadOptions) (Graph, error) {
+	type rawEdge struct {
+		u, v     int
+		weight   float64
+		directed bool
+	}
+
+	var (
+		n        = -1
+		labels   []string
+		coords   [][2]float64
+		hasXY    bool
+		edges    []rawEdge
+		section  string
+		hasArcs  bool
+		weighted bool
+		lineNo   int
+	)
+
+	scanner := newLineScanner(r)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "%") {
+			continue
+		}
+
+		if line[0] == '*' {
+			fields := strings.Fields(line)
+			section = strings.ToLower(fields[0])
+			switch section {
+			case "*network":
+			case "*vertices":
+				if len(fields) < 2 {
+					return Graph{}, parseErr(lineNo, ErrInvalidHeader, "*Vertices needs a vertex count")
+				}
+				num, err := strconv.Atoi(fields[1])
+				if err != nil || num < 0 {
+					return Graph{}, parseErr(lineNo, ErrInvalidHeader, "%q is not a non-negative integer", fields[1])
+				}
+				if err := checkVertexLimit(opts.MaxVertices, num, lineNo); err != nil {
+					return Graph{}, err
+				}
+				n = num
+				labels = make([]string, n)
+				coords = make([][2]float64, n)
+			case "*arcs", "*arcslist":
+				hasArcs = true
+			case "*edges", "*edgeslist":
+			default:
+				return Graph{}, parseErr(lineNo, ErrUnsupportedFormat, "section %s", fields[0])
+			}
+			if section != "*network" && section != "*vertices" && n < 0 {
+				return Graph{}, parseErr(lineNo, ErrInvalidHeader, "%s before *Vertices", fields[0])
+			}
+			continue
+		}
+
+		fields, err := splitQuoted(line)
+		if err != nil {
+			return Graph{}, parseErr(lineNo, ErrMissingField, "%v", err)
+		}
+
+		vertex := func(field string) (int, error) {
+			id, err := strconv.Atoi(field)
+			if err != nil || id < 1 || id > n {
+				return 0, parseErr(lineNo, ErrInvalidVertex, "%q is not in 1..%d", field, n)
+			}
+			return id, nil
+		}
+
+		switch section {
+		case "*vertices":
+			id, err := vertex(fields[0])
+			if err != nil {
+				return Graph{}, err
+			}
+			if len(fields) > 1 {
+				labels[id-1] = fields[1]
+			}
+			if len(fields) > 3 {
+				for i := range 2 {
+					if coords[id-1][i], err = strconv.ParseFloat(fields[2+i], 64); err != nil {
+						return Graph{}, parseErr(lineNo, ErrInvalidEntry, "coordinate %q is not a number", fields[2+i])
+					}
+				}
+				hasXY = true
+			}
+		case "*arcs", "*edges":
+			if len(fields) < 2 {
+				return Graph{}, parseErr(lineNo, ErrMissingField, "expected at least 2 fields, got %d", len(fields))
+			}
+			e := rawEdge{weight: 1, directed: section == "*arcs"}
+			if e.u, err = vertex(fields[0]); err != nil {
+				return Graph{}, err
+			}
+			if e.v, err = vertex(fields[1]); err != nil {
+				return Graph{}, err
+			}
+			if len(fields) > 2 {
+				if e.weight, err = strconv.ParseFloat(fields[2], 64); err != nil {
+					return Graph{}, parseErr(lineNo, ErrInvalidWeight, "%q is not a number", fields[2])
+				}
+				weighted = true
+			}
+			edges = append(edges, e)
+		case "*arcslist", "*edgeslist":
+			u, err := vertex(fields[0])
+			if err != nil {
+				return Graph{}, err
+			}
+			for _, field := range fields[1:] {
+				v, err := vertex(field)
+				if err != nil {
+					return Graph{}, err
+				}
+				edges = append(edges, rawEdge{u: u, v: v, weight: 1, directed: section == "*arcslist"})
+			}
+		default:
+			return Graph{}, parseErr(lineNo, ErrInvalidHeader, "data outside of a section")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Graph{}, err
+	}
+	if n < 0 {
+		return Graph{}, parseErr(0, ErrInvalidHeader, "missing *Vertices section")
+	}
+
+	graph := NewGraph(n, hasArcs, weighted)
+	for _, e := range edges {
+		graph.AddEdge(e.u, e.v, e.weight)
+		if hasArcs && !e.directed && e.u != e.v {
+			graph.AddEdge(e.v, e.u, e.weight)
+		}
+	}
+	for _, label := range labels {
+		if label != "" {
+			graph.Labels = labels
+			break
+		}
+	}
+	if hasXY {
+		graph.Coords = coords
+	}
+	return graph, nil
+}
+
+// LoadPajek opens filename and reads it with ReadPajek.
+func LoadPajek(filename string) (Graph, error) {
+	return loadFile(filename, func(r io.Reader) (Graph, error) {
+		return ReadPajek(r, ReadOptions{})
+	})
+}
+
+// WritePajek writes g as a Pajek .net file, using *Arcs for directed and
+// *Edges for undirected graphs.
+func (g *Graph) WritePajek(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "*Vertices %d\n", len(g.AdjMatrix))
+	for v := 1; v <= len(g.AdjMatrix); v++ {
+		switch {
+		case g.Coords != nil:
+			fmt.Fprintf(bw, "%d \"%s\" %g %g\n", v, strings.ReplaceAll(g.Label(v), "\"", "'"), g.Coords[v-1][0], g.Coords[v-1][1])
+		case v-1 < len(g.Labels) && g.Labels[v-1] != "":
+			fmt.Fprintf(bw, "%d \"%s\"\n", v, strings.ReplaceAll(g.Labels[v-1], "\"", "'"))
+		default:
+			fmt.Fprintf(bw, "%d\n", v)
+		}
+	}
+
+	if g.Directed {
+		bw.WriteString("*Arcs\n")
+	} else {
+		bw.WriteString("*Edges\n")
+	}
+	for _, edge := range g.Edges {
+		if g.Weighted {
+			weight := g.WeightMatrix[edge[0]-1][edge[1]-1]
+			fmt.Fprintf(bw, "%d %d %s\n", edge[0], edge[1], strconv.FormatFloat(weight, 'g', -1, 64))
+		} else {
+			fmt.Fprintf(bw, "%d %d\n", edge[0], edge[1])
+		}
+	}
+	return bw.Flush()
+}
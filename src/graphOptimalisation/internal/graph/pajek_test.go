@@ -0,0 +1,111 @@
+package graph_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Simikao/graphOptimalisation/internal/generate"
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+var labelledFormats = []struct {
+	name  string
+	write func(*g.Graph, io.Writer) error
+	read  func(io.Reader) (g.Graph, error)
+}{
+	{"pajek", (*g.Graph).WritePajek, func(r io.Reader) (g.Graph, error) { return g.ReadPajek(r, g.ReadOptions{}) }},
+	{"gml", (*g.Graph).WriteGML, func(r io.Reader) (g.Graph, error) { return g.ReadGML(r, g.ReadOptions{}) }},
+}
+
+func TestLabelledFormatsRoundTrip(t *testing.T) {
+	for _, format := range labelledFormats {
+		for _, directed := range []bool{false, true} {
+			for _, weighted := range []bool{false, true} {
+				t.Run(fmt.Sprintf("%s/directed=%t/weighted=%t", format.name, directed, weighted), func(t *testing.T) {
+					gen := generate.New(6)
+					gen.Directed, gen.Weighted = directed, weighted
+					graph, err := gen.ErdosRenyi(12, 0.3)
+					if err != nil {
+						t.Fatal(err)
+					}
+					graph.AddVertex()
+					graph.AddEdge(5, 5, 2.5)
+					graph.Labels = make([]string, 13)
+					graph.Coords = make([][2]float64, 13)
+					for i := range graph.Labels {
+						graph.Labels[i] = fmt.Sprintf("city %c", 'A'+i)
+						graph.Coords[i] = [2]float64{float64(i) / 3, -1.0 / float64(i+1)}
+					}
+
+					var buf bytes.Buffer
+					if err := format.write(&graph, &buf); err != nil {
+						t.Fatal(err)
+					}
+					back, err := format.read(&buf)
+					if err != nil {
+						t.Fatal(err)
+					}
+					checkSameSparse(t, back.ToSparse(), graph.ToSparse())
+					for v := 1; v <= 13; v++ {
+						if back.Label(v) != graph.Label(v) {
+							t.Errorf("vertex %d: label %q, want %q", v, back.Label(v), graph.Label(v))
+						}
+					}
+					if len(back.Coords) != 13 {
+						t.Fatalf("got %d coordinates, want 13", len(back.Coords))
+					}
+					for i := range graph.Coords {
+						if back.Coords[i] != graph.Coords[i] {
+							t.Errorf("vertex %d: coordinates %v, want %v", i+1, back.Coords[i], graph.Coords[i])
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestLabelledFormatsWithoutExtras(t *testing.T) {
+	graph := g.NewGraph(3, false, false)
+	graph.AddEdge(1, 2).AddEdge(2, 3)
+	for _, format := range labelledFormats {
+		var buf bytes.Buffer
+		if err := format.write(&graph, &buf); err != nil {
+			t.Fatal(err)
+		}
+		back, err := format.read(&buf)
+		if err != nil {
+			t.Fatalf("%s: %v", format.name, err)
+		}
+		if back.Labels != nil || back.Coords != nil || back.Weighted {
+			t.Errorf("%s: got labels %v, coordinates %v, weighted=%t; want none", format.name, back.Labels, back.Coords, back.Weighted)
+		}
+		checkSameSparse(t, back.ToSparse(), graph.ToSparse())
+	}
+}
+
+func TestReadPajekMixedSections(t *testing.T) {
+	// *Edges w pliku z *Arcs to łuki w obu kierunkach
+	input := "*Vertices 3\n1 \"a b\"\n2\n3\n*Arcs\n1 2 4\n*Edges\n2 3\n"
+	graph, err := g.ReadPajek(strings.NewReader(input), g.ReadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !graph.Directed || !graph.Weighted {
+		t.Fatalf("got directed=%t weighted=%t, want both", graph.Directed, graph.Weighted)
+	}
+	for _, e := range [][3]float64{{1, 2, 4}, {2, 3, 1}, {3, 2, 1}} {
+		if w, err := graph.GetWeight(int(e[0]), int(e[1])); err != nil || w != e[2] {
+			t.Errorf("edge %v-%v: got %v, %v; want weight %v", e[0], e[1], w, err, e[2])
+		}
+	}
+	if graph.HasEdge(2, 1) {
+		t.Error("arc 1->2 also added as 2->1")
+	}
+	if graph.Label(1) != "a b" {
+		t.Errorf("label of 1 is %q, want %q", graph.Label(1), "a b")
+	}
+}
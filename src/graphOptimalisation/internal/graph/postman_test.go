@@ -0,0 +1,55 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+	"github.com/Simikao/graphOptimalisation/internal/validate"
+)
+
+func TestChinesePostmanProblem(t *testing.T) {
+	build := func(n int, edges [][3]float64) g.Graph {
+		graph := g.NewGraph(n, false, true)
+		for _, e := range edges {
+			graph.AddEdge(int(e[0]), int(e[1]), e[2])
+		}
+		return graph
+	}
+	tests := []struct {
+		name  string
+		graph g.Graph
+		cost  float64
+		err   error
+	}{
+		{"Eulerian square", build(4, [][3]float64{{1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 1, 1}}), 4, nil},
+		// Wierzchołki 1 i 3 są nieparzyste; najtańsza ścieżka między nimi ma koszt 2
+		{"square with a chord", build(4, [][3]float64{{1, 2, 1}, {2, 3, 1}, {3, 4, 1}, {4, 1, 1}, {1, 3, 3}}), 9, nil},
+		{"path", build(3, [][3]float64{{1, 2, 2}, {2, 3, 5}}), 14, nil},
+		{"isolated first vertex", build(4, [][3]float64{{2, 3, 1}, {3, 4, 1}, {4, 2, 1}}), 3, nil},
+		{"no edges", build(2, nil), 0, nil},
+		{"empty", build(0, nil), 0, nil},
+		{"two components", build(4, [][3]float64{{1, 2, 1}, {3, 4, 1}}), 0, g.ErrDisconnected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.graph.Clone()
+			circuit, cost, err := tt.graph.ChinesePostmanProblem(context.Background(), nil, nil)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if cost != tt.cost {
+				t.Errorf("got cost %g, want %g", cost, tt.cost)
+			}
+			if len(original.Edges) > 0 {
+				if err := validate.VerifyPostmanCircuit(&original, circuit); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
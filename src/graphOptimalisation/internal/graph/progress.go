@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// Progress describes how far a long-running algorithm has got. Done and
+// Total are in units of the current phase (e.g. rows of the distance matrix
+// or search nodes); Total is 0 if unknown. Best is the cost of the best
+// solution found so far, or NaN if there is none yet.
+type Progress struct {
+	Algorithm string
+	Phase     string
+	Done      int
+	Total     int
+	Best      float64
+}
+
+func (p Progress) String() string {
+	s := fmt.Sprintf("%s: %s %d", p.Algorithm, p.Phase, p.Done)
+	if p.Total > 0 {
+		s += fmt.Sprintf("/%d (%.0f%%)", p.Total, 100*float64(p.Done)/float64(p.Total))
+	}
+	if !math.IsNaN(p.Best) {
+		s += fmt.Sprintf(", best %g", p.Best)
+	}
+	return s
+}
+
+// ProgressFunc receives progress reports. The long-running algorithms take
+// one as a parameter or an option; it is called from the goroutine running
+// the algorithm and should return quickly. A nil ProgressFunc is ignored.
+type ProgressFunc func(p Progress)
+
+// checkEvery is how many inner iterations pass between checks of ctx.
+const checkEvery = 1 << 10
+
+// reporter sends progress for one algorithm and checks for cancellation.
+type reporter struct {
+	ctx       context.Context
+	fn        ProgressFunc
+	algorithm string
+}
+
+// newReporter returns a reporter sending progress to fn, which may be nil.
+func newReporter(ctx context.Context, fn ProgressFunc, algorithm string) reporter {
+	return reporter{ctx: ctx, fn: fn, algorithm: algorithm}
+}
+
+func (r reporter) report(phase string, done, total int, best float64) {
+	if r.fn != nil {
+		r.fn(Progress{Algorithm: r.algorithm, Phase: phase, Done: done, Total: total, Best: best})
+	}
+}
+
+// step reports progress without a best value and returns ctx.Err().
+func (r reporter) step(phase string, done, total int) error {
+	r.report(phase, done, total, math.NaN())
+	return r.ctx.Err()
+}
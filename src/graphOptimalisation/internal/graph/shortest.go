@@ -0,0 +1,251 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var ErrNoCoordinates = errors.New("graph has no vertex coordinates")
+
+// ShortestPaths is a shortest-path tree from Source. Vertices are 1-based
+// as everywhere else; Dist and Pred are indexed by v-1.
+type ShortestPaths struct {
+	Source int
+	// Dist[v-1] is the length of the shortest path to v, or +Inf if v was
+	// not reached.
+	Dist []float64
+	// Pred[v-1] is the vertex before v on that path, or 0 for the source
+	// and unreached vertices.
+	Pred []int
+}
+
+func newShortestPaths(n, source int) *ShortestPaths {
+	sp := &ShortestPaths{Source: source, Dist: make([]float64, n), Pred: make([]int, n)}
+	for i := range sp.Dist {
+		sp.Dist[i] = math.Inf(1)
+	}
+	sp.Dist[source-1] = 0
+	return sp
+}
+
+// Distance returns the length of the shortest path to v (+Inf if none).
+func (sp *ShortestPaths) Distance(v int) float64 {
+	return sp.Dist[v-1]
+}
+
+// PathTo returns the vertices of the shortest path from Source to v, both
+// included, or nil if v is unreachable.
+func (sp *ShortestPaths) PathTo(v int) []int {
+	if math.IsInf(sp.Dist[v-1], 1) {
+		return nil
+	}
+	var path []int
+	for u := v; u != 0; u = sp.Pred[u-1] {
+		path = append(path, u)
+		if len(path) > len(sp.Pred) {
+			return nil // Cykl w poprzednikach; nie powinien się zdarzyć
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// arc is an outgoing edge in an adjacency list (0-based target).
+type arc struct {
+	to     int
+	weight float64
+}
+
+// arcs builds adjacency lists from the adjacency matrix; undirected edges
+// appear in both directions.
+func (g *Graph) arcs() [][]arc {
+	out := make([][]arc, len(g.AdjMatrix))
+	for u, row := range g.AdjMatrix {
+		for v, present := range row {
+			if present > 0 {
+				out[u] = append(out[u], arc{v, g.edgeWeight(u+1, v+1)})
+			}
+		}
+	}
+	return out
+}
+
+// CheckVertex returns an error wrapping ErrVertexOutOfRange unless v is a
+// vertex of g.
+func (g *Graph) CheckVertex(v int) error {
+	if v < 1 || v > len(g.AdjMatrix) {
+		return fmt.Errorf("%w: %d (graph has %d vertices)", ErrVertexOutOfRange, v, len(g.AdjMatrix))
+	}
+	return nil
+}
+
+// Dijkstra computes shortest paths from source with the given priority
+// queue. It requires non-negative weights. If ctx is cancelled it returns the
+// distances settled so far (the others may be too large) and ctx.Err().
+func (g *Graph) Dijkstra(ctx context.Context, source int, heap HeapKind) (*ShortestPaths, error) {
+	if err := g.CheckVertex(source); err != nil {
+		return nil, fmt.Errorf("Dijkstra: %w", err)
+	}
+	if err := g.requireNonNegative("Dijkstra"); err != nil {
+		return nil, err
+	}
+	return search(reporter{ctx: ctx}, g.arcs(), source, 0, heap, nil), ctx.Err()
+}
+
+// Heuristic estimates the distance from v to target (both 1-based). A* finds
+// shortest paths if the estimate is consistent: h(u) <= w(u, v) + h(v) for
+// every edge and h(target) = 0.
+type Heuristic func(v, target int) float64
+
+// ZeroHeuristic turns A* into Dijkstra.
+func ZeroHeuristic(v, target int) float64 { return 0 }
+
+// EuclideanHeuristic is the straight-line distance between the coordinates
+// of the vertices. It is admissible when no edge is shorter than the
+// distance between its endpoints, e.g. in geometric graphs.
+func (g *Graph) EuclideanHeuristic() (Heuristic, error) {
+	if len(g.Coords) != len(g.AdjMatrix) {
+		return nil, ErrNoCoordinates
+	}
+	coords := g.Coords
+	return func(v, target int) float64 {
+		p, q := coords[v-1], coords[target-1]
+		return math.Hypot(p[0]-q[0], p[1]-q[1])
+	}, nil
+}
+
+// AStar searches for a shortest path from source to target guided by h (nil
+// means ZeroHeuristic) and stops when target is settled. The returned tree
+// covers only the vertices explored on the way; use PathTo(target). It
+// requires non-negative weights. If ctx is cancelled it returns the tree
+// explored so far and ctx.Err().
+func (g *Graph) AStar(ctx context.Context, source, target int, h Heuristic) (*ShortestPaths, error) {
+	for _, v := range []int{source, target} {
+		if err := g.CheckVertex(v); err != nil {
+			return nil, fmt.Errorf("AStar: %w", err)
+		}
+	}
+	if err := g.requireNonNegative("AStar"); err != nil {
+		return nil, err
+	}
+	if h == nil {
+		h = ZeroHeuristic
+	}
+	return search(reporter{ctx: ctx}, g.arcs(), source, target, BinaryHeap, h), ctx.Err()
+}
+
+// search is Dijkstra over adj when h is nil and A* otherwise; a target of 0
+// means every reachable vertex. It stops early when rep.ctx is cancelled.
+func search(rep reporter, adj [][]arc, source, target int, heap HeapKind, h Heuristic) *ShortestPaths {
+	n := len(adj)
+	sp := newShortestPaths(n, source)
+	settled := make([]bool, n)
+
+	estimate := func(v int) float64 {
+		if h == nil {
+			return sp.Dist[v]
+		}
+		return sp.Dist[v] + h(v+1, target)
+	}
+	queue := newPriorityQueue(heap, n)
+	queue.push(source-1, estimate(source-1))
+	for done := 0; queue.len() > 0; done++ {
+		if done%checkEvery == 0 && rep.step("settling vertices", done, n) != nil {
+			return sp
+		}
+		u, _ := queue.pop()
+		settled[u] = true
+		if u == target-1 {
+			break
+		}
+		for _, a := range adj[u] {
+			if settled[a.to] {
+				continue
+			}
+			if d := sp.Dist[u] + a.weight; d < sp.Dist[a.to] {
+				sp.Dist[a.to] = d
+				sp.Pred[a.to] = u + 1
+				queue.push(a.to, estimate(a.to))
+			}
+		}
+	}
+	return sp
+}
+
+// NegativeCycleError is returned by BellmanFord when a negative cycle is
+// reachable from the source.
+type NegativeCycleError struct {
+	// Cycle lists the vertices of the cycle, the first repeated at the end.
+	Cycle []int
+}
+
+func (e *NegativeCycleError) Error() string {
+	parts := make([]string, len(e.Cycle))
+	for i, v := range e.Cycle {
+		parts[i] = fmt.Sprint(v)
+	}
+	return fmt.Sprintf("%v: %s", ErrNegativeCycle, strings.Join(parts, " -> "))
+}
+
+func (e *NegativeCycleError) Unwrap() error { return ErrNegativeCycle }
+
+// BellmanFord computes shortest paths from source with arbitrary weights.
+// If a negative cycle is reachable from source it returns a
+// *NegativeCycleError holding the cycle. Note that in an undirected graph
+// every negative edge forms such a cycle. If ctx is cancelled it returns the
+// current, not yet final, distances and ctx.Err().
+func (g *Graph) BellmanFord(ctx context.Context, source int) (*ShortestPaths, error) {
+	if err := g.CheckVertex(source); err != nil {
+		return nil, fmt.Errorf("BellmanFord: %w", err)
+	}
+	return bellmanFord(reporter{ctx: ctx}, g.arcs(), source)
+}
+
+func bellmanFord(rep reporter, adj [][]arc, source int) (*ShortestPaths, error) {
+	n := len(adj)
+	sp := newShortestPaths(n, source)
+
+	// Po n-1 rundach odległości są ostateczne, chyba że istnieje ujemny cykl
+	last := -1
+	for round := 0; round < n; round++ {
+		if err := rep.step("relaxing edges", round, n); err != nil {
+			return sp, err
+		}
+		last = -1
+		for u := range adj {
+			if math.IsInf(sp.Dist[u], 1) {
+				continue
+			}
+			for _, a := range adj[u] {
+				if d := sp.Dist[u] + a.weight; d < sp.Dist[a.to] {
+					sp.Dist[a.to] = d
+					sp.Pred[a.to] = u + 1
+					last = a.to
+				}
+			}
+		}
+		if last < 0 {
+			return sp, nil
+		}
+	}
+
+	// Zmiana w n-tej rundzie: cofając się n razy po poprzednikach trafiamy na cykl
+	v := last + 1
+	for i := 0; i < n; i++ {
+		v = sp.Pred[v-1]
+	}
+	cycle := []int{v}
+	for u := sp.Pred[v-1]; u != v; u = sp.Pred[u-1] {
+		cycle = append(cycle, u)
+	}
+	cycle = append(cycle, v)
+	for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+		cycle[i], cycle[j] = cycle[j], cycle[i]
+	}
+	return sp, &NegativeCycleError{Cycle: cycle}
+}
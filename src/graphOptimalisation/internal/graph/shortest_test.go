@@ -0,0 +1,143 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Simikao/graphOptimalisation/internal/generate"
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+func TestSingleSourceShortestPaths(t *testing.T) {
+	inf := math.Inf(1)
+	// 1 -> 2 -> 4 jest krótsze niż bezpośrednia krawędź 1 -> 4; 5 jest nieosiągalny
+	graph := g.NewGraph(5, true, true)
+	graph.AddEdge(1, 2, 2).AddEdge(2, 4, 3).AddEdge(1, 4, 7).AddEdge(1, 3, 1).AddEdge(3, 2, 0).AddEdge(5, 1, 1)
+	want := []float64{0, 1, 1, 4, inf}
+	wantPath := []int{1, 3, 2, 4}
+
+	ctx := context.Background()
+	run := map[string]func() (*g.ShortestPaths, error){
+		"dijkstra/binary":  func() (*g.ShortestPaths, error) { return graph.Dijkstra(ctx, 1, g.BinaryHeap) },
+		"dijkstra/pairing": func() (*g.ShortestPaths, error) { return graph.Dijkstra(ctx, 1, g.PairingHeap) },
+		"bellman-ford":     func() (*g.ShortestPaths, error) { return graph.BellmanFord(ctx, 1) },
+	}
+	for name, f := range run {
+		t.Run(name, func(t *testing.T) {
+			sp, err := f()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for v, d := range want {
+				if got := sp.Distance(v + 1); got != d {
+					t.Errorf("distance to %d = %g, want %g", v+1, got, d)
+				}
+			}
+			if got := sp.PathTo(4); !equalInts(got, wantPath) {
+				t.Errorf("path to 4 = %v, want %v", got, wantPath)
+			}
+			if got := sp.PathTo(5); got != nil {
+				t.Errorf("path to the unreachable 5 = %v, want nil", got)
+			}
+		})
+	}
+
+	sp, err := graph.AStar(ctx, 1, 4, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sp.PathTo(4); !equalInts(got, wantPath) {
+		t.Errorf("A*: path to 4 = %v, want %v", got, wantPath)
+	}
+}
+
+func TestShortestPathsSingleVertex(t *testing.T) {
+	graph := g.NewGraph(1, false, false)
+	for _, heap := range []g.HeapKind{g.BinaryHeap, g.PairingHeap} {
+		sp, err := graph.Dijkstra(context.Background(), 1, heap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sp.Distance(1) != 0 || !equalInts(sp.PathTo(1), []int{1}) {
+			t.Errorf("%s: got distance %g and path %v", heap, sp.Distance(1), sp.PathTo(1))
+		}
+	}
+}
+
+func TestShortestPathsErrors(t *testing.T) {
+	ctx := context.Background()
+	empty := g.NewGraph(0, false, false)
+	if _, err := empty.Dijkstra(ctx, 1, g.BinaryHeap); !errors.Is(err, g.ErrVertexOutOfRange) {
+		t.Errorf("Dijkstra on the empty graph: got %v, want %v", err, g.ErrVertexOutOfRange)
+	}
+
+	negative := g.NewGraph(2, true, true)
+	negative.AddEdge(1, 2, -1)
+	if _, err := negative.Dijkstra(ctx, 1, g.PairingHeap); !errors.Is(err, g.ErrNegativeWeight) {
+		t.Errorf("Dijkstra with a negative edge: got %v, want %v", err, g.ErrNegativeWeight)
+	}
+	if _, err := negative.AStar(ctx, 1, 2, nil); !errors.Is(err, g.ErrNegativeWeight) {
+		t.Errorf("A* with a negative edge: got %v, want %v", err, g.ErrNegativeWeight)
+	}
+
+	cycle := g.NewGraph(3, true, true)
+	cycle.AddEdge(1, 2, 1).AddEdge(2, 3, -3).AddEdge(3, 2, 1)
+	_, err := cycle.BellmanFord(ctx, 1)
+	var cerr *g.NegativeCycleError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("got %v, want a *NegativeCycleError", err)
+	}
+	if c := cerr.Cycle; len(c) < 3 || c[0] != c[len(c)-1] {
+		t.Errorf("cycle %v is not closed", c)
+	}
+}
+
+func TestShortestPathsAgree(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 20; seed++ {
+		gen := generate.New(seed)
+		gen.Weighted, gen.Directed = true, seed%2 == 1
+		gen.MinWeight = 0
+		graph, err := gen.ErdosRenyi(int(2+seed), 0.15+float64(seed%4)*0.15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bf, err := graph.BellmanFord(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, heap := range []g.HeapKind{g.BinaryHeap, g.PairingHeap} {
+			sp, err := graph.Dijkstra(ctx, 1, heap)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for v := range bf.Dist {
+				if sp.Dist[v] != bf.Dist[v] {
+					t.Errorf("seed %d: %s heap gives distance %g to %d, Bellman-Ford %g", seed, heap, sp.Dist[v], v+1, bf.Dist[v])
+				}
+			}
+		}
+		target := len(graph.AdjMatrix)
+		astar, err := graph.AStar(ctx, 1, target, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if astar.Distance(target) != bf.Distance(target) {
+			t.Errorf("seed %d: A* gives distance %g to %d, Bellman-Ford %g", seed, astar.Distance(target), target, bf.Distance(target))
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
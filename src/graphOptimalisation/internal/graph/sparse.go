@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+)
+
+// Arc is an outgoing edge in a SparseGraph.
+type Arc struct {
+	To     int
+	Weight float64
+}
+
+// SparseGraph stores a graph as adjacency lists. It is meant for inputs that
+// are too large for the dense matrices of Graph; vertices are 1-based like
+// everywhere else in the package.
+type SparseGraph struct {
+	Adj      [][]Arc
+	Directed bool
+	Weighted bool
+	// Number of edges (arcs for directed graphs); an undirected edge is
+	// stored in both adjacency lists but counted once.
+	M int
+}
+
+func NewSparseGraph(vertices int, directed, weighted bool) *SparseGraph {
+	return &SparseGraph{
+		Adj:      make([][]Arc, vertices),
+		Directed: directed,
+		Weighted: weighted,
+	}
+}
+
+func (s *SparseGraph) N() int {
+	return len(s.Adj)
+}
+
+func (s *SparseGraph) AddEdge(u, v int, weight ...float64) *SparseGraph {
+	w := 1.0
+	if s.Weighted && len(weight) > 0 {
+		w = weight[0]
+	}
+
+	s.Adj[u-1] = append(s.Adj[u-1], Arc{To: v, Weight: w})
+	if !s.Directed && u != v {
+		s.Adj[v-1] = append(s.Adj[v-1], Arc{To: u, Weight: w})
+	}
+	s.M++
+	return s
+}
+
+// Edges lists every edge once, in the same orientation convention as
+// Graph.Edges (u <= v for undirected graphs).
+func (s *SparseGraph) Edges() [][2]int {
+	edges := make([][2]int, 0, s.M)
+	for i, arcs := range s.Adj {
+		u := i + 1
+		for _, arc := range arcs {
+			if s.Directed || u <= arc.To {
+				edges = append(edges, [2]int{u, arc.To})
+			}
+		}
+	}
+	return edges
+}
+
+// ToGraph converts s into the dense representation used by the algorithms.
+func (s *SparseGraph) ToGraph() Graph {
+	graph := NewGraph(s.N(), s.Directed, s.Weighted)
+	for i, arcs := range s.Adj {
+		u := i + 1
+		for _, arc := range arcs {
+			if !s.Directed && arc.To < u {
+				continue
+			}
+			if s.Weighted {
+				graph.AddEdge(u, arc.To, arc.Weight)
+			} else {
+				graph.AddEdge(u, arc.To)
+			}
+		}
+	}
+	return graph
+}
+
+// ToSparse converts g into adjacency lists.
+func (g *Graph) ToSparse() *SparseGraph {
+	s := NewSparseGraph(len(g.AdjMatrix), g.Directed, g.Weighted)
+	for _, edge := range g.Edges {
+		if g.Weighted {
+			s.AddEdge(edge[0], edge[1], g.WeightMatrix[edge[0]-1][edge[1]-1])
+		} else {
+			s.AddEdge(edge[0], edge[1])
+		}
+	}
+	return s
+}
+
+// CheckVertex returns an error wrapping ErrVertexOutOfRange unless v is a
+// vertex of s.
+func (s *SparseGraph) CheckVertex(v int) error {
+	if v < 1 || v > s.N() {
+		return fmt.Errorf("%w: %d (graph has %d vertices)", ErrVertexOutOfRange, v, s.N())
+	}
+	return nil
+}
+
+// arcs converts the adjacency lists to the 0-based form the searches use.
+func (s *SparseGraph) arcs() [][]arc {
+	out := make([][]arc, s.N())
+	for u, arcs := range s.Adj {
+		out[u] = make([]arc, len(arcs))
+		for i, a := range arcs {
+			out[u][i] = arc{a.To - 1, a.Weight}
+		}
+	}
+	return out
+}
+
+// Dijkstra is Graph.Dijkstra on adjacency lists, in O(m log n) time and
+// O(n + m) memory.
+func (s *SparseGraph) Dijkstra(ctx context.Context, source int, heap HeapKind) (*ShortestPaths, error) {
+	if err := s.CheckVertex(source); err != nil {
+		return nil, fmt.Errorf("Dijkstra: %w", err)
+	}
+	for u, arcs := range s.Adj {
+		for _, a := range arcs {
+			if a.Weight < 0 {
+				return nil, fmt.Errorf("Dijkstra: %w on edge (%d, %d): %g", ErrNegativeWeight, u+1, a.To, a.Weight)
+			}
+		}
+	}
+	return search(reporter{ctx: ctx}, s.arcs(), source, 0, heap, nil), ctx.Err()
+}
+
+// BellmanFord is Graph.BellmanFord on adjacency lists, in O(nm) time and
+// O(n + m) memory.
+func (s *SparseGraph) BellmanFord(ctx context.Context, source int) (*ShortestPaths, error) {
+	if err := s.CheckVertex(source); err != nil {
+		return nil, fmt.Errorf("BellmanFord: %w", err)
+	}
+	return bellmanFord(reporter{ctx: ctx}, s.arcs(), source)
+}
+
+// MinimumSpanningForest is Graph.MinimumSpanningForest with Kruskal's
+// algorithm on adjacency lists. Progress goes to progress, which may be nil.
+func (s *SparseGraph) MinimumSpanningForest(ctx context.Context, progress ProgressFunc, t Tracer) (*SpanningForest, error) {
+	if s.Directed {
+		return nil, fmt.Errorf("MinimumSpanningForest: %w", ErrDirectedGraph)
+	}
+	edges := make([]weightedEdge, 0, s.M)
+	for u, arcs := range s.Adj {
+		for _, a := range arcs {
+			if u < a.To-1 {
+				edges = append(edges, weightedEdge{u, a.To - 1, a.Weight})
+			}
+		}
+	}
+	tr := newTracer(t, "mst")
+	chosen, err := kruskal(tr, newReporter(ctx, progress, "kruskal"), s.N(), edges)
+	forest := newSpanningForest(s.N(), chosen)
+	if err == nil {
+		tr.emit(EventResult, forest.Weight, flattenEdges(forest.Edges)...)
+	}
+	return forest, err
+}
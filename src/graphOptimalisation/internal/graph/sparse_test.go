@@ -0,0 +1,134 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Simikao/graphOptimalisation/internal/generate"
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+func TestSparseMatchesDense(t *testing.T) {
+	ctx := context.Background()
+	for seed := int64(1); seed <= 20; seed++ {
+		gen := generate.New(seed)
+		gen.Weighted, gen.Directed = true, seed%2 == 0
+		graph, err := gen.ErdosRenyi(int(5+seed), 0.3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sparse := graph.ToSparse()
+
+		dense, err := graph.Dijkstra(ctx, 1, g.BinaryHeap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := sparse.Dijkstra(ctx, 1, g.BinaryHeap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bf, err := sparse.BellmanFord(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for v := 1; v <= len(graph.AdjMatrix); v++ {
+			want := dense.Distance(v)
+			if d := got.Distance(v); d != want {
+				t.Errorf("seed %d: sparse Dijkstra distance to %d is %g, want %g", seed, v, d, want)
+			}
+			if d := bf.Distance(v); d != want && math.Abs(d-want) > 1e-9 {
+				t.Errorf("seed %d: sparse Bellman-Ford distance to %d is %g, want %g", seed, v, d, want)
+			}
+		}
+
+		if graph.Directed {
+			if _, err := sparse.MinimumSpanningForest(ctx, nil, nil); !errors.Is(err, g.ErrDirectedGraph) {
+				t.Errorf("seed %d: got %v, want %v", seed, err, g.ErrDirectedGraph)
+			}
+			continue
+		}
+		want, err := graph.MinimumSpanningForest(ctx, g.MSTOptions{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		forest, err := sparse.MinimumSpanningForest(ctx, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(forest.Weight-want.Weight) > 1e-9 || forest.Components != want.Components {
+			t.Errorf("seed %d: sparse forest weighs %g in %d components, want %g in %d", seed, forest.Weight, forest.Components, want.Weight, want.Components)
+		}
+	}
+}
+
+// TestSparseAtScale runs the sparse algorithms on a graph whose dense
+// matrices would take terabytes.
+func TestSparseAtScale(t *testing.T) {
+	const n = 200000
+	sparse := g.NewSparseGraph(n, false, true)
+	for v := 1; v < n; v++ {
+		sparse.AddEdge(v, v+1, 1)
+	}
+	sparse.AddEdge(1, n, 2*n)
+
+	ctx := context.Background()
+	sp, err := sparse.Dijkstra(ctx, 1, g.BinaryHeap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := sp.Distance(n); d != n-1 {
+		t.Errorf("distance to %d is %g, want %d", n, d, n-1)
+	}
+	forest, err := sparse.MinimumSpanningForest(ctx, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !forest.Spanning() || forest.Weight != n-1 {
+		t.Errorf("forest of %d components weighs %g, want one tree of weight %d", forest.Components, forest.Weight, n-1)
+	}
+}
+
+func TestSparseNegativeWeight(t *testing.T) {
+	sparse := g.NewSparseGraph(3, true, true)
+	sparse.AddEdge(1, 2, 1).AddEdge(2, 3, -1)
+	ctx := context.Background()
+	if _, err := sparse.Dijkstra(ctx, 1, g.BinaryHeap); !errors.Is(err, g.ErrNegativeWeight) {
+		t.Errorf("Dijkstra: got %v, want %v", err, g.ErrNegativeWeight)
+	}
+	sp, err := sparse.BellmanFord(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := sp.Distance(3); d != 0 {
+		t.Errorf("Bellman-Ford distance to 3 is %g, want 0", d)
+	}
+	if _, err := sparse.Dijkstra(ctx, 4, g.BinaryHeap); !errors.Is(err, g.ErrVertexOutOfRange) {
+		t.Errorf("Dijkstra from 4: got %v, want %v", err, g.ErrVertexOutOfRange)
+	}
+}
+
+func TestReadMETISChecksSymmetry(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   error
+	}{
+		{"symmetric", "3 2 1\n2 5\n1 5 3 7\n2 7\n", nil},
+		{"missing reverse entry", "3 2\n2\n1 3\n\n", g.ErrNotSymmetric},
+		{"reverse entry without forward", "2 1\n\n1\n", g.ErrNotSymmetric},
+		{"different weights", "2 1 1\n2 5\n1 6\n", g.ErrNotSymmetric},
+		{"neighbour listed twice", "2 1\n2 2\n1 1\n", g.ErrInvalidVertex},
+		{"half-edge count still checked", "3 1\n2 3\n1\n1\n", g.ErrHeaderMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := g.ReadMETIS(strings.NewReader(tt.input), g.ReadOptions{})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
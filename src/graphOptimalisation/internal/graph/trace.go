@@ -0,0 +1,172 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// EventKind identifies what happened in an algorithm step.
+type EventKind string
+
+const (
+	// EventPhase marks the start of a phase of a multi-step algorithm.
+	EventPhase EventKind = "phase"
+	// EventCoverAdd: the endpoints Vertices[0], Vertices[1] of an uncovered
+	// edge were added to the cover; Value is the number of edges left.
+	EventCoverAdd EventKind = "cover-add"
+	// EventMSTEdge: edge (Vertices[0], Vertices[1]) of weight Value was added
+	// to the spanning tree.
+	EventMSTEdge EventKind = "mst-edge"
+	// EventOddVertex: Vertices[0] has odd degree Value.
+	EventOddVertex EventKind = "odd-vertex"
+	// EventMatchCandidate: the pair Vertices with weight Value was considered.
+	EventMatchCandidate EventKind = "match-candidate"
+	// EventMatched: the pair Vertices with weight Value joined the matching.
+	EventMatched EventKind = "matched"
+	// EventEdgeDuplicated: the postman added a copy of edge Vertices of
+	// weight Value.
+	EventEdgeDuplicated EventKind = "edge-duplicated"
+	// EventEulerStep: the circuit traversed the edge Vertices.
+	EventEulerStep EventKind = "euler-step"
+	// EventTriangleViolation: d(Vertices[0], Vertices[1]) exceeds the path
+	// through Vertices[2] by Value.
+	EventTriangleViolation EventKind = "triangle-violation"
+	// EventResult carries the final solution in Vertices and its cost in
+	// Value. Edge sets are flattened: (u1, v1, u2, v2, ...).
+	EventResult EventKind = "result"
+)
+
+// Event is one step of an algorithm. The meaning of Vertices and Value
+// depends on Kind.
+type Event struct {
+	Algorithm string    `json:"algorithm"`
+	Kind      EventKind `json:"kind"`
+	Vertices  []int     `json:"vertices,omitempty"`
+	Value     float64   `json:"value"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// MarshalJSON writes a Value of ±Inf or NaN, e.g. the weight of a pair with
+// no path between them, as null; JSON has no such numbers.
+func (e Event) MarshalJSON() ([]byte, error) {
+	type plain Event
+	out := struct {
+		plain
+		Value *float64 `json:"value"`
+	}{plain: plain(e)}
+	if !math.IsInf(e.Value, 0) && !math.IsNaN(e.Value) {
+		out.Value = &e.Value
+	}
+	return json.Marshal(out)
+}
+
+func (e Event) String() string {
+	v := e.Vertices
+	switch e.Kind {
+	case EventPhase:
+		return e.Message
+	case EventCoverAdd:
+		return fmt.Sprintf("Added vertices %d and %d to the cover, %g edges left.", v[0], v[1], e.Value)
+	case EventMSTEdge:
+		return fmt.Sprintf("Added edge (%d, %d) with weight %g to the spanning tree.", v[0], v[1], e.Value)
+	case EventOddVertex:
+		return fmt.Sprintf("Vertex %d has odd degree (%g).", v[0], e.Value)
+	case EventMatchCandidate:
+		return fmt.Sprintf("Checking pair (%d, %d) with weight %g.", v[0], v[1], e.Value)
+	case EventMatched:
+		return fmt.Sprintf("Matched vertices %d and %d with weight %g.", v[0], v[1], e.Value)
+	case EventEdgeDuplicated:
+		return fmt.Sprintf("Duplicated edge (%d, %d) with weight %g.", v[0], v[1], e.Value)
+	case EventEulerStep:
+		return fmt.Sprintf("Traversed edge (%d, %d).", v[0], v[1])
+	case EventTriangleViolation:
+		return fmt.Sprintf("Triangle inequality violated: d(%d, %d) exceeds d(%d, %d) + d(%d, %d) by %g.", v[0], v[1], v[0], v[2], v[2], v[1], e.Value)
+	case EventResult:
+		return fmt.Sprintf("Result: %v (cost %g).", v, e.Value)
+	}
+	return fmt.Sprintf("%s %v %g %s", e.Kind, v, e.Value, e.Message)
+}
+
+// Tracer receives the steps of an algorithm as they happen. Algorithms
+// accept a nil Tracer, in which case nothing is recorded.
+type Tracer interface {
+	Trace(e Event)
+}
+
+// TracerFunc adapts a function to the Tracer interface.
+type TracerFunc func(e Event)
+
+func (f TracerFunc) Trace(e Event) { f(e) }
+
+// tracer fills in the algorithm name and skips work when there is no Tracer.
+type tracer struct {
+	t         Tracer
+	algorithm string
+}
+
+func newTracer(t Tracer, algorithm string) tracer {
+	return tracer{t: t, algorithm: algorithm}
+}
+
+func (tr tracer) emit(kind EventKind, value float64, vertices ...int) {
+	if tr.t != nil {
+		tr.t.Trace(Event{Algorithm: tr.algorithm, Kind: kind, Vertices: vertices, Value: value})
+	}
+}
+
+func (tr tracer) phase(format string, args ...any) {
+	if tr.t != nil {
+		tr.t.Trace(Event{Algorithm: tr.algorithm, Kind: EventPhase, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// Recorder keeps every event, e.g. for rendering an animation later.
+type Recorder struct {
+	Events []Event
+}
+
+func (r *Recorder) Trace(e Event) {
+	r.Events = append(r.Events, e)
+}
+
+// String renders the recorded events as text, one per line.
+func (r *Recorder) String() string {
+	var sb strings.Builder
+	for _, e := range r.Events {
+		sb.WriteString(e.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// TextTracer writes every event as a line of text.
+func TextTracer(w io.Writer) Tracer {
+	return TracerFunc(func(e Event) {
+		fmt.Fprintln(w, e.String())
+	})
+}
+
+// JSONTracer writes every event as a line of JSON. After the first error it
+// drops the remaining events; Err reports the error.
+type JSONTracer struct {
+	enc *json.Encoder
+	err error
+}
+
+func NewJSONTracer(w io.Writer) *JSONTracer {
+	return &JSONTracer{enc: json.NewEncoder(w)}
+}
+
+func (t *JSONTracer) Trace(e Event) {
+	if t.err == nil {
+		t.err = t.enc.Encode(e)
+	}
+}
+
+// Err returns the first error encoding or writing an event, if any.
+func (t *JSONTracer) Err() error {
+	return t.err
+}
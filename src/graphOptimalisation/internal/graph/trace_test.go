@@ -0,0 +1,202 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestJSONTracer(t *testing.T) {
+	var buf bytes.Buffer
+	tr := NewJSONTracer(&buf)
+	tr.Trace(Event{Algorithm: "tsp", Kind: EventMatchCandidate, Vertices: []int{1, 2}, Value: math.Inf(1)})
+	tr.Trace(Event{Algorithm: "cover", Kind: EventCoverAdd, Vertices: []int{3, 4}, Value: 0})
+	tr.Trace(Event{Algorithm: "mst", Kind: EventMSTEdge, Vertices: []int{1, 3}, Value: -2.5})
+	if err := tr.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`{"algorithm":"tsp","kind":"match-candidate","vertices":[1,2],"value":null}`,
+		`{"algorithm":"cover","kind":"cover-add","vertices":[3,4],"value":0}`,
+		`{"algorithm":"mst","kind":"mst-edge","vertices":[1,3],"value":-2.5}`,
+	}
+	if got := strings.Split(strings.TrimSpace(buf.String()), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	broken := NewJSONTracer(failingWriter{})
+	broken.Trace(Event{Kind: EventPhase, Message: "start"})
+	broken.Trace(Event{Kind: EventPhase, Message: "more"})
+	if broken.Err() == nil {
+		t.Error("write error not reported")
+	}
+}
+
+// kinds counts the recorded events of every kind.
+func kinds(events []Event) map[EventKind]int {
+	count := make(map[EventKind]int)
+	for _, e := range events {
+		count[e.Kind]++
+	}
+	return count
+}
+
+func checkResult(t *testing.T, events []Event, algorithm string, vertices []int, value float64) {
+	t.Helper()
+	if len(events) == 0 {
+		t.Fatal("no events recorded")
+	}
+	last := events[len(events)-1]
+	if last.Kind != EventResult || last.Algorithm != algorithm || last.Value != value || !equalInts(last.Vertices, vertices) {
+		t.Errorf("last event is %+v, want a %s result %v with value %g", last, algorithm, vertices, value)
+	}
+}
+
+func TestVertexCoverEvents(t *testing.T) {
+	g := NewGraph(6, false, false)
+	g.AddEdge(1, 2).AddEdge(2, 3).AddEdge(3, 4).AddEdge(4, 5).AddEdge(5, 6)
+	var rec Recorder
+	cover, err := g.ApproximateVertexCover(context.Background(), &rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left := float64(len(g.Edges))
+	for _, e := range rec.Events[:len(rec.Events)-1] {
+		if e.Kind != EventCoverAdd || e.Algorithm != "cover" || !g.HasEdge(e.Vertices[0], e.Vertices[1]) {
+			t.Errorf("unexpected event %+v", e)
+		}
+		if e.Value >= left {
+			t.Errorf("%g edges left after %+v, was %g", e.Value, e, left)
+		}
+		left = e.Value
+	}
+	if left != 0 {
+		t.Errorf("%g edges left after the last step", left)
+	}
+	checkResult(t, rec.Events, "cover", cover, float64(len(cover)))
+}
+
+func TestMSTEvents(t *testing.T) {
+	g := NewGraph(5, false, true)
+	g.AddEdge(1, 2, 4).AddEdge(2, 3, 1).AddEdge(3, 4, 3).AddEdge(4, 5, 2).AddEdge(5, 1, 6).AddEdge(1, 3, 2).AddEdge(2, 5, 5)
+	for _, opts := range []MSTOptions{{Algorithm: MSTKruskal}, {Algorithm: MSTPrim}, {Algorithm: MSTBoruvka, Workers: 2}} {
+		var rec Recorder
+		forest, err := g.MinimumSpanningTree(context.Background(), opts, &rec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total := 0.0
+		for _, e := range rec.Events[:len(rec.Events)-1] {
+			if e.Kind != EventMSTEdge || e.Algorithm != "mst" {
+				t.Errorf("%v: unexpected event %+v", opts.Algorithm, e)
+				continue
+			}
+			if w := g.WeightMatrix[e.Vertices[0]-1][e.Vertices[1]-1]; w != e.Value {
+				t.Errorf("%v: edge %v reported with weight %g, has %g", opts.Algorithm, e.Vertices, e.Value, w)
+			}
+			total += e.Value
+		}
+		if n := kinds(rec.Events)[EventMSTEdge]; n != len(forest.Edges) || total != forest.Weight {
+			t.Errorf("%v: %d edges of weight %g reported, tree has %d of weight %g", opts.Algorithm, n, total, len(forest.Edges), forest.Weight)
+		}
+		checkResult(t, rec.Events, "mst", flattenEdges(forest.Edges), forest.Weight)
+	}
+}
+
+func TestChristofidesEvents(t *testing.T) {
+	// Kwadrat o boku 3 z przekątnymi 5 (zaokrąglone w górę, więc metryka zostaje)
+	g := NewGraph(4, false, true)
+	g.AddEdge(1, 2, 3).AddEdge(2, 3, 3).AddEdge(3, 4, 3).AddEdge(4, 1, 3).AddEdge(1, 3, 5).AddEdge(2, 4, 5)
+	var rec Recorder
+	tour, err := g.Christofides(context.Background(), &rec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := kinds(rec.Events)
+	if count[EventPhase] != 4 || count[EventMSTEdge] != 3 || count[EventOddVertex]%2 != 0 || count[EventMatched] != count[EventOddVertex]/2 {
+		t.Errorf("unexpected events: %v", count)
+	}
+	for _, e := range rec.Events {
+		if e.Algorithm != "tsp" {
+			t.Errorf("event %+v not attributed to tsp", e)
+		}
+	}
+	cost := 0.0
+	for i := range tour {
+		cost += g.WeightMatrix[tour[i]-1][tour[(i+1)%len(tour)]-1]
+	}
+	checkResult(t, rec.Events, "tsp", tour, cost)
+
+	// Graf bez nierówności trójkąta: jedno zdarzenie z naruszeniem i błąd
+	g.SetWeight(1, 3, 7)
+	rec = Recorder{}
+	if _, err := g.Christofides(context.Background(), &rec, nil); err == nil {
+		t.Fatal("no error for a non-metric graph")
+	}
+	if len(rec.Events) != 1 || rec.Events[0].Kind != EventTriangleViolation || rec.Events[0].Value != 1 {
+		t.Errorf("got events %+v, want one triangle violation by 1", rec.Events)
+	}
+}
+
+func TestPostmanEvents(t *testing.T) {
+	// Wierzchołki 1 i 3 mają stopień 3; najtańsza ścieżka między nimi to przekątna
+	g := NewGraph(4, false, true)
+	g.AddEdge(1, 2, 2).AddEdge(2, 3, 2).AddEdge(3, 4, 2).AddEdge(4, 1, 2).AddEdge(1, 3, 3)
+	edges := len(g.Edges)
+	var rec Recorder
+	circuit, cost, err := g.ChinesePostmanProblem(context.Background(), &rec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := kinds(rec.Events)
+	if count[EventOddVertex] != 2 || count[EventMatched] != 1 || count[EventEdgeDuplicated] != 1 {
+		t.Errorf("unexpected events: %v", count)
+	}
+	if steps := count[EventEulerStep]; steps != edges+1 || steps != len(circuit)-1 {
+		t.Errorf("%d Euler steps for %d edges and a circuit of %d vertices", steps, edges, len(circuit))
+	}
+	for _, e := range rec.Events {
+		if e.Kind == EventEdgeDuplicated && (e.Value != 3 || !equalInts(e.Vertices, []int{1, 3}) && !equalInts(e.Vertices, []int{3, 1})) {
+			t.Errorf("duplicated %v with weight %g, want the diagonal 1-3 with weight 3", e.Vertices, e.Value)
+		}
+	}
+	checkResult(t, rec.Events, "cpp", circuit, cost)
+
+	// Graf eulerowski nie dubluje krawędzi
+	square := NewGraph(4, false, true)
+	square.AddEdge(1, 2, 1).AddEdge(2, 3, 1).AddEdge(3, 4, 1).AddEdge(4, 1, 1)
+	rec = Recorder{}
+	if _, _, err := square.ChinesePostmanProblem(context.Background(), &rec, nil); err != nil {
+		t.Fatal(err)
+	}
+	if count := kinds(rec.Events); count[EventEdgeDuplicated] != 0 || count[EventEulerStep] != 4 {
+		t.Errorf("unexpected events for an Eulerian graph: %v", count)
+	}
+}
+
+func TestRecorderMatchesTextTracer(t *testing.T) {
+	g := NewGraph(3, false, true)
+	g.AddEdge(1, 2, 1).AddEdge(2, 3, 2).AddEdge(1, 3, 2)
+	var rec Recorder
+	var buf bytes.Buffer
+	both := TracerFunc(func(e Event) {
+		rec.Trace(e)
+		TextTracer(&buf).Trace(e)
+	})
+	if _, err := g.Christofides(context.Background(), both, nil); err != nil {
+		t.Fatal(err)
+	}
+	if rec.String() != buf.String() {
+		t.Errorf("Recorder gave\n%s\nTextTracer gave\n%s", rec.String(), buf.String())
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(rec.Events) {
+		t.Errorf("%d lines for %d events", lines, len(rec.Events))
+	}
+}
@@ -0,0 +1,225 @@
+// Package graphio picks the right reader or writer of the graph package for
+// a file format, so the CLI, the REPL and the server accept the same inputs.
+package graphio
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+	"github.com/Simikao/graphOptimalisation/internal/render"
+)
+
+// Nazwy formatów przyjmowane przez flagę -format
+const (
+	EdgeList     = "edgelist"
+	AdjMatrix    = "adjmatrix"
+	WeightMatrix = "weightmatrix"
+	MatrixMarket = "mtx"
+	METIS        = "metis"
+	Pajek        = "pajek"
+	GML          = "gml"
+	DOT          = "dot"
+	JSON         = "json"
+	SVG          = "svg"
+)
+
+var formatByExt = map[string]string{
+	".txt":   EdgeList,
+	".edges": EdgeList,
+	".csv":   EdgeList,
+	".tsv":   EdgeList,
+	".adj":   AdjMatrix,
+	".mat":   WeightMatrix,
+	".dist":  WeightMatrix,
+	".mtx":   MatrixMarket,
+	".graph": METIS,
+	".metis": METIS,
+	".net":   Pajek,
+	".gml":   GML,
+	".dot":   DOT,
+	".gv":    DOT,
+	".json":  JSON,
+	".svg":   SVG,
+}
+
+// Formats lists every known format name.
+func Formats() []string {
+	return []string{EdgeList, AdjMatrix, WeightMatrix, MatrixMarket, METIS, Pajek, GML, DOT, JSON, SVG}
+}
+
+// Extensions lists the file extensions Detect recognises.
+func Extensions() []string {
+	exts := make([]string, 0, len(formatByExt))
+	for ext := range formatByExt {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+// Detect returns explicit if set, otherwise guesses from the extension.
+func Detect(path, explicit string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+	format, ok := formatByExt[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return "", fmt.Errorf("cannot guess format of %q from its extension, specify the format", path)
+	}
+	return format, nil
+}
+
+// ReadOptions controls Read.
+type ReadOptions struct {
+	// Directed only matters for edge lists without a header.
+	Directed bool
+	// MaxVertices makes every reader fail with graph.ErrTooManyVertices
+	// before allocating a larger graph; zero means no limit.
+	MaxVertices int
+}
+
+// Read reads a graph in the given format.
+func Read(r io.Reader, format string, opts ReadOptions) (g.Graph, error) {
+	limit := g.ReadOptions{MaxVertices: opts.MaxVertices}
+	switch format {
+	case EdgeList:
+		return g.ReadEdgeList(r, g.EdgeListOptions{Directed: opts.Directed, MaxVertices: opts.MaxVertices})
+	case AdjMatrix:
+		return g.ReadAdjacencyMatrix(r, g.MatrixOptions{MaxVertices: opts.MaxVertices})
+	case WeightMatrix:
+		return g.ReadWeightMatrix(r, g.MatrixOptions{MaxVertices: opts.MaxVertices})
+	case MatrixMarket, METIS:
+		sparse, err := ReadSparse(r, format, opts)
+		if err != nil {
+			return g.Graph{}, err
+		}
+		return sparse.ToGraph(), nil
+	case Pajek:
+		return g.ReadPajek(r, limit)
+	case GML:
+		return g.ReadGML(r, limit)
+	case DOT:
+		return g.ReadDOT(r, limit)
+	case JSON:
+		return g.ReadJSON(r, limit)
+	}
+	return g.Graph{}, fmt.Errorf("unknown input format %q", format)
+}
+
+// IsSparse reports whether format is read into adjacency lists by
+// ReadSparse.
+func IsSparse(format string) bool {
+	return format == MatrixMarket || format == METIS
+}
+
+// ReadSparse reads a MatrixMarket or METIS graph into adjacency lists,
+// without the n×n matrices Read allocates.
+func ReadSparse(r io.Reader, format string, opts ReadOptions) (*g.SparseGraph, error) {
+	limit := g.ReadOptions{MaxVertices: opts.MaxVertices}
+	switch format {
+	case MatrixMarket:
+		return g.ReadMatrixMarket(r, limit)
+	case METIS:
+		return g.ReadMETIS(r, limit)
+	}
+	return nil, fmt.Errorf("format %q is not read as a sparse graph", format)
+}
+
+// LoadSparse is Load for the formats ReadSparse accepts.
+func LoadSparse(path, format string) (*g.SparseGraph, error) {
+	format, err := Detect(path, format)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sparse, err := ReadSparse(file, format, ReadOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return sparse, nil
+}
+
+// Load reads the file at path, guessing the format from its extension when
+// format is empty.
+func Load(path, format string, directed bool) (g.Graph, error) {
+	format, err := Detect(path, format)
+	if err != nil {
+		return g.Graph{}, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return g.Graph{}, err
+	}
+	defer file.Close()
+
+	graph, err := Read(file, format, ReadOptions{Directed: directed})
+	if err != nil {
+		return g.Graph{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return graph, nil
+}
+
+// Write writes graph to w in the given format.
+func Write(w io.Writer, graph *g.Graph, format string) error {
+	switch format {
+	case EdgeList:
+		return graph.WriteEdgeList(w)
+	case AdjMatrix:
+		return graph.WriteAdjacencyMatrix(w, ' ')
+	case WeightMatrix:
+		return graph.WriteWeightMatrix(w, ' ')
+	case MatrixMarket:
+		return graph.ToSparse().WriteMatrixMarket(w)
+	case METIS:
+		return graph.ToSparse().WriteMETIS(w)
+	case Pajek:
+		return graph.WritePajek(w)
+	case GML:
+		return graph.WriteGML(w)
+	case DOT:
+		return graph.WriteDOT(w)
+	case JSON:
+		return graph.WriteJSON(w)
+	case SVG:
+		return render.WriteSVG(w, graph, render.Options{ShowWeights: true})
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+// Save writes graph to path ("-" means stdout), guessing the format from the
+// extension when format is empty.
+func Save(graph *g.Graph, path, format string) error {
+	if path == "-" {
+		if format == "" {
+			return fmt.Errorf("writing to stdout requires an explicit format")
+		}
+		return Write(os.Stdout, graph, format)
+	}
+
+	format, err := Detect(path, format)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := Write(file, graph, format); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,276 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+// Frame is one picture of an animation: the solution built so far, the step
+// that was just taken and a caption describing it.
+type Frame struct {
+	Overlay Overlay
+	Caption string
+}
+
+// Frames replays the events recorded by a graph.Recorder and returns one
+// frame per event, preceded by a frame of the bare graph. Edges added to a
+// spanning tree, matching or circuit and vertices added to a cover
+// accumulate from frame to frame; the edge or vertices of the current step
+// are marked with CurrentEdges and CurrentVertices.
+func Frames(events []g.Event) []Frame {
+	frames := []Frame{{Caption: "Start"}}
+	var solution Overlay
+	for _, e := range events {
+		var step Overlay
+		v := e.Vertices
+		switch e.Kind {
+		case g.EventCoverAdd:
+			solution.Vertices = append(solution.Vertices, v[0], v[1])
+			step.CurrentVertices = []int{v[0], v[1]}
+		case g.EventMSTEdge, g.EventMatched, g.EventEdgeDuplicated, g.EventEulerStep:
+			solution.Edges = append(solution.Edges, [2]int{v[0], v[1]})
+			step.CurrentEdges = [][2]int{{v[0], v[1]}}
+		case g.EventMatchCandidate:
+			step.CurrentEdges = [][2]int{{v[0], v[1]}}
+		case g.EventOddVertex:
+			step.CurrentVertices = []int{v[0]}
+		case g.EventTriangleViolation:
+			step.CurrentEdges = [][2]int{{v[0], v[1]}, {v[0], v[2]}, {v[2], v[1]}}
+		}
+
+		// Każda klatka dostaje własne kopie, bo solution rośnie dalej
+		overlay := Overlay{
+			Edges:           append([][2]int(nil), solution.Edges...),
+			Vertices:        append([]int(nil), solution.Vertices...),
+			CurrentEdges:    step.CurrentEdges,
+			CurrentVertices: step.CurrentVertices,
+		}
+		frames = append(frames, Frame{Overlay: overlay, Caption: e.String()})
+	}
+	return frames
+}
+
+// fixedLayout computes the layout once so that vertices stay in place
+// across the frames of an animation.
+func fixedLayout(graph *g.Graph, opts *Options) error {
+	opts.defaults()
+	pos, err := opts.Layout(graph)
+	if err != nil {
+		return err
+	}
+	opts.Layout = func(*g.Graph) ([]Point, error) { return pos, nil }
+	return nil
+}
+
+// WriteFrames writes every frame to dir as frame-0001.svg, frame-0002.svg,
+// ... or, with format "dot", as Graphviz files.
+func WriteFrames(dir string, graph *g.Graph, frames []Frame, format string, opts Options) error {
+	if format != "svg" && format != "dot" {
+		return fmt.Errorf("unknown frame format %q (svg or dot)", format)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := fixedLayout(graph, &opts); err != nil {
+		return err
+	}
+
+	width := len(strconv.Itoa(len(frames)))
+	for i, frame := range frames {
+		name := filepath.Join(dir, fmt.Sprintf("frame-%0*d.%s", max(width, 4), i+1, format))
+		file, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		if format == "svg" {
+			opts.Overlay, opts.Title = frame.Overlay, frame.Caption
+			err = WriteSVG(file, graph, opts)
+		} else {
+			err = WriteDOT(file, graph, frame.Overlay, frame.Caption)
+		}
+		if err == nil {
+			err = file.Close()
+		} else {
+			file.Close()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteDOT writes graph in Graphviz format with the overlay drawn as edge and
+// node colours and title as the graph label.
+func WriteDOT(w io.Writer, graph *g.Graph, overlay Overlay, title string) error {
+	highlighted := make(map[[2]int]bool)
+	for i := 0; i+1 < len(overlay.Tour); i++ {
+		highlighted[edgeKey(overlay.Tour[i], overlay.Tour[i+1], graph.Directed)] = true
+	}
+	for _, e := range overlay.Edges {
+		highlighted[edgeKey(e[0], e[1], graph.Directed)] = true
+	}
+	current := make(map[[2]int]bool)
+	for _, e := range overlay.CurrentEdges {
+		current[edgeKey(e[0], e[1], graph.Directed)] = true
+	}
+	fill := make(map[int]string)
+	for _, v := range overlay.Vertices {
+		fill[v] = highlightColor
+	}
+	for _, v := range overlay.CurrentVertices {
+		fill[v] = currentColor
+	}
+
+	kind, connector := "graph", "--"
+	if graph.Directed {
+		kind, connector = "digraph", "->"
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s G {\n", kind)
+	if title != "" {
+		fmt.Fprintf(bw, "  label=%s;\n  labelloc=t;\n", strconv.Quote(title))
+	}
+	bw.WriteString("  node [shape=circle];\n")
+	for v := 1; v <= len(graph.AdjMatrix); v++ {
+		fmt.Fprintf(bw, "  %d [label=%s", v, strconv.Quote(graph.Label(v)))
+		if color, ok := fill[v]; ok {
+			fmt.Fprintf(bw, ", style=filled, fillcolor=\"%s\"", color)
+		}
+		bw.WriteString("];\n")
+	}
+	for _, e := range graph.Edges {
+		u, v := e[0], e[1]
+		fmt.Fprintf(bw, "  %d %s %d [", u, connector, v)
+		if graph.Weighted {
+			fmt.Fprintf(bw, "label=\"%s\", ", strconv.FormatFloat(graph.WeightMatrix[u-1][v-1], 'g', 4, 64))
+		}
+		switch key := edgeKey(u, v, graph.Directed); {
+		case current[key]:
+			fmt.Fprintf(bw, "color=\"%s\", penwidth=4", currentColor)
+		case highlighted[key]:
+			fmt.Fprintf(bw, "color=\"%s\", penwidth=3", highlightColor)
+		default:
+			fmt.Fprintf(bw, "color=\"%s\"", edgeColor)
+		}
+		bw.WriteString("];\n")
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// WriteHTML writes a self-contained HTML page that plays the frames, with
+// buttons to step back and forth, a slider and autoplay every delayMS
+// milliseconds.
+func WriteHTML(w io.Writer, graph *g.Graph, frames []Frame, title string, delayMS int, opts Options) error {
+	if err := fixedLayout(graph, &opts); err != nil {
+		return err
+	}
+	if delayMS <= 0 {
+		delayMS = 800
+	}
+
+	captions := make([]string, len(frames))
+	var svgs bytes.Buffer
+	for i, frame := range frames {
+		captions[i] = frame.Caption
+		opts.Overlay, opts.Title = frame.Overlay, ""
+		svgs.WriteString("<div class=\"frame\" hidden>\n")
+		if err := WriteSVG(&svgs, graph, opts); err != nil {
+			return err
+		}
+		svgs.WriteString("</div>\n")
+	}
+	// json.Marshal koduje < i > jako \u003c i \u003e, więc podpisy nie zamkną znacznika <script>
+	captionJSON, err := json.Marshal(captions)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, htmlHead, html.EscapeString(title), html.EscapeString(title))
+	svgs.WriteTo(bw)
+	fmt.Fprintf(bw, htmlTail, len(frames)-1, captionJSON, delayMS)
+	return bw.Flush()
+}
+
+const htmlHead = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+  body { font-family: sans-serif; margin: 2em; }
+  .controls { margin: 1em 0; display: flex; gap: .5em; align-items: center; }
+  #caption { min-height: 1.5em; font-size: 1.1em; }
+  #slider { width: 300px; }
+</style>
+</head>
+<body>
+<h1>%s</h1>
+<div class="controls">
+  <button id="first">&#x23EE;</button>
+  <button id="prev">&#x25C0;</button>
+  <button id="play">Play</button>
+  <button id="next">&#x25B6;</button>
+  <button id="last">&#x23ED;</button>
+  <input id="slider" type="range" min="0" value="0">
+  <span id="counter"></span>
+</div>
+<p id="caption"></p>
+`
+
+const htmlTail = `<script>
+(function () {
+  var frames = document.querySelectorAll(".frame");
+  var last = %d;
+  var captions = %s;
+  var delay = %d;
+  var slider = document.getElementById("slider");
+  var play = document.getElementById("play");
+  var current = 0, timer = null;
+  slider.max = last;
+
+  function show(i) {
+    current = Math.max(0, Math.min(last, i));
+    frames.forEach(function (f, j) { f.hidden = j !== current; });
+    slider.value = current;
+    document.getElementById("caption").textContent = captions[current];
+    document.getElementById("counter").textContent = (current + 1) + " / " + (last + 1);
+    if (current === last) stop();
+  }
+  function stop() {
+    clearInterval(timer);
+    timer = null;
+    play.textContent = "Play";
+  }
+  play.onclick = function () {
+    if (timer) { stop(); return; }
+    if (current === last) show(0);
+    play.textContent = "Pause";
+    timer = setInterval(function () { show(current + 1); }, delay);
+  };
+  document.getElementById("first").onclick = function () { stop(); show(0); };
+  document.getElementById("prev").onclick = function () { stop(); show(current - 1); };
+  document.getElementById("next").onclick = function () { stop(); show(current + 1); };
+  document.getElementById("last").onclick = function () { stop(); show(last); };
+  slider.oninput = function () { stop(); show(parseInt(slider.value, 10)); };
+  document.addEventListener("keydown", function (e) {
+    if (e.key === "ArrowLeft") { stop(); show(current - 1); }
+    if (e.key === "ArrowRight") { stop(); show(current + 1); }
+  });
+  show(0);
+})();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,201 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+func TestFrames(t *testing.T) {
+	events := []g.Event{
+		{Kind: g.EventPhase, Message: "Step 1"},
+		{Kind: g.EventMSTEdge, Vertices: []int{1, 2}, Value: 1},
+		{Kind: g.EventOddVertex, Vertices: []int{3}, Value: 1},
+		{Kind: g.EventMatchCandidate, Vertices: []int{3, 4}, Value: 2},
+		{Kind: g.EventMatched, Vertices: []int{3, 4}, Value: 2},
+		{Kind: g.EventCoverAdd, Vertices: []int{5, 6}, Value: 0},
+		{Kind: g.EventTriangleViolation, Vertices: []int{1, 2, 3}, Value: 4},
+	}
+	frames := Frames(events)
+	if len(frames) != len(events)+1 {
+		t.Fatalf("got %d frames, want %d", len(frames), len(events)+1)
+	}
+	if frames[0].Caption != "Start" || len(frames[0].Overlay.Edges) != 0 {
+		t.Errorf("first frame is %+v, want the bare graph", frames[0])
+	}
+	for i, e := range events {
+		if frames[i+1].Caption != e.String() {
+			t.Errorf("frame %d caption %q, want %q", i+2, frames[i+1].Caption, e.String())
+		}
+	}
+
+	tests := []struct {
+		frame           int
+		edges           int
+		vertices        int
+		currentEdges    int
+		currentVertices int
+	}{
+		{1, 0, 0, 0, 0},
+		{2, 1, 0, 1, 0},
+		{3, 1, 0, 0, 1},
+		{4, 1, 0, 1, 0},
+		{5, 2, 0, 1, 0},
+		{6, 2, 2, 0, 2},
+		{7, 2, 2, 3, 0},
+	}
+	for _, tt := range tests {
+		o := frames[tt.frame].Overlay
+		if len(o.Edges) != tt.edges || len(o.Vertices) != tt.vertices ||
+			len(o.CurrentEdges) != tt.currentEdges || len(o.CurrentVertices) != tt.currentVertices {
+			t.Errorf("frame %d: got %d/%d solution and %d/%d current edges/vertices, want %d/%d and %d/%d",
+				tt.frame+1, len(o.Edges), len(o.Vertices), len(o.CurrentEdges), len(o.CurrentVertices),
+				tt.edges, tt.vertices, tt.currentEdges, tt.currentVertices)
+		}
+	}
+
+	// Późniejsze klatki nie mogą zmieniać wcześniejszych
+	frames[2].Overlay.Edges[0] = [2]int{9, 9}
+	if frames[5].Overlay.Edges[0] != [2]int{1, 2} {
+		t.Error("frames share their edge slices")
+	}
+}
+
+func TestFramesFromKruskal(t *testing.T) {
+	graph := g.NewGraph(4, false, true)
+	graph.AddEdge(1, 2, 3).AddEdge(2, 3, 1).AddEdge(3, 4, 2).AddEdge(4, 1, 5).AddEdge(1, 3, 4)
+	var rec g.Recorder
+	mst, err := graph.KruskalMST(context.Background(), &rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames := Frames(rec.Events)
+	last := frames[len(frames)-1].Overlay
+	if len(last.Edges) != len(mst) {
+		t.Fatalf("last frame shows %d edges, want the %d tree edges", len(last.Edges), len(mst))
+	}
+	for i, e := range mst {
+		if last.Edges[i] != e {
+			t.Errorf("tree edge %d is %v in the animation, want %v", i+1, last.Edges[i], e)
+		}
+	}
+}
+
+func TestWriteFrames(t *testing.T) {
+	graph := cycle(4)
+	frames := Frames([]g.Event{
+		{Kind: g.EventMSTEdge, Vertices: []int{1, 2}, Value: 1},
+		{Kind: g.EventMSTEdge, Vertices: []int{2, 3}, Value: 2},
+	})
+	for _, format := range []string{"svg", "dot"} {
+		dir := filepath.Join(t.TempDir(), "frames")
+		if err := WriteFrames(dir, &graph, frames, format, Options{}); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != len(frames) {
+			t.Fatalf("%s: wrote %d files, want %d", format, len(entries), len(frames))
+		}
+		for i, entry := range entries {
+			if want := fmt.Sprintf("frame-%04d.%s", i+1, format); entry.Name() != want {
+				t.Errorf("%s: file %d is %s, want %s", format, i+1, entry.Name(), want)
+			}
+		}
+	}
+
+	// Położenie wierzchołków jest takie samo we wszystkich klatkach
+	dir := t.TempDir()
+	if err := WriteFrames(dir, &graph, frames, "svg", Options{}); err != nil {
+		t.Fatal(err)
+	}
+	var first string
+	for i := range frames {
+		data, err := os.ReadFile(filepath.Join(dir, fmt.Sprintf("frame-%04d.svg", i+1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var centres []string
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.Contains(line, "<circle ") {
+				centres = append(centres, strings.SplitN(line, " r=", 2)[0])
+			}
+		}
+		switch joined := strings.Join(centres, "\n"); {
+		case i == 0:
+			first = joined
+		case joined != first:
+			t.Errorf("vertices moved in frame %d", i+1)
+		}
+	}
+
+	if err := WriteFrames(t.TempDir(), &graph, frames, "png", Options{}); err == nil {
+		t.Error("no error for an unknown frame format")
+	}
+}
+
+func TestWriteDOTOverlay(t *testing.T) {
+	graph := cycle(4)
+	graph.Labels = []string{`say "hi"`, "", "", ""}
+	overlay := Overlay{
+		Edges:           [][2]int{{1, 2}, {3, 2}},
+		CurrentEdges:    [][2]int{{4, 3}},
+		Vertices:        []int{1},
+		CurrentVertices: []int{4},
+	}
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, &graph, overlay, "step 2"); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	counts := []struct {
+		what, substr string
+		want         int
+	}{
+		{"highlighted edges", `color="` + highlightColor + `", penwidth=3`, 2},
+		{"current edges", `color="` + currentColor + `", penwidth=4`, 1},
+		{"plain edges", `color="` + edgeColor + `"`, 1},
+		{"highlighted vertices", `fillcolor="` + highlightColor + `"`, 1},
+		{"current vertices", `fillcolor="` + currentColor + `"`, 1},
+		{"weight labels", `", color="`, 4},
+	}
+	for _, c := range counts {
+		if got := strings.Count(dot, c.substr); got != c.want {
+			t.Errorf("%d %s, want %d", got, c.what, c.want)
+		}
+	}
+	for _, want := range []string{`label="step 2";`, `1 [label="say \"hi\""`, "1 -- 2 ["} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT does not contain %q:\n%s", want, dot)
+		}
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	graph := cycle(3)
+	frames := []Frame{{Caption: "Start"}, {Caption: "</script><b>"}, {Caption: "end"}}
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, &graph, frames, "Kruskal <demo>", 0, Options{Layout: Circular}); err != nil {
+		t.Fatal(err)
+	}
+	page := buf.String()
+	if got := strings.Count(page, `<div class="frame" hidden>`); got != len(frames) {
+		t.Errorf("%d frames embedded, want %d", got, len(frames))
+	}
+	if got := strings.Count(page, "</script>"); got != 1 {
+		t.Errorf("a caption closed the script tag: %d </script> found", got)
+	}
+	for _, want := range []string{"<title>Kruskal &lt;demo&gt;</title>", "var last = 2;", "var delay = 800;"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page does not contain %q", want)
+		}
+	}
+}
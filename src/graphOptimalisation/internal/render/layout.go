@@ -0,0 +1,110 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+// Point is a vertex position. Layouts return positions in arbitrary units;
+// WriteSVG scales them to fit the drawing.
+type Point struct {
+	X, Y float64
+}
+
+// Layout computes a position for every vertex (pos[v-1] for vertex v).
+type Layout func(graph *g.Graph) ([]Point, error)
+
+// Circular places the vertices evenly on a circle, in vertex order.
+func Circular(graph *g.Graph) ([]Point, error) {
+	n := len(graph.AdjMatrix)
+	pos := make([]Point, n)
+	for i := range pos {
+		angle := 2*math.Pi*float64(i)/float64(n) - math.Pi/2
+		pos[i] = Point{X: math.Cos(angle), Y: math.Sin(angle)}
+	}
+	return pos, nil
+}
+
+// Coordinates uses the coordinates stored in graph.Coords.
+func Coordinates(graph *g.Graph) ([]Point, error) {
+	if len(graph.Coords) != len(graph.AdjMatrix) {
+		return nil, fmt.Errorf("graph has no vertex coordinates")
+	}
+	pos := make([]Point, len(graph.Coords))
+	for i, c := range graph.Coords {
+		pos[i] = Point{X: c[0], Y: c[1]}
+	}
+	return pos, nil
+}
+
+// ForceDirected returns a Fruchterman–Reingold layout. The same seed always
+// gives the same picture.
+func ForceDirected(iterations int, seed int64) Layout {
+	return func(graph *g.Graph) ([]Point, error) {
+		n := len(graph.AdjMatrix)
+		if n == 0 {
+			return nil, nil
+		}
+
+		rng := rand.New(rand.NewSource(seed))
+		pos := make([]Point, n)
+		for i := range pos {
+			pos[i] = Point{X: rng.Float64(), Y: rng.Float64()}
+		}
+
+		// Obszar jednostkowy: optymalna odległość k = sqrt(1/n)
+		k := math.Sqrt(1 / float64(n))
+		temperature := 0.1
+		cooling := temperature / float64(iterations+1)
+		disp := make([]Point, n)
+
+		for it := 0; it < iterations; it++ {
+			for i := range disp {
+				disp[i] = Point{}
+			}
+
+			// Odpychanie każdej pary wierzchołków
+			for i := 0; i < n; i++ {
+				for j := i + 1; j < n; j++ {
+					dx, dy := pos[i].X-pos[j].X, pos[i].Y-pos[j].Y
+					dist := math.Max(math.Hypot(dx, dy), 1e-9)
+					force := k * k / dist
+					disp[i].X += dx / dist * force
+					disp[i].Y += dy / dist * force
+					disp[j].X -= dx / dist * force
+					disp[j].Y -= dy / dist * force
+				}
+			}
+
+			// Przyciąganie wzdłuż krawędzi
+			for _, edge := range graph.Edges {
+				u, v := edge[0]-1, edge[1]-1
+				if u == v {
+					continue
+				}
+				dx, dy := pos[u].X-pos[v].X, pos[u].Y-pos[v].Y
+				dist := math.Max(math.Hypot(dx, dy), 1e-9)
+				force := dist * dist / k
+				disp[u].X -= dx / dist * force
+				disp[u].Y -= dy / dist * force
+				disp[v].X += dx / dist * force
+				disp[v].Y += dy / dist * force
+			}
+
+			for i := range pos {
+				length := math.Hypot(disp[i].X, disp[i].Y)
+				if length == 0 {
+					continue
+				}
+				step := math.Min(length, temperature)
+				pos[i].X = math.Min(1, math.Max(0, pos[i].X+disp[i].X/length*step))
+				pos[i].Y = math.Min(1, math.Max(0, pos[i].Y+disp[i].Y/length*step))
+			}
+			temperature -= cooling
+		}
+		return pos, nil
+	}
+}
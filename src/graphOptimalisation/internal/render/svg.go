@@ -0,0 +1,197 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+// Overlay marks part of a solution on top of the drawing.
+type Overlay struct {
+	// Tour is a sequence of vertices drawn as consecutive highlighted edges,
+	// e.g. a Hamiltonian cycle or an Eulerian circuit.
+	Tour []int
+	// Edges are highlighted individually, e.g. a matching or a spanning tree.
+	Edges [][2]int
+	// Vertices are filled with the highlight colour, e.g. a vertex cover.
+	Vertices []int
+	// CurrentEdges and CurrentVertices mark the step being shown in an
+	// animation; they are drawn in a colour of their own.
+	CurrentEdges    [][2]int
+	CurrentVertices []int
+}
+
+type Options struct {
+	Width, Height float64
+	// Layout defaults to ForceDirected(300, 1).
+	Layout      Layout
+	Overlay     Overlay
+	ShowWeights bool
+	Title       string
+}
+
+const (
+	margin         = 30.0
+	vertexRadius   = 12.0
+	edgeColor      = "#999999"
+	highlightColor = "#d62728"
+	currentColor   = "#ff7f0e"
+	vertexFill     = "#ffffff"
+)
+
+func (o *Options) defaults() {
+	if o.Width <= 0 {
+		o.Width = 600
+	}
+	if o.Height <= 0 {
+		o.Height = 600
+	}
+	if o.Layout == nil {
+		o.Layout = ForceDirected(300, 1)
+	}
+}
+
+// fit scales the layout to the drawing area, keeping the aspect ratio.
+func fit(pos []Point, width, height float64) []Point {
+	if len(pos) == 0 {
+		return pos
+	}
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range pos {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+
+	scale := math.Min((width-2*margin)/math.Max(maxX-minX, 1e-9), (height-2*margin)/math.Max(maxY-minY, 1e-9))
+	offX := (width - (maxX-minX)*scale) / 2
+	offY := (height - (maxY-minY)*scale) / 2
+	out := make([]Point, len(pos))
+	for i, p := range pos {
+		out[i] = Point{X: offX + (p.X-minX)*scale, Y: offY + (p.Y-minY)*scale}
+	}
+	return out
+}
+
+func edgeKey(u, v int, directed bool) [2]int {
+	if !directed && u > v {
+		u, v = v, u
+	}
+	return [2]int{u, v}
+}
+
+// WriteSVG draws graph as a standalone SVG image.
+func WriteSVG(w io.Writer, graph *g.Graph, opts Options) error {
+	opts.defaults()
+	pos, err := opts.Layout(graph)
+	if err != nil {
+		return err
+	}
+	if len(pos) != len(graph.AdjMatrix) {
+		return fmt.Errorf("layout returned %d positions for %d vertices", len(pos), len(graph.AdjMatrix))
+	}
+	pos = fit(pos, opts.Width, opts.Height)
+
+	highlighted := make(map[[2]int]bool)
+	for i := 0; i+1 < len(opts.Overlay.Tour); i++ {
+		highlighted[edgeKey(opts.Overlay.Tour[i], opts.Overlay.Tour[i+1], graph.Directed)] = true
+	}
+	for _, e := range opts.Overlay.Edges {
+		highlighted[edgeKey(e[0], e[1], graph.Directed)] = true
+	}
+	current := make(map[[2]int]bool)
+	for _, e := range opts.Overlay.CurrentEdges {
+		current[edgeKey(e[0], e[1], graph.Directed)] = true
+	}
+	inCover := make(map[int]bool)
+	for _, v := range opts.Overlay.Vertices {
+		inCover[v] = true
+	}
+	isCurrent := make(map[int]bool)
+	for _, v := range opts.Overlay.CurrentVertices {
+		isCurrent[v] = true
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%g\" height=\"%g\" viewBox=\"0 0 %g %g\">\n",
+		opts.Width, opts.Height, opts.Width, opts.Height)
+	if graph.Directed {
+		for _, marker := range []struct{ id, color string }{{"arrow", edgeColor}, {"arrow-hl", highlightColor}, {"arrow-cur", currentColor}} {
+			fmt.Fprintf(bw, "  <defs><marker id=\"%s\" viewBox=\"0 0 10 10\" refX=\"10\" refY=\"5\" markerWidth=\"6\" markerHeight=\"6\" orient=\"auto\">"+
+				"<path d=\"M0,0 L10,5 L0,10 z\" fill=\"%s\"/></marker></defs>\n", marker.id, marker.color)
+		}
+	}
+	bw.WriteString("  <rect width=\"100%\" height=\"100%\" fill=\"white\"/>\n")
+	if opts.Title != "" {
+		fmt.Fprintf(bw, "  <text x=\"%g\" y=\"18\" text-anchor=\"middle\" font-family=\"sans-serif\" font-size=\"14\">%s</text>\n",
+			opts.Width/2, html.EscapeString(opts.Title))
+	}
+
+	// Krawędzie najpierw, żeby wierzchołki były na wierzchu
+	for _, edge := range graph.Edges {
+		u, v := edge[0], edge[1]
+		if u == v {
+			continue
+		}
+		p, q := pos[u-1], pos[v-1]
+		// Skróć odcinek, żeby strzałka kończyła się na brzegu koła
+		dx, dy := q.X-p.X, q.Y-p.Y
+		length := math.Max(math.Hypot(dx, dy), 1e-9)
+		x1, y1 := p.X+dx/length*vertexRadius, p.Y+dy/length*vertexRadius
+		x2, y2 := q.X-dx/length*vertexRadius, q.Y-dy/length*vertexRadius
+
+		color, width, marker := edgeColor, 1.5, "arrow"
+		switch key := edgeKey(u, v, graph.Directed); {
+		case current[key]:
+			color, width, marker = currentColor, 4.5, "arrow-cur"
+		case highlighted[key]:
+			color, width, marker = highlightColor, 3.5, "arrow-hl"
+		}
+		fmt.Fprintf(bw, "  <line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"%s\" stroke-width=\"%g\"", x1, y1, x2, y2, color, width)
+		if graph.Directed {
+			fmt.Fprintf(bw, " marker-end=\"url(#%s)\"", marker)
+		}
+		bw.WriteString("/>\n")
+
+		if opts.ShowWeights && graph.Weighted {
+			fmt.Fprintf(bw, "  <text x=\"%.2f\" y=\"%.2f\" text-anchor=\"middle\" font-family=\"sans-serif\" font-size=\"10\" fill=\"#333\">%s</text>\n",
+				(p.X+q.X)/2, (p.Y+q.Y)/2-3, strconv.FormatFloat(graph.WeightMatrix[u-1][v-1], 'g', 4, 64))
+		}
+	}
+
+	for i, p := range pos {
+		v := i + 1
+		fill := vertexFill
+		if isCurrent[v] {
+			fill = currentColor
+		} else if inCover[v] {
+			fill = highlightColor
+		}
+		fmt.Fprintf(bw, "  <circle cx=\"%.2f\" cy=\"%.2f\" r=\"%g\" fill=\"%s\" stroke=\"#333\" stroke-width=\"1.5\"/>\n", p.X, p.Y, vertexRadius, fill)
+		fmt.Fprintf(bw, "  <text x=\"%.2f\" y=\"%.2f\" text-anchor=\"middle\" dominant-baseline=\"central\" font-family=\"sans-serif\" font-size=\"11\">%s</text>\n",
+			p.X, p.Y, html.EscapeString(graph.Label(v)))
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// ToSVG writes the drawing to filename.
+func ToSVG(graph *g.Graph, filename string, opts Options) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := WriteSVG(file, graph, opts); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,207 @@
+package render
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+// cycle returns the undirected cycle 1-2-...-n-1.
+func cycle(n int) g.Graph {
+	graph := g.NewGraph(n, false, true)
+	for v := 1; v <= n; v++ {
+		graph.AddEdge(v, v%n+1, float64(v))
+	}
+	return graph
+}
+
+func TestCircular(t *testing.T) {
+	graph := cycle(6)
+	pos, err := Circular(&graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pos) != 6 {
+		t.Fatalf("got %d positions, want 6", len(pos))
+	}
+	// Pierwszy wierzchołek na górze, kolejne co 60 stopni
+	if math.Abs(pos[0].X) > 1e-9 || math.Abs(pos[0].Y+1) > 1e-9 {
+		t.Errorf("vertex 1 at %v, want (0, -1)", pos[0])
+	}
+	for i, p := range pos {
+		if r := math.Hypot(p.X, p.Y); math.Abs(r-1) > 1e-9 {
+			t.Errorf("vertex %d is %g from the centre, want 1", i+1, r)
+		}
+		q := pos[(i+1)%6]
+		if d := math.Hypot(p.X-q.X, p.Y-q.Y); math.Abs(d-1) > 1e-9 {
+			t.Errorf("vertices %d and %d are %g apart, want 1", i+1, (i+1)%6+1, d)
+		}
+	}
+}
+
+func TestCoordinates(t *testing.T) {
+	graph := cycle(3)
+	if _, err := Coordinates(&graph); err == nil {
+		t.Error("no error for a graph without coordinates")
+	}
+	graph.Coords = [][2]float64{{0, 0}, {2, 0.5}, {-1, 3}}
+	pos, err := Coordinates(&graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range graph.Coords {
+		if pos[i] != (Point{X: c[0], Y: c[1]}) {
+			t.Errorf("vertex %d at %v, want %v", i+1, pos[i], c)
+		}
+	}
+}
+
+func TestForceDirected(t *testing.T) {
+	graph := cycle(10)
+	graph.AddEdge(1, 6, 1)
+	first, err := ForceDirected(100, 7)(&graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := ForceDirected(100, 7)(&graph)
+	other, _ := ForceDirected(100, 8)(&graph)
+
+	differs := false
+	for i, p := range first {
+		if p != again[i] {
+			t.Fatalf("vertex %d at %v and %v with the same seed", i+1, p, again[i])
+		}
+		if p != other[i] {
+			differs = true
+		}
+		if math.IsNaN(p.X) || math.IsNaN(p.Y) || p.X < 0 || p.X > 1 || p.Y < 0 || p.Y > 1 {
+			t.Errorf("vertex %d at %v, outside the unit square", i+1, p)
+		}
+		for j := range first[:i] {
+			if math.Hypot(p.X-first[j].X, p.Y-first[j].Y) < 1e-6 {
+				t.Errorf("vertices %d and %d overlap", j+1, i+1)
+			}
+		}
+	}
+	if !differs {
+		t.Error("seeds 7 and 8 gave the same layout")
+	}
+
+	empty := g.NewGraph(0, false, false)
+	if pos, err := ForceDirected(10, 1)(&empty); err != nil || len(pos) != 0 {
+		t.Errorf("empty graph: got %v, %v", pos, err)
+	}
+}
+
+func TestWriteSVGOverlay(t *testing.T) {
+	graph := cycle(5)
+	graph.Labels = []string{"a<b", "", "", "", ""}
+	opts := Options{
+		Layout: Circular,
+		Title:  "cover & tour",
+		Overlay: Overlay{
+			Tour:            []int{1, 2, 3},
+			Vertices:        []int{4},
+			CurrentEdges:    [][2]int{{5, 4}},
+			CurrentVertices: []int{5},
+		},
+		ShowWeights: true,
+	}
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, &graph, opts); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+
+	counts := []struct {
+		what, substr string
+		want         int
+	}{
+		{"edges", "<line ", 5},
+		{"vertices", "<circle ", 5},
+		{"tour edges", `stroke="` + highlightColor + `" stroke-width="3.5"`, 2},
+		{"current edges", `stroke="` + currentColor + `" stroke-width="4.5"`, 1},
+		{"cover vertices", `fill="` + highlightColor + `" stroke="#333"`, 1},
+		{"current vertices", `fill="` + currentColor + `" stroke="#333"`, 1},
+		{"weight labels", `font-size="10"`, 5},
+		{"arrow markers", "<marker ", 0},
+	}
+	for _, c := range counts {
+		if got := strings.Count(svg, c.substr); got != c.want {
+			t.Errorf("%d %s drawn, want %d", got, c.what, c.want)
+		}
+	}
+	for _, escaped := range []string{"cover &amp; tour", "a&lt;b"} {
+		if !strings.Contains(svg, escaped) {
+			t.Errorf("SVG does not contain %q", escaped)
+		}
+	}
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Errorf("not a standalone SVG document:\n%s", svg)
+	}
+}
+
+func TestWriteSVGDirected(t *testing.T) {
+	graph := g.NewGraph(3, true, false)
+	graph.AddEdge(1, 2).AddEdge(2, 1).AddEdge(3, 3)
+	var buf bytes.Buffer
+	err := WriteSVG(&buf, &graph, Options{Layout: Circular, Overlay: Overlay{Edges: [][2]int{{2, 1}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	// Pętla nie jest rysowana, a podświetlenie dotyczy tylko łuku 2->1
+	if got := strings.Count(svg, "<line "); got != 2 {
+		t.Errorf("%d arcs drawn, want 2", got)
+	}
+	if got := strings.Count(svg, "url(#arrow-hl)"); got != 1 {
+		t.Errorf("%d highlighted arcs, want 1", got)
+	}
+	if got := strings.Count(svg, "url(#arrow)"); got != 1 {
+		t.Errorf("%d plain arcs, want 1", got)
+	}
+}
+
+func TestWriteSVGLayoutMismatch(t *testing.T) {
+	graph := cycle(4)
+	short := func(*g.Graph) ([]Point, error) { return make([]Point, 3), nil }
+	if err := WriteSVG(&bytes.Buffer{}, &graph, Options{Layout: short}); err == nil {
+		t.Error("no error for a layout with too few positions")
+	}
+	if err := WriteSVG(&bytes.Buffer{}, &graph, Options{Layout: Coordinates}); err == nil {
+		t.Error("no error for a coordinate layout without coordinates")
+	}
+}
+
+func TestFitKeepsMargins(t *testing.T) {
+	pos := fit([]Point{{-5, 2}, {10, 2}, {0, 7}}, 400, 300)
+	for i, p := range pos {
+		if p.X < margin-1e-9 || p.X > 400-margin+1e-9 || p.Y < margin-1e-9 || p.Y > 300-margin+1e-9 {
+			t.Errorf("point %d at %v, outside the margins", i+1, p)
+		}
+	}
+	// Proporcje zachowane: odcinek poziomy trzy razy dłuższy od pionowego
+	if w, h := pos[1].X-pos[0].X, pos[2].Y-pos[0].Y; math.Abs(w-3*h) > 1e-9 {
+		t.Errorf("aspect ratio changed: %g x %g", w, h)
+	}
+}
+
+func TestToSVG(t *testing.T) {
+	graph := cycle(4)
+	name := filepath.Join(t.TempDir(), "cycle.svg")
+	if err := ToSVG(&graph, name, Options{Layout: Circular}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(data), "<circle ") != 4 {
+		t.Errorf("file does not hold the drawing:\n%s", data)
+	}
+}
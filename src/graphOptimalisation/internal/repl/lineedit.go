@@ -0,0 +1,218 @@
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var errInterrupted = errors.New("interrupted")
+
+const maxHistory = 1000
+
+// lineEditor reads lines with history and tab completion when its input is
+// a terminal, and plain lines otherwise.
+type lineEditor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	fd       int
+	terminal bool
+	history  []string
+	// complete returns candidates replacing the word that ends at the
+	// cursor, given the line up to the cursor.
+	complete func(line string) []string
+}
+
+func newLineEditor(in io.Reader, out io.Writer) *lineEditor {
+	e := &lineEditor{in: bufio.NewReader(in), out: out, fd: -1}
+	if f, ok := in.(*os.File); ok && isTerminal(int(f.Fd())) {
+		e.fd = int(f.Fd())
+		e.terminal = true
+	}
+	return e
+}
+
+func (e *lineEditor) addHistory(line string) {
+	if line == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > maxHistory {
+		e.history = e.history[len(e.history)-maxHistory:]
+	}
+}
+
+func (e *lineEditor) loadHistory(filename string) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		e.addHistory(line)
+	}
+}
+
+func (e *lineEditor) saveHistory(filename string) error {
+	return os.WriteFile(filename, []byte(strings.Join(e.history, "\n")+"\n"), 0o600)
+}
+
+// readLine returns the next line without its newline. It returns io.EOF at
+// the end of input (Ctrl-D on an empty line) and errInterrupted on Ctrl-C.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	if !e.terminal {
+		line, err := e.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	state, err := makeRaw(e.fd)
+	if err != nil {
+		e.terminal = false
+		return e.readLine(prompt)
+	}
+	defer restore(e.fd, state)
+
+	var (
+		buf     []rune
+		pos     int
+		histIdx = len(e.history)
+		saved   []rune
+	)
+	refresh := func() {
+		fmt.Fprintf(e.out, "\r%s%s\x1b[K", prompt, string(buf))
+		if back := len(buf) - pos; back > 0 {
+			fmt.Fprintf(e.out, "\x1b[%dD", back)
+		}
+	}
+	refresh()
+
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(buf), nil
+		case 3: // Ctrl-C
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupted
+		case 4: // Ctrl-D
+			if len(buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+		case 1: // Ctrl-A
+			pos = 0
+		case 5: // Ctrl-E
+			pos = len(buf)
+		case 21: // Ctrl-U
+			buf, pos = buf[pos:], 0
+		case 127, 8: // Backspace
+			if pos > 0 {
+				buf = append(buf[:pos-1], buf[pos:]...)
+				pos--
+			}
+		case '\t':
+			buf, pos = e.completeAt(buf, pos, prompt)
+		case 27: // sekwencje ESC [ ...
+			if b, _ := e.in.ReadByte(); b != '[' {
+				continue
+			}
+			code, _ := e.in.ReadByte()
+			switch code {
+			case 'A', 'B':
+				if histIdx == len(e.history) {
+					saved = append([]rune(nil), buf...)
+				}
+				if code == 'A' && histIdx > 0 {
+					histIdx--
+				} else if code == 'B' && histIdx < len(e.history) {
+					histIdx++
+				}
+				if histIdx == len(e.history) {
+					buf = append([]rune(nil), saved...)
+				} else {
+					buf = []rune(e.history[histIdx])
+				}
+				pos = len(buf)
+			case 'C':
+				if pos < len(buf) {
+					pos++
+				}
+			case 'D':
+				if pos > 0 {
+					pos--
+				}
+			case 'H':
+				pos = 0
+			case 'F':
+				pos = len(buf)
+			case '3':
+				if b, _ := e.in.ReadByte(); b == '~' && pos < len(buf) {
+					buf = append(buf[:pos], buf[pos+1:]...)
+				}
+			}
+		default:
+			if r >= 32 {
+				buf = append(buf[:pos], append([]rune{r}, buf[pos:]...)...)
+				pos++
+			}
+		}
+		refresh()
+	}
+}
+
+// completeAt completes the word ending at pos. A single candidate replaces
+// the word; several candidates extend it to their common prefix and, if
+// that adds nothing, are listed below the prompt. pos counts runes, so the
+// line is cut and compared as runes, never as bytes.
+func (e *lineEditor) completeAt(buf []rune, pos int, prompt string) ([]rune, int) {
+	if e.complete == nil {
+		return buf, pos
+	}
+	start := pos
+	for start > 0 && buf[start-1] != ' ' && buf[start-1] != '\t' {
+		start--
+	}
+	word := buf[start:pos]
+
+	candidates := e.complete(string(buf[:pos]))
+	if len(candidates) == 0 {
+		return buf, pos
+	}
+
+	replacement := []rune(candidates[0])
+	if len(candidates) == 1 {
+		if !strings.HasSuffix(candidates[0], "/") {
+			replacement = append(replacement, ' ')
+		}
+	} else {
+		for _, c := range candidates[1:] {
+			replacement = replacement[:commonPrefix(replacement, []rune(c))]
+		}
+		if string(replacement) == string(word) {
+			fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+			return buf, pos
+		}
+	}
+
+	head := append(append([]rune(nil), buf[:start]...), replacement...)
+	return append(head, buf[pos:]...), len(head)
+}
+
+// commonPrefix returns the number of leading runes a and b share.
+func commonPrefix(a, b []rune) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,33 @@
+package repl
+
+import (
+	"io"
+	"testing"
+)
+
+func TestCompleteAt(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		pos        int
+		candidates []string
+		want       string
+		wantPos    int
+	}{
+		{"single candidate", "lo", 2, []string{"load"}, "load ", 5},
+		{"directory", "load da", 7, []string{"data/"}, "load data/", 10},
+		{"common prefix", "run ms", 6, []string{"mst", "mst-prim"}, "run mst", 7},
+		{"after a non-ASCII word", "new łódź ki", 11, []string{"kilka"}, "new łódź kilka ", 15},
+		{"cursor before the end", "load łąka.txt x", 9, []string{"łąka.txt"}, "load łąka.txt .txt x", 14},
+		{"prefix ending inside a rune", "", 0, []string{"ąa", "ęa"}, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &lineEditor{out: io.Discard, complete: func(string) []string { return tt.candidates }}
+			buf, pos := e.completeAt([]rune(tt.line), tt.pos, "> ")
+			if string(buf) != tt.want || pos != tt.wantPos {
+				t.Errorf("got %q at %d, want %q at %d", string(buf), pos, tt.want, tt.wantPos)
+			}
+		})
+	}
+}
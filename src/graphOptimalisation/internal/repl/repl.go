@@ -0,0 +1,485 @@
+// Package repl implements an interactive shell for building graphs and
+// running the algorithms of the graph package on them.
+package repl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+	"github.com/Simikao/graphOptimalisation/internal/graphio"
+	"github.com/Simikao/graphOptimalisation/internal/solver"
+)
+
+var errNoGraph = errors.New("no graph loaded, use 'new' or 'load' first")
+
+const maxUndo = 100
+
+type command struct {
+	args    string
+	help    string
+	mutates bool
+	run     func(r *REPL, args []string) error
+}
+
+var commands map[string]command
+
+// Polecenia są rejestrowane w init, bo "help" odwołuje się do samej mapy.
+func init() {
+	commands = map[string]command{
+		"help":          {"", "list commands", false, (*REPL).cmdHelp},
+		"new":           {"<n> [directed] [weighted]", "start a new graph with n vertices", true, (*REPL).cmdNew},
+		"load":          {"<file> [format] [directed]", "load a graph from a file", true, (*REPL).cmdLoad},
+		"save":          {"<file> [format]", "save the graph to a file", false, (*REPL).cmdSave},
+		"add-edge":      {"<u> <v> [weight]", "add an edge (AddEdge)", true, (*REPL).cmdAddEdge},
+		"remove-edge":   {"<u> <v>", "remove an edge (RemoveEdge)", true, (*REPL).cmdRemoveEdge},
+		"add-vertex":    {"", "add an isolated vertex (AddVertex)", true, (*REPL).cmdAddVertex},
+		"remove-vertex": {"<v>", "remove a vertex, renumbering the later ones (RemoveVertex)", true, (*REPL).cmdRemoveVertex},
+		"weight":        {"<u> <v>", "show an edge weight (GetWeight)", false, (*REPL).cmdWeight},
+		"set-weight":    {"<u> <v> <weight>", "change an edge weight (SetWeight)", true, (*REPL).cmdSetWeight},
+		"degree":        {"<v>", "degree of v (GetDegree, plus in/out for directed graphs)", false, (*REPL).cmdDegree},
+		"degrees":       {"", "degrees in descending order (SortedByDegrees)", false, (*REPL).cmdDegrees},
+		"minmax":        {"", "minimum and maximum degree (GetMinMaxDegree)", false, (*REPL).cmdMinMax},
+		"evenodd":       {"", "number of even and odd degree vertices", false, (*REPL).cmdEvenOdd},
+		"show":          {"", "print the adjacency and weight matrices (Inspect)", false, (*REPL).cmdShow},
+		"edges":         {"", "print the edge list", false, (*REPL).cmdEdges},
+		"run":           {"<solver> [logs]", "run a solver on the graph (see 'solvers')", false, (*REPL).cmdRun},
+		"solvers":       {"", "list the available solvers", false, (*REPL).cmdSolvers},
+		"undo":          {"", "revert the last change", false, (*REPL).cmdUndo},
+		"history":       {"", "list previous commands", false, (*REPL).cmdHistory},
+		"quit":          {"", "leave the shell (also 'exit' or Ctrl-D)", false, nil},
+	}
+}
+
+// REPL holds the graph being edited and the undo stack.
+type REPL struct {
+	graph  *g.Graph
+	undo   []g.Graph
+	out    io.Writer
+	editor *lineEditor
+	// HistoryFile, if set, is read on start and written on exit.
+	HistoryFile string
+	Prompt      string
+}
+
+func New(in io.Reader, out io.Writer) *REPL {
+	r := &REPL{out: out, editor: newLineEditor(in, out), Prompt: "graph> "}
+	r.editor.complete = r.complete
+	return r
+}
+
+// SetGraph makes graph the current graph.
+func (r *REPL) SetGraph(graph g.Graph) {
+	r.graph = &graph
+}
+
+// Run reads and executes commands until "quit" or end of input.
+func (r *REPL) Run() error {
+	if r.HistoryFile != "" {
+		r.editor.loadHistory(r.HistoryFile)
+		defer r.editor.saveHistory(r.HistoryFile)
+	}
+
+	for {
+		line, err := r.editor.readLine(r.Prompt)
+		if errors.Is(err, errInterrupted) {
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		r.editor.addHistory(line)
+		if line == "quit" || line == "exit" {
+			return nil
+		}
+		if err := r.Exec(line); err != nil {
+			fmt.Fprintf(r.out, "error: %v\n", err)
+		}
+	}
+}
+
+// Exec runs a single command line.
+func (r *REPL) Exec(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+	cmd, ok := commands[fields[0]]
+	if !ok || cmd.run == nil {
+		return fmt.Errorf("unknown command %q, try 'help'", fields[0])
+	}
+
+	if !cmd.mutates {
+		return cmd.run(r, fields[1:])
+	}
+
+	// Migawka przed zmianą; wycofujemy ją, jeśli polecenie się nie powiodło
+	var snapshot *g.Graph
+	if r.graph != nil {
+		clone := r.graph.Clone()
+		snapshot = &clone
+	}
+	if err := cmd.run(r, fields[1:]); err != nil {
+		return err
+	}
+	if snapshot != nil {
+		r.undo = append(r.undo, *snapshot)
+		if len(r.undo) > maxUndo {
+			r.undo = r.undo[1:]
+		}
+	}
+	return nil
+}
+
+func (r *REPL) requireGraph() error {
+	if r.graph == nil {
+		return errNoGraph
+	}
+	return nil
+}
+
+// vertexArgs parses want vertex numbers and checks they exist.
+func (r *REPL) vertexArgs(args []string, want int) ([]int, error) {
+	if err := r.requireGraph(); err != nil {
+		return nil, err
+	}
+	if len(args) < want {
+		return nil, fmt.Errorf("expected %d vertex arguments", want)
+	}
+	vertices := make([]int, want)
+	for i := range vertices {
+		v, err := strconv.Atoi(args[i])
+		if err != nil || v < 1 || v > len(r.graph.AdjMatrix) {
+			return nil, fmt.Errorf("vertex %q is not in 1..%d", args[i], len(r.graph.AdjMatrix))
+		}
+		vertices[i] = v
+	}
+	return vertices, nil
+}
+
+func (r *REPL) cmdHelp(args []string) error {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd := commands[name]
+		fmt.Fprintf(r.out, "  %-32s %s\n", strings.TrimSpace(name+" "+cmd.args), cmd.help)
+	}
+	return nil
+}
+
+func (r *REPL) cmdNew(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: new <n> [directed] [weighted]")
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 0 {
+		return fmt.Errorf("%q is not a vertex count", args[0])
+	}
+	var directed, weighted bool
+	for _, flag := range args[1:] {
+		switch flag {
+		case "directed":
+			directed = true
+		case "weighted":
+			weighted = true
+		default:
+			return fmt.Errorf("unknown option %q", flag)
+		}
+	}
+	r.SetGraph(g.NewGraph(n, directed, weighted))
+	return nil
+}
+
+func (r *REPL) cmdLoad(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: load <file> [format] [directed]")
+	}
+	format, directed := "", false
+	for _, arg := range args[1:] {
+		if arg == "directed" {
+			directed = true
+		} else {
+			format = arg
+		}
+	}
+	graph, err := graphio.Load(args[0], format, directed)
+	if err != nil {
+		return err
+	}
+	r.SetGraph(graph)
+	fmt.Fprintf(r.out, "loaded %d vertices, %d edges\n", len(graph.AdjMatrix), len(graph.Edges))
+	return nil
+}
+
+func (r *REPL) cmdSave(args []string) error {
+	if err := r.requireGraph(); err != nil {
+		return err
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("usage: save <file> [format]")
+	}
+	format := ""
+	if len(args) > 1 {
+		format = args[1]
+	}
+	return graphio.Save(r.graph, args[0], format)
+}
+
+func (r *REPL) cmdAddEdge(args []string) error {
+	vs, err := r.vertexArgs(args, 2)
+	if err != nil {
+		return err
+	}
+	var weights []float64
+	if len(args) > 2 {
+		if !r.graph.Weighted {
+			return fmt.Errorf("graph is unweighted")
+		}
+		w, err := strconv.ParseFloat(args[2], 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a weight", args[2])
+		}
+		weights = append(weights, w)
+	}
+	if r.graph.AdjMatrix[vs[0]-1][vs[1]-1] != 0 {
+		return fmt.Errorf("edge %d-%d already exists", vs[0], vs[1])
+	}
+	r.graph.AddEdge(vs[0], vs[1], weights...)
+	return nil
+}
+
+func (r *REPL) cmdRemoveEdge(args []string) error {
+	vs, err := r.vertexArgs(args, 2)
+	if err != nil {
+		return err
+	}
+	if !r.graph.HasEdge(vs[0], vs[1]) {
+		return fmt.Errorf("no edge %d-%d", vs[0], vs[1])
+	}
+	r.graph.RemoveEdge(vs[0], vs[1])
+	return nil
+}
+
+func (r *REPL) cmdAddVertex(args []string) error {
+	if err := r.requireGraph(); err != nil {
+		return err
+	}
+	r.graph.AddVertex()
+	fmt.Fprintf(r.out, "added vertex %d\n", len(r.graph.AdjMatrix))
+	return nil
+}
+
+func (r *REPL) cmdRemoveVertex(args []string) error {
+	vs, err := r.vertexArgs(args, 1)
+	if err != nil {
+		return err
+	}
+	r.graph.RemoveVertex(vs[0])
+	return nil
+}
+
+func (r *REPL) cmdWeight(args []string) error {
+	vs, err := r.vertexArgs(args, 2)
+	if err != nil {
+		return err
+	}
+	w, err := r.graph.GetWeight(vs[0], vs[1])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(r.out, w)
+	return nil
+}
+
+func (r *REPL) cmdSetWeight(args []string) error {
+	vs, err := r.vertexArgs(args, 2)
+	if err != nil {
+		return err
+	}
+	if len(args) < 3 {
+		return fmt.Errorf("usage: set-weight <u> <v> <weight>")
+	}
+	w, err := strconv.ParseFloat(args[2], 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a weight", args[2])
+	}
+	return r.graph.SetWeight(vs[0], vs[1], w)
+}
+
+func (r *REPL) cmdDegree(args []string) error {
+	vs, err := r.vertexArgs(args, 1)
+	if err != nil {
+		return err
+	}
+	v := vs[0]
+	if r.graph.Directed {
+		fmt.Fprintf(r.out, "degree %d (in %d, out %d)\n", r.graph.GetDegree(v), r.graph.GetInDegree(v), r.graph.GetOutDegree(v))
+	} else {
+		fmt.Fprintf(r.out, "degree %d\n", r.graph.GetDegree(v))
+	}
+	return nil
+}
+
+func (r *REPL) cmdDegrees(args []string) error {
+	if err := r.requireGraph(); err != nil {
+		return err
+	}
+	fmt.Fprintln(r.out, r.graph.SortedByDegrees())
+	return nil
+}
+
+func (r *REPL) cmdMinMax(args []string) error {
+	if err := r.requireGraph(); err != nil {
+		return err
+	}
+	if len(r.graph.AdjMatrix) == 0 {
+		return fmt.Errorf("graph has no vertices")
+	}
+	minDegree, maxDegree := r.graph.GetMinMaxDegree()
+	fmt.Fprintf(r.out, "min %d, max %d\n", minDegree, maxDegree)
+	return nil
+}
+
+func (r *REPL) cmdEvenOdd(args []string) error {
+	if err := r.requireGraph(); err != nil {
+		return err
+	}
+	even, odd := r.graph.GetEvenOddDegreeCounts()
+	fmt.Fprintf(r.out, "even %d, odd %d\n", even, odd)
+	return nil
+}
+
+func (r *REPL) cmdShow(args []string) error {
+	if err := r.requireGraph(); err != nil {
+		return err
+	}
+	fmt.Fprint(r.out, r.graph.String())
+	return nil
+}
+
+func (r *REPL) cmdEdges(args []string) error {
+	if err := r.requireGraph(); err != nil {
+		return err
+	}
+	fmt.Fprintln(r.out, r.graph.Edges)
+	return nil
+}
+
+func (r *REPL) cmdRun(args []string) error {
+	if err := r.requireGraph(); err != nil {
+		return err
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("usage: run <%s> [logs]", strings.Join(solver.Names(), "|"))
+	}
+	sv, err := solver.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	showLogs := len(args) > 1 && args[1] == "logs"
+
+	// Ctrl-C przerywa algorytm, a nie całą sesję; pokazujemy wtedy najlepszy dotąd wynik
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	res, err := solver.Run(ctx, sv, r.graph, solver.Options{Trace: showLogs})
+	if err != nil {
+		if !errors.Is(err, context.Canceled) || res.Solution == nil {
+			return err
+		}
+		fmt.Fprintln(r.out, "interrupted; showing the best result found so far")
+	}
+	fmt.Fprintf(r.out, "%s: %v\ncost: %g\n", args[0], res.Solution, res.Cost)
+	if showLogs {
+		fmt.Fprint(r.out, (&g.Recorder{Events: res.Trace}).String())
+	}
+	return nil
+}
+
+func (r *REPL) cmdSolvers(args []string) error {
+	for _, sv := range solver.All() {
+		fmt.Fprintf(r.out, "  %-12s %-16s %s\n", sv.Name(), sv.Problem(), sv.Description())
+	}
+	return nil
+}
+
+func (r *REPL) cmdUndo(args []string) error {
+	if len(r.undo) == 0 {
+		return fmt.Errorf("nothing to undo")
+	}
+	r.SetGraph(r.undo[len(r.undo)-1])
+	r.undo = r.undo[:len(r.undo)-1]
+	return nil
+}
+
+func (r *REPL) cmdHistory(args []string) error {
+	for i, line := range r.editor.history {
+		fmt.Fprintf(r.out, "%4d  %s\n", i+1, line)
+	}
+	return nil
+}
+
+// complete suggests command names, algorithm names, formats and file paths
+// depending on the position of the word being completed.
+func (r *REPL) complete(line string) []string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasSuffix(line, " ") {
+		fields = append(fields, "")
+	}
+	word := fields[len(fields)-1]
+
+	var options []string
+	switch {
+	case len(fields) == 1:
+		for name := range commands {
+			options = append(options, name)
+		}
+	case fields[0] == "run" && len(fields) == 2:
+		options = solver.Names()
+	case fields[0] == "run" && len(fields) == 3:
+		options = []string{"logs"}
+	case (fields[0] == "load" || fields[0] == "save") && len(fields) == 2:
+		return completePath(word)
+	case fields[0] == "load" || fields[0] == "save":
+		options = append(graphio.Formats(), "directed")
+	case fields[0] == "new" && len(fields) > 2:
+		options = []string{"directed", "weighted"}
+	}
+
+	var matches []string
+	for _, option := range options {
+		if strings.HasPrefix(option, word) {
+			matches = append(matches, option)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+func completePath(word string) []string {
+	matches, _ := filepath.Glob(word + "*")
+	for i, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.IsDir() {
+			matches[i] = match + string(filepath.Separator)
+		}
+	}
+	return matches
+}
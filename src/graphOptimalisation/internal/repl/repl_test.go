@@ -0,0 +1,33 @@
+package repl
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+func TestRemoveEdge(t *testing.T) {
+	graph := g.NewGraph(3, false, true)
+	graph.AddEdge(1, 2, 0)
+	r := New(strings.NewReader(""), io.Discard)
+	r.SetGraph(graph)
+
+	if err := r.Exec("remove-edge 2 3"); err == nil || !strings.Contains(err.Error(), "no edge 2-3") {
+		t.Errorf("removing a missing edge: got %v, want \"no edge 2-3\"", err)
+	}
+	if len(r.undo) != 0 {
+		t.Errorf("failed command left %d undo snapshots", len(r.undo))
+	}
+	// Krawędź o wadze 0 też istnieje i da się ją usunąć
+	if err := r.Exec("remove-edge 2 1"); err != nil {
+		t.Fatal(err)
+	}
+	if r.graph.HasEdge(1, 2) || len(r.graph.Edges) != 0 {
+		t.Errorf("edge 1-2 still present: %v", r.graph.Edges)
+	}
+	if err := r.Exec("remove-edge 1 2"); err == nil {
+		t.Error("removing the same edge twice succeeded")
+	}
+}
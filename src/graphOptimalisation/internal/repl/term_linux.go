@@ -0,0 +1,50 @@
+//go:build linux
+
+package repl
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+type termState struct {
+	termios syscall.Termios
+}
+
+func ioctl(fd int, req uint, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(req), uintptr(unsafe.Pointer(termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func isTerminal(fd int) bool {
+	var termios syscall.Termios
+	return ioctl(fd, syscall.TCGETS, &termios) == nil
+}
+
+// makeRaw switches the terminal to raw input mode (no echo, no line
+// buffering, no signals) and returns the state to restore afterwards.
+// Output processing is left on so "\n" still moves to a new line.
+func makeRaw(fd int) (*termState, error) {
+	var old syscall.Termios
+	if err := ioctl(fd, syscall.TCGETS, &old); err != nil {
+		return nil, err
+	}
+
+	raw := old
+	raw.Iflag &^= syscall.BRKINT | syscall.ICRNL | syscall.INPCK | syscall.ISTRIP | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.IEXTEN | syscall.ISIG
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := ioctl(fd, syscall.TCSETS, &raw); err != nil {
+		return nil, err
+	}
+	return &termState{termios: old}, nil
+}
+
+func restore(fd int, state *termState) error {
+	return ioctl(fd, syscall.TCSETS, &state.termios)
+}
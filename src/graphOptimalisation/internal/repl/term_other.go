@@ -0,0 +1,21 @@
+//go:build !linux
+
+package repl
+
+import "errors"
+
+type termState struct{}
+
+// Poza Linuksem nie przełączamy terminala w tryb surowy; REPL czyta wtedy
+// zwykłe linie, bez uzupełniania tabulatorem i strzałek historii.
+func isTerminal(fd int) bool {
+	return false
+}
+
+func makeRaw(fd int) (*termState, error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
+
+func restore(fd int, state *termState) error {
+	return nil
+}
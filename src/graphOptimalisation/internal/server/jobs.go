@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+const (
+	statusQueued   = "queued"
+	statusRunning  = "running"
+	statusDone     = "done"
+	statusFailed   = "failed"
+	statusCanceled = "canceled"
+)
+
+type job struct {
+	ID string
+
+	mu       sync.Mutex
+	status   string
+	result   *Result
+	err      string
+	progress *JobProgress
+	created  time.Time
+	finished time.Time
+	stop     context.CancelFunc
+}
+
+// JobStatus is the JSON returned when polling a job.
+type JobStatus struct {
+	ID       string       `json:"id"`
+	Status   string       `json:"status"`
+	Progress *JobProgress `json:"progress,omitempty"`
+	Result   *Result      `json:"result,omitempty"`
+	Error    string       `json:"error,omitempty"`
+	Created  time.Time    `json:"created"`
+	Finished *time.Time   `json:"finished,omitempty"`
+}
+
+// JobProgress is the last progress report of a running job.
+type JobProgress struct {
+	Algorithm string   `json:"algorithm"`
+	Phase     string   `json:"phase"`
+	Done      int      `json:"done"`
+	Total     int      `json:"total,omitempty"`
+	Best      *float64 `json:"best,omitempty"`
+}
+
+func (j *job) setProgress(p g.Progress) {
+	jp := &JobProgress{Algorithm: p.Algorithm, Phase: p.Phase, Done: p.Done, Total: p.Total}
+	// NaN nie da się zapisać w JSON
+	if !math.IsNaN(p.Best) {
+		best := p.Best
+		jp.Best = &best
+	}
+	j.mu.Lock()
+	j.progress = jp
+	j.mu.Unlock()
+}
+
+func (j *job) snapshot() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	st := JobStatus{ID: j.ID, Status: j.status, Progress: j.progress, Result: j.result, Error: j.err, Created: j.created}
+	if !j.finished.IsZero() {
+		finished := j.finished
+		st.Finished = &finished
+	}
+	return st
+}
+
+func (j *job) cancel() {
+	j.stop()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.status == statusQueued || j.status == statusRunning {
+		j.status = statusCanceled
+		j.finished = time.Now()
+	}
+}
+
+func (j *job) finish(res Result, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if res.Incomplete {
+		// Wynik częściowy zostaje przy zadaniu także po anulowaniu
+		j.result = &res
+	}
+	if j.status == statusCanceled {
+		return
+	}
+	j.finished = time.Now()
+	switch {
+	case errors.Is(err, context.Canceled):
+		j.status = statusCanceled
+	case err != nil:
+		j.status, j.err = statusFailed, err.Error()
+	default:
+		j.status, j.result = statusDone, &res
+	}
+}
+
+var errQueueFull = errors.New("too many pending jobs, try again later")
+
+type jobStore struct {
+	mu      sync.Mutex
+	jobs    map[string]*job
+	slots   chan struct{}
+	pending chan struct{} // zadania w kolejce lub w trakcie
+	ttl     time.Duration
+}
+
+func newJobStore(workers, maxPending int, ttl time.Duration) *jobStore {
+	return &jobStore{
+		jobs:    make(map[string]*job),
+		slots:   make(chan struct{}, workers),
+		pending: make(chan struct{}, maxPending),
+		ttl:     ttl,
+	}
+}
+
+func newJobID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// submit queues a job; at most cap(slots) jobs run at the same time. A slot
+// is given back only when the algorithm has returned, also after a timeout
+// or a cancellation. With cap(pending) jobs already queued or running it
+// fails with errQueueFull.
+func (s *jobStore) submit(req Request, graph g.Graph, timeout time.Duration) (*job, error) {
+	select {
+	case s.pending <- struct{}{}:
+	default:
+		return nil, errQueueFull
+	}
+	ctx, stop := context.WithTimeout(context.Background(), timeout)
+	j := &job{ID: newJobID(), status: statusQueued, created: time.Now(), stop: stop}
+
+	s.mu.Lock()
+	s.evict()
+	s.jobs[j.ID] = j
+	s.mu.Unlock()
+
+	go func() {
+		defer func() { <-s.pending }()
+		defer stop()
+		select {
+		case s.slots <- struct{}{}:
+		case <-ctx.Done():
+			j.finish(Result{}, ctx.Err())
+			return
+		}
+		defer func() { <-s.slots }()
+
+		j.mu.Lock()
+		if j.status == statusQueued {
+			j.status = statusRunning
+		}
+		j.mu.Unlock()
+
+		res, err := solve(ctx, req, graph, j.setProgress)
+		j.finish(res, err)
+	}()
+	return j, nil
+}
+
+func (s *jobStore) get(id string) (*job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	return j, ok
+}
+
+// evict drops jobs that finished more than ttl ago. Callers hold s.mu.
+func (s *jobStore) evict() {
+	for id, j := range s.jobs {
+		j.mu.Lock()
+		expired := !j.finished.IsZero() && time.Since(j.finished) > s.ttl
+		j.mu.Unlock()
+		if expired {
+			delete(s.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+// Package server exposes the solvers of the graph package over HTTP/JSON.
+//
+//	POST   /solve       run an algorithm and wait for the result
+//	POST   /jobs        submit the same request asynchronously
+//	GET    /jobs/{id}   poll a job
+//	DELETE /jobs/{id}   cancel a job
+//	GET    /algorithms  list the available algorithms
+//	GET    /healthz     liveness check
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+	"github.com/Simikao/graphOptimalisation/internal/graphio"
+	"github.com/Simikao/graphOptimalisation/internal/solver"
+	"github.com/Simikao/graphOptimalisation/internal/validate"
+)
+
+var errTimeout = errors.New("timeout exceeded")
+
+type Config struct {
+	// Timeout applies when a request does not set timeout_ms.
+	Timeout time.Duration
+	// MaxTimeout caps the timeout a request may ask for.
+	MaxTimeout time.Duration
+	// MaxBodyBytes limits the size of a request body.
+	MaxBodyBytes int64
+	// MaxVertices limits the size of a request graph. Graphs are stored as
+	// n×n matrices, so a body of a few bytes can ask for gigabytes; the
+	// limit is checked before they are allocated. The default of 1000 keeps
+	// the matrices of one graph around 16 MB.
+	MaxVertices int
+	// Workers is the number of jobs that may run at the same time.
+	Workers int
+	// MaxPendingJobs limits the jobs that are queued or running; further
+	// submissions are refused with 503 until some finish.
+	MaxPendingJobs int
+	// JobTTL is how long finished jobs are kept for polling.
+	JobTTL time.Duration
+}
+
+func (c *Config) defaults() {
+	if c.Timeout <= 0 {
+		c.Timeout = 30 * time.Second
+	}
+	if c.MaxTimeout <= 0 {
+		c.MaxTimeout = 10 * time.Minute
+	}
+	if c.MaxBodyBytes <= 0 {
+		c.MaxBodyBytes = 32 << 20
+	}
+	if c.MaxVertices <= 0 {
+		c.MaxVertices = 1000
+	}
+	if c.Workers <= 0 {
+		c.Workers = 4
+	}
+	if c.MaxPendingJobs <= 0 {
+		c.MaxPendingJobs = 64
+	}
+	if c.JobTTL <= 0 {
+		c.JobTTL = time.Hour
+	}
+}
+
+// Request is the body of POST /solve and POST /jobs. Algorithm is the name
+// of a registered solver (GET /algorithms lists them). Graph is either a JSON
+// graph object (format "json", the default) or a string holding the file
+// contents in another format, e.g. "dot" or "edgelist".
+type Request struct {
+	Algorithm string          `json:"algorithm"`
+	Format    string          `json:"format,omitempty"`
+	Directed  bool            `json:"directed,omitempty"`
+	Graph     json.RawMessage `json:"graph"`
+	TimeoutMS int64           `json:"timeout_ms,omitempty"`
+	// Trace asks for the steps of the algorithm as events.
+	Trace bool `json:"trace,omitempty"`
+	// Verify checks the solution with the validate package; an invalid
+	// solution is reported as an error listing the violations.
+	Verify bool `json:"verify,omitempty"`
+}
+
+// Result mirrors the JSON printed by the CLI.
+type Result struct {
+	Algorithm string `json:"algorithm"`
+	Problem   string `json:"problem"`
+	Solution  any    `json:"solution"`
+	// Cost is null if it is not finite, e.g. for a tour that cannot be
+	// closed.
+	Cost      *float64  `json:"cost"`
+	Bound     *float64  `json:"bound,omitempty"`
+	Trace     []g.Event `json:"trace,omitempty"`
+	RuntimeMS float64   `json:"runtime_ms"`
+	// Incomplete marks the best result found before the time limit or a
+	// cancellation stopped the algorithm; Error says which.
+	Incomplete bool   `json:"incomplete,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func newResult(res solver.Result) Result {
+	out := Result{
+		Algorithm: res.Solver,
+		Problem:   string(res.Problem),
+		Solution:  res.Solution,
+		Trace:     res.Trace,
+		RuntimeMS: float64(res.Runtime.Microseconds()) / 1000,
+	}
+	if !math.IsInf(res.Cost, 0) && !math.IsNaN(res.Cost) {
+		out.Cost = &res.Cost
+	}
+	if res.HasBound() {
+		out.Bound = &res.Bound
+	}
+	return out
+}
+
+type Server struct {
+	cfg  Config
+	jobs *jobStore
+	mux  *http.ServeMux
+}
+
+func New(cfg Config) *Server {
+	cfg.defaults()
+	s := &Server{cfg: cfg, jobs: newJobStore(cfg.Workers, cfg.MaxPendingJobs, cfg.JobTTL)}
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("POST /solve", s.handleSolve)
+	s.mux.HandleFunc("POST /jobs", s.handleSubmit)
+	s.mux.HandleFunc("GET /jobs/{id}", s.handleJob)
+	s.mux.HandleFunc("DELETE /jobs/{id}", s.handleCancel)
+	s.mux.HandleFunc("GET /algorithms", s.handleAlgorithms)
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	return s
+}
+
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// writeJSON encodes v before writing the header, so that a value JSON
+// cannot hold becomes a 500 with the error instead of an empty body.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		status = http.StatusInternalServerError
+		buf.Reset()
+		json.NewEncoder(&buf).Encode(map[string]string{"error": fmt.Sprintf("encoding response: %v", err)})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	body := map[string]any{"error": err.Error()}
+	var verr *validate.Error
+	if errors.As(err, &verr) {
+		body["violations"] = verr.Violations
+	}
+	writeJSON(w, status, body)
+}
+
+// parseRequest decodes the body and the graph it carries.
+func (s *Server) parseRequest(w http.ResponseWriter, r *http.Request) (Request, g.Graph, error) {
+	var req Request
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.cfg.MaxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return req, g.Graph{}, fmt.Errorf("invalid request body: %w", err)
+	}
+	if _, err := solver.Lookup(req.Algorithm); err != nil {
+		return req, g.Graph{}, err
+	}
+	if len(req.Graph) == 0 {
+		return req, g.Graph{}, fmt.Errorf("missing graph")
+	}
+
+	format := req.Format
+	if format == "" {
+		format = graphio.JSON
+	}
+	raw := []byte(req.Graph)
+	if raw[0] == '"' {
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return req, g.Graph{}, err
+		}
+		raw = []byte(text)
+	}
+	graph, err := graphio.Read(bytes.NewReader(raw), format, graphio.ReadOptions{Directed: req.Directed, MaxVertices: s.cfg.MaxVertices})
+	if err != nil {
+		return req, g.Graph{}, fmt.Errorf("invalid graph: %w", err)
+	}
+	return req, graph, nil
+}
+
+func (s *Server) timeout(req Request) time.Duration {
+	if req.TimeoutMS <= 0 {
+		return s.cfg.Timeout
+	}
+	return min(time.Duration(req.TimeoutMS)*time.Millisecond, s.cfg.MaxTimeout)
+}
+
+func (s *Server) handleSolve(w http.ResponseWriter, r *http.Request) {
+	req, graph, err := s.parseRequest(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.timeout(req))
+	defer cancel()
+	res, err := solve(ctx, req, graph, nil)
+	switch {
+	case errors.Is(err, errTimeout) && res.Incomplete:
+		writeJSON(w, http.StatusGatewayTimeout, res)
+	case errors.Is(err, errTimeout):
+		writeError(w, http.StatusGatewayTimeout, err)
+	case errors.Is(err, context.Canceled):
+		// klient się rozłączył; nie ma komu odpowiedzieć
+	case err != nil:
+		writeError(w, http.StatusUnprocessableEntity, err)
+	default:
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	req, graph, err := s.parseRequest(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	job, err := s.jobs.submit(req, graph, s.timeout(req))
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job.snapshot())
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobs.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no job %q", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, job.snapshot())
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobs.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no job %q", r.PathValue("id")))
+		return
+	}
+	job.cancel()
+	writeJSON(w, http.StatusOK, job.snapshot())
+}
+
+// algorithmInfo describes a solver in GET /algorithms.
+type algorithmInfo struct {
+	Name        string `json:"name"`
+	Problem     string `json:"problem"`
+	Description string `json:"description"`
+}
+
+func (s *Server) handleAlgorithms(w http.ResponseWriter, r *http.Request) {
+	var list []algorithmInfo
+	for _, sv := range solver.All() {
+		list = append(list, algorithmInfo{sv.Name(), string(sv.Problem()), sv.Description()})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"algorithms": list, "formats": graphio.Formats()})
+}
+
+// solve runs the requested algorithm and returns when it does. When ctx
+// expires the algorithm stops at its next check and solve returns its best
+// result so far, marked as incomplete, together with the error. Progress
+// goes to progress, which may be nil.
+func solve(ctx context.Context, req Request, graph g.Graph, progress g.ProgressFunc) (res Result, err error) {
+	sv, err := solver.Lookup(req.Algorithm)
+	if err != nil {
+		return Result{}, err
+	}
+	// Błąd w algorytmie nie może położyć całego serwera
+	defer func() {
+		if p := recover(); p != nil {
+			res, err = Result{}, fmt.Errorf("%s: internal error: %v", req.Algorithm, p)
+		}
+	}()
+	out, err := solver.Run(ctx, sv, &graph, solver.Options{Trace: req.Trace, Verify: req.Verify, Progress: progress})
+	if err == nil {
+		return newResult(out), nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = errTimeout
+	}
+	if ctx.Err() == nil || out.Solution == nil {
+		return Result{}, err
+	}
+	res = newResult(out)
+	res.Incomplete, res.Error = true, err.Error()
+	return res, err
+}
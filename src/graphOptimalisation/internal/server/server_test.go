@@ -0,0 +1,161 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+	"github.com/Simikao/graphOptimalisation/internal/solver"
+)
+
+func post(t *testing.T, s *Server, path, body string) (int, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	var out map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("%s: %v in %q", path, err, rec.Body.String())
+	}
+	return rec.Code, out
+}
+
+func TestSolveMaxVertices(t *testing.T) {
+	s := New(Config{MaxVertices: 10})
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"small graph", `{"algorithm": "mst", "format": "edgelist", "graph": "1 2 1\n2 3 2"}`, http.StatusOK},
+		{"large vertex id", `{"algorithm": "mst", "format": "edgelist", "graph": "1 100000000"}`, http.StatusBadRequest},
+		{"large header", `{"algorithm": "mst", "graph": {"vertices": 100000000, "edges": []}}`, http.StatusBadRequest},
+		{"huge json vertex id", `{"algorithm": "mst", "graph": {"edges": [[1, 1e300]]}}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, out := post(t, s, "/solve", tt.body)
+			if status != tt.status {
+				t.Errorf("got status %d (%v), want %d", status, out, tt.status)
+			}
+		})
+	}
+}
+
+// completeGraph returns an edge list of the complete graph on n vertices,
+// big enough for Held-Karp to run into any short time limit.
+func completeGraph(n int) string {
+	var b strings.Builder
+	for u := 1; u <= n; u++ {
+		for v := u + 1; v <= n; v++ {
+			fmt.Fprintf(&b, "%d %d %d\n", u, v, (u*v)%17+1)
+		}
+	}
+	return b.String()
+}
+
+func TestSolveTimeoutReturnsPartialResult(t *testing.T) {
+	s := New(Config{})
+	body := fmt.Sprintf(`{"algorithm": "tsp-exact", "format": "edgelist", "timeout_ms": 20, "graph": %q}`, completeGraph(20))
+	status, out := post(t, s, "/solve", body)
+	if status != http.StatusGatewayTimeout {
+		t.Fatalf("got status %d, want %d", status, http.StatusGatewayTimeout)
+	}
+	if out["incomplete"] != true || out["solution"] == nil {
+		t.Errorf("got %v, want the best tour so far marked as incomplete", out)
+	}
+}
+
+func TestJobTimeoutKeepsPartialResult(t *testing.T) {
+	s := New(Config{Workers: 1})
+	body := fmt.Sprintf(`{"algorithm": "tsp-exact", "format": "edgelist", "timeout_ms": 20, "graph": %q}`, completeGraph(20))
+	_, out := post(t, s, "/jobs", body)
+	id, _ := out["id"].(string)
+	job, ok := s.jobs.get(id)
+	if !ok {
+		t.Fatalf("job %q not found in %v", id, out)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for job.snapshot().Finished == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("job did not finish")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	st := job.snapshot()
+	if st.Status != statusFailed || st.Result == nil || !st.Result.Incomplete {
+		t.Errorf("got status %q with result %+v, want a failed job with a partial result", st.Status, st.Result)
+	}
+	// Slot zwalniany jest dopiero po powrocie algorytmu
+	select {
+	case s.jobs.slots <- struct{}{}:
+		<-s.jobs.slots
+	case <-time.After(time.Second):
+		t.Error("slot not released after the job finished")
+	}
+}
+
+func TestSubmitRefusedWhenQueueIsFull(t *testing.T) {
+	s := New(Config{Workers: 1, MaxPendingJobs: 1})
+	long := fmt.Sprintf(`{"algorithm": "tsp-exact", "format": "edgelist", "timeout_ms": 60000, "graph": %q}`, completeGraph(20))
+	status, out := post(t, s, "/jobs", long)
+	if status != http.StatusAccepted {
+		t.Fatalf("first job: got status %d (%v)", status, out)
+	}
+	if status, out := post(t, s, "/jobs", long); status != http.StatusServiceUnavailable {
+		t.Fatalf("second job: got status %d (%v), want %d", status, out, http.StatusServiceUnavailable)
+	}
+
+	// Po anulowaniu pierwszego zadania miejsce w kolejce się zwalnia
+	id, _ := out["id"].(string)
+	job, ok := s.jobs.get(id)
+	if !ok {
+		t.Fatalf("job %q not found", id)
+	}
+	job.cancel()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		status, _ := post(t, s, "/jobs", `{"algorithm": "mst", "format": "edgelist", "graph": "1 2 1"}`)
+		if status == http.StatusAccepted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("queue still full after the job was canceled, status %d", status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWriteJSONNonFinite(t *testing.T) {
+	res := newResult(solver.Result{
+		Solver:   "tsp-exact",
+		Solution: []int{1, 2, 3},
+		Cost:     math.Inf(1),
+		Trace:    []g.Event{{Kind: g.EventMatchCandidate, Vertices: []int{1, 2}, Value: math.Inf(1)}},
+	})
+	rec := httptest.NewRecorder()
+	writeJSON(rec, http.StatusGatewayTimeout, res)
+	var out map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("%v in %q", err, rec.Body.String())
+	}
+	if rec.Code != http.StatusGatewayTimeout || out["cost"] != nil || out["solution"] == nil {
+		t.Errorf("got status %d and %v, want %d with a null cost", rec.Code, out, http.StatusGatewayTimeout)
+	}
+
+	// Czego nie da się zapisać w JSON, kończy się błędem, a nie pustą odpowiedzią
+	rec = httptest.NewRecorder()
+	writeJSON(rec, http.StatusOK, map[string]float64{"x": math.NaN()})
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("%v in %q", err, rec.Body.String())
+	}
+	if rec.Code != http.StatusInternalServerError || out["error"] == nil {
+		t.Errorf("got status %d and %v, want %d with an error", rec.Code, out, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,134 @@
+package solver
+
+import (
+	"context"
+	"math"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+func init() {
+	Register(New("cover", VertexCover, "2-approximate vertex cover from a maximal matching", cover))
+	Register(New("cover-exact", VertexCover, "minimum vertex cover by branch and bound", coverExact))
+	Register(New("cover-konig", VertexCover, "minimum vertex cover of a bipartite graph by König's theorem", coverKonig))
+	Register(New("tsp", TSP, "Christofides tour on a metric graph", tsp))
+	Register(New("tsp-exact", TSP, "optimal tour by Held-Karp (at most 20 vertices)", tspExact))
+	Register(New("cpp", ChinesePostman, "Chinese postman circuit", cpp))
+	Register(New("mst", SpanningTree, "minimum spanning tree (Kruskal)", spanningTree(g.MSTOptions{Algorithm: g.MSTKruskal})))
+	Register(New("mst-prim", SpanningTree, "minimum spanning tree (Prim, binary heap)", spanningTree(g.MSTOptions{Algorithm: g.MSTPrim})))
+	Register(New("mst-boruvka", SpanningTree, "minimum spanning tree (parallel Borůvka)", spanningTree(g.MSTOptions{Algorithm: g.MSTBoruvka})))
+}
+
+// matchingBound is a lower bound on the size of a vertex cover: every cover
+// contains an endpoint of each edge of a maximum matching, and every vertex
+// with a loop. It is NaN if the matching cannot be computed.
+func matchingBound(ctx context.Context, graph *g.Graph, progress g.ProgressFunc) float64 {
+	m, err := graph.MaximumMatching(ctx, progress)
+	if err != nil {
+		return math.NaN()
+	}
+	bound := len(m.Edges)
+	for _, e := range graph.Edges {
+		// Wolny wierzchołek z pętlą; po zliczeniu oznaczamy go jako zajęty
+		if e[0] == e[1] && m.Mate[e[0]-1] == 0 {
+			m.Mate[e[0]-1] = e[0]
+			bound++
+		}
+	}
+	return float64(bound)
+}
+
+func cover(ctx context.Context, graph *g.Graph, t g.Tracer, progress g.ProgressFunc) (Result, error) {
+	bound := matchingBound(ctx, graph, progress)
+	c, err := graph.ApproximateVertexCover(ctx, t)
+	if math.IsNaN(bound) {
+		// Pokrycie składa się z obu końców krawędzi skojarzenia maksymalnego,
+		// a każde pokrycie musi zawierać po jednym końcu każdej z nich
+		bound = float64(len(c) / 2)
+	}
+	return Result{Solution: c, Cost: float64(len(c)), Bound: bound}, err
+}
+
+func coverExact(ctx context.Context, graph *g.Graph, t g.Tracer, progress g.ProgressFunc) (Result, error) {
+	// Ograniczenie liczymy przed przeszukiwaniem, żeby zostało po przerwaniu
+	bound := matchingBound(ctx, graph, progress)
+	c, err := graph.MinimumVertexCover(ctx, progress)
+	if err != nil {
+		return Result{Solution: c, Cost: float64(len(c)), Bound: bound}, err
+	}
+	return Result{Solution: c, Cost: float64(len(c)), Bound: float64(len(c))}, nil
+}
+
+func coverKonig(ctx context.Context, graph *g.Graph, t g.Tracer, progress g.ProgressFunc) (Result, error) {
+	c, err := graph.KonigVertexCover(ctx, progress)
+	if err != nil {
+		return Result{Bound: math.NaN()}, err
+	}
+	return Result{Solution: c, Cost: float64(len(c)), Bound: float64(len(c))}, nil
+}
+
+func tsp(ctx context.Context, graph *g.Graph, t g.Tracer, progress g.ProgressFunc) (Result, error) {
+	tour, err := graph.Christofides(ctx, t, progress)
+	if err != nil {
+		return Result{Bound: math.NaN()}, err
+	}
+	cost := TourCost(graph, tour)
+	// Trasa bez jednej krawędzi jest drzewem rozpinającym, więc optimum nie
+	// jest lżejsze od MST. Christofides dobiera skojarzenie zachłannie, więc
+	// gwarancja 3/2 tu nie obowiązuje
+	bound := math.NaN()
+	if forest, err := graph.MinimumSpanningTree(ctx, g.MSTOptions{}, nil); err == nil {
+		bound = forest.Weight
+	}
+	return Result{Solution: tour, Cost: cost, Bound: bound}, nil
+}
+
+func tspExact(ctx context.Context, graph *g.Graph, t g.Tracer, progress g.ProgressFunc) (Result, error) {
+	tour, cost, err := graph.OptimalTour(ctx, progress)
+	if err != nil {
+		// Po przerwaniu OptimalTour zwraca trasę najbliższego sąsiada
+		if tour == nil {
+			return Result{Bound: math.NaN()}, err
+		}
+		return Result{Solution: tour, Cost: cost, Bound: math.NaN()}, err
+	}
+	return Result{Solution: tour, Cost: cost, Bound: cost}, nil
+}
+
+func cpp(ctx context.Context, graph *g.Graph, t g.Tracer, progress g.ProgressFunc) (Result, error) {
+	// ChinesePostmanProblem dokłada krawędzie do grafu, więc pracujemy na kopii
+	work := graph.Clone()
+	circuit, cost, err := work.ChinesePostmanProblem(ctx, t, progress)
+	if err != nil {
+		return Result{Bound: math.NaN()}, err
+	}
+	return Result{Solution: circuit, Cost: cost, Bound: postmanBound(graph)}, nil
+}
+
+// postmanBound is the total weight of the edges: a circuit uses each of them
+// at least once. ChinesePostmanProblem pairs the odd vertices greedily, so
+// its own cost is not a bound. With negative weights repeating an edge can
+// only help, and the bound is NaN.
+func postmanBound(graph *g.Graph) float64 {
+	for _, e := range graph.Edges {
+		if graph.Weighted && graph.WeightMatrix[e[0]-1][e[1]-1] < 0 {
+			return math.NaN()
+		}
+	}
+	return EdgeCost(graph, graph.Edges)
+}
+
+func spanningTree(opts g.MSTOptions) Func {
+	return func(ctx context.Context, graph *g.Graph, t g.Tracer, progress g.ProgressFunc) (Result, error) {
+		opts := opts
+		opts.Progress = progress
+		forest, err := graph.MinimumSpanningTree(ctx, opts, t)
+		if forest == nil {
+			return Result{Bound: math.NaN()}, err
+		}
+		if err != nil {
+			return Result{Solution: forest.Edges, Cost: forest.Weight, Bound: math.NaN()}, err
+		}
+		return Result{Solution: forest.Edges, Cost: forest.Weight, Bound: forest.Weight}, nil
+	}
+}
@@ -0,0 +1,239 @@
+// Package solver puts the algorithms of the graph package behind a common
+// interface and a registry keyed by name, so the CLI, the server, the REPL
+// and the benchmark can enumerate them instead of each keeping its own list.
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+	"github.com/Simikao/graphOptimalisation/internal/validate"
+)
+
+// Problem names what a solver solves; solvers of the same problem return
+// solutions of the same type and can be compared with each other.
+type Problem string
+
+const (
+	// VertexCover solutions are []int of vertices.
+	VertexCover Problem = "vertex-cover"
+	// TSP solutions are []int tours, closed implicitly.
+	TSP Problem = "tsp"
+	// ChinesePostman solutions are []int closed walks.
+	ChinesePostman Problem = "chinese-postman"
+	// SpanningTree solutions are [][2]int edges.
+	SpanningTree Problem = "spanning-tree"
+)
+
+var ErrUnknownSolver = errors.New("unknown solver")
+
+// Options are shared by every solver.
+type Options struct {
+	// Timeout limits the run; zero means no limit.
+	Timeout time.Duration
+	// Trace records the steps of the algorithm in Result.Trace.
+	Trace bool
+	// Tracer, if set, also receives the steps as they happen.
+	Tracer g.Tracer
+	// Progress, if set, receives progress reports of the algorithm.
+	Progress g.ProgressFunc
+	// Verify checks the solution with the validate package.
+	Verify bool
+}
+
+// Result is what every solver returns.
+type Result struct {
+	Solver   string
+	Problem  Problem
+	Solution any
+	Cost     float64
+	// Bound is a lower bound on the optimal cost, or NaN if unknown. For an
+	// exact solver it equals Cost.
+	Bound   float64
+	Runtime time.Duration
+	Trace   []g.Event
+}
+
+// Solver is one algorithm for one problem. Solve must not modify graph. t
+// and progress may be nil.
+type Solver interface {
+	Name() string
+	Problem() Problem
+	Description() string
+	Solve(ctx context.Context, graph *g.Graph, t g.Tracer, progress g.ProgressFunc) (Result, error)
+}
+
+// Func is the body of a solver created with New. It fills in Solution, Cost
+// and Bound of the result; the rest is done by Run.
+type Func func(ctx context.Context, graph *g.Graph, t g.Tracer, progress g.ProgressFunc) (Result, error)
+
+type funcSolver struct {
+	name, description string
+	problem           Problem
+	fn                Func
+}
+
+func (s funcSolver) Name() string        { return s.name }
+func (s funcSolver) Problem() Problem    { return s.problem }
+func (s funcSolver) Description() string { return s.description }
+
+func (s funcSolver) Solve(ctx context.Context, graph *g.Graph, t g.Tracer, progress g.ProgressFunc) (Result, error) {
+	return s.fn(ctx, graph, t, progress)
+}
+
+// New returns a Solver that runs fn.
+func New(name string, problem Problem, description string, fn Func) Solver {
+	return funcSolver{name: name, description: description, problem: problem, fn: fn}
+}
+
+var (
+	registry = make(map[string]Solver)
+	order    []string
+)
+
+// Register adds s to the registry. It panics if the name is taken, like
+// registering the same driver twice in database/sql.
+func Register(s Solver) {
+	if _, ok := registry[s.Name()]; ok {
+		panic("solver: Register called twice for " + s.Name())
+	}
+	registry[s.Name()] = s
+	order = append(order, s.Name())
+}
+
+// All returns the registered solvers in the order they were registered.
+func All() []Solver {
+	out := make([]Solver, len(order))
+	for i, name := range order {
+		out[i] = registry[name]
+	}
+	return out
+}
+
+// Names returns the names of the registered solvers.
+func Names() []string {
+	return append([]string(nil), order...)
+}
+
+// ForProblem returns the solvers of problem.
+func ForProblem(problem Problem) []Solver {
+	var out []Solver
+	for _, s := range All() {
+		if s.Problem() == problem {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Problems returns the problems that have at least one solver, sorted.
+func Problems() []Problem {
+	seen := make(map[Problem]bool)
+	var out []Problem
+	for _, s := range All() {
+		if !seen[s.Problem()] {
+			seen[s.Problem()] = true
+			out = append(out, s.Problem())
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+func Lookup(name string) (Solver, error) {
+	if s, ok := registry[name]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("%w %q (known: %s)", ErrUnknownSolver, name, strings.Join(order, ", "))
+}
+
+// LookupAll returns the solvers with the given names, in that order.
+func LookupAll(names []string) ([]Solver, error) {
+	out := make([]Solver, 0, len(names))
+	for _, name := range names {
+		s, err := Lookup(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// Run runs s on graph with opts and fills in the name, problem, runtime and
+// trace of the result. Like the algorithms themselves, it returns the best
+// result found so far together with the error if ctx is cancelled or the
+// timeout expires; Solution is nil if there is none.
+func Run(ctx context.Context, s Solver, graph *g.Graph, opts Options) (Result, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	var rec *g.Recorder
+	t := opts.Tracer
+	if opts.Trace {
+		rec = &g.Recorder{}
+		t = tee(rec, opts.Tracer)
+	}
+
+	start := time.Now()
+	res, err := s.Solve(ctx, graph, t, opts.Progress)
+	res.Runtime = time.Since(start)
+	res.Solver, res.Problem = s.Name(), s.Problem()
+	if rec != nil {
+		res.Trace = rec.Events
+	}
+	if err == nil && opts.Verify {
+		err = validate.Solution(string(s.Problem()), graph, res.Solution)
+	}
+	return res, err
+}
+
+// tee sends every event to both tracers; b may be nil.
+func tee(a, b g.Tracer) g.Tracer {
+	if b == nil {
+		return a
+	}
+	return g.TracerFunc(func(e g.Event) {
+		a.Trace(e)
+		b.Trace(e)
+	})
+}
+
+// TourCost sums the shortest-path distances along the closed tour.
+func TourCost(graph *g.Graph, tour []int) float64 {
+	if len(tour) < 2 {
+		return 0
+	}
+	dist := graph.GetCompletedWeightMatrix()
+	cost := 0.0
+	for i := range tour {
+		cost += dist[tour[i]-1][tour[(i+1)%len(tour)]-1]
+	}
+	return cost
+}
+
+// EdgeCost sums the weights of edges, counting 1 per edge in an unweighted
+// graph.
+func EdgeCost(graph *g.Graph, edges [][2]int) float64 {
+	if !graph.Weighted {
+		return float64(len(edges))
+	}
+	cost := 0.0
+	for _, e := range edges {
+		cost += graph.WeightMatrix[e[0]-1][e[1]-1]
+	}
+	return cost
+}
+
+// HasBound reports whether r carries a lower bound.
+func (r Result) HasBound() bool {
+	return !math.IsNaN(r.Bound)
+}
@@ -0,0 +1,127 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+	"github.com/Simikao/graphOptimalisation/internal/validate"
+)
+
+// testGraphs are small metric graphs every solver should handle, including
+// the degenerate ones.
+func testGraphs() map[string]*g.Graph {
+	square := g.NewGraph(4, false, true)
+	square.AddEdge(1, 2, 1).AddEdge(2, 3, 1).AddEdge(3, 4, 1).AddEdge(4, 1, 1).AddEdge(1, 3, 1.5).AddEdge(2, 4, 1.5)
+	triangle := g.NewGraph(3, false, true)
+	triangle.AddEdge(1, 2, 2).AddEdge(2, 3, 3).AddEdge(1, 3, 4)
+	single := g.NewGraph(1, false, true)
+	empty := g.NewGraph(0, false, true)
+	disconnected := g.NewGraph(4, false, true)
+	disconnected.AddEdge(1, 2, 1).AddEdge(3, 4, 1)
+	return map[string]*g.Graph{
+		"square":       &square,
+		"triangle":     &triangle,
+		"single":       &single,
+		"empty":        &empty,
+		"disconnected": &disconnected,
+	}
+}
+
+// TestSolversVerify runs every registered solver with Verify on every test
+// graph. A solver may refuse a graph, but must not panic, and whatever it
+// returns without an error must pass the validators and respect its bound.
+func TestSolversVerify(t *testing.T) {
+	for _, sv := range All() {
+		for name, graph := range testGraphs() {
+			t.Run(sv.Name()+"/"+name, func(t *testing.T) {
+				res, err := Run(context.Background(), sv, graph, Options{Verify: true})
+				if err != nil {
+					var verr *validate.Error
+					if errors.As(err, &verr) {
+						t.Fatalf("invalid solution: %v", err)
+					}
+					t.Skipf("refused: %v", err)
+				}
+				if res.HasBound() && res.Bound > res.Cost+1e-9 {
+					t.Errorf("bound %g exceeds cost %g", res.Bound, res.Cost)
+				}
+			})
+		}
+	}
+}
+
+// TestExactSolversBoundHeuristics checks that the bounds reported by the
+// heuristics are below the optima found by the exact solvers.
+func TestExactSolversBoundHeuristics(t *testing.T) {
+	pairs := []struct{ heuristic, exact string }{
+		{"tsp", "tsp-exact"},
+		{"cover", "cover-exact"},
+	}
+	for _, p := range pairs {
+		for name, graph := range testGraphs() {
+			h, errH := Run(context.Background(), mustLookup(t, p.heuristic), graph, Options{})
+			x, errX := Run(context.Background(), mustLookup(t, p.exact), graph, Options{})
+			if errH != nil || errX != nil {
+				continue
+			}
+			if x.Cost > h.Cost+1e-9 {
+				t.Errorf("%s: %s found %g, worse than %s with %g", name, p.exact, x.Cost, p.heuristic, h.Cost)
+			}
+			if h.HasBound() && h.Bound > x.Cost+1e-9 {
+				t.Errorf("%s: %s reports bound %g above the optimum %g", name, p.heuristic, h.Bound, x.Cost)
+			}
+			if math.IsNaN(x.Bound) || x.Bound != x.Cost {
+				t.Errorf("%s: %s reports bound %g for cost %g", name, p.exact, x.Bound, x.Cost)
+			}
+		}
+	}
+}
+
+func mustLookup(t *testing.T, name string) Solver {
+	t.Helper()
+	s, err := Lookup(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestPostmanBound(t *testing.T) {
+	// Zachłanne skojarzenie nieparzystych wierzchołków daje tu 117, optimum to 115
+	graph := g.NewGraph(4, false, true)
+	graph.AddEdge(1, 2, 1).AddEdge(3, 4, 100).AddEdge(1, 3, 2).AddEdge(2, 4, 2).AddEdge(1, 4, 3).AddEdge(2, 3, 3)
+	res, err := Run(context.Background(), mustLookup(t, "cpp"), &graph, Options{Verify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Bound != 111 {
+		t.Errorf("bound %g, want the total edge weight 111", res.Bound)
+	}
+	if res.Bound > 115 || res.Cost < 115 {
+		t.Errorf("cost %g and bound %g do not enclose the optimum 115", res.Cost, res.Bound)
+	}
+
+	negative := g.NewGraph(2, false, true)
+	negative.AddEdge(1, 2, -1)
+	if res, err := Run(context.Background(), mustLookup(t, "cpp"), &negative, Options{}); err == nil && res.HasBound() {
+		t.Errorf("bound %g reported for a graph with a negative edge", res.Bound)
+	}
+}
+
+func TestRunReportsProgress(t *testing.T) {
+	graph := g.NewGraph(4, false, true)
+	graph.AddEdge(1, 2, 1).AddEdge(2, 3, 2).AddEdge(3, 4, 1).AddEdge(4, 1, 2).AddEdge(1, 3, 2).AddEdge(2, 4, 2)
+	for _, name := range []string{"mst", "mst-prim", "tsp"} {
+		seen := make(map[string]bool)
+		progress := func(p g.Progress) { seen[p.Algorithm] = true }
+		if _, err := Run(context.Background(), mustLookup(t, name), &graph, Options{Progress: progress}); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(seen) == 0 {
+			t.Errorf("%s: no progress reported", name)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+// Package validate checks solutions returned by the solvers against the
+// graph they were computed for. Every Verify function returns nil for a valid
+// solution and an *Error listing all violations otherwise.
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+// Rodzaje naruszeń
+const (
+	VertexOutOfRange = "vertex-out-of-range"
+	DuplicateVertex  = "duplicate-vertex"
+	MissingVertex    = "missing-vertex"
+	UncoveredEdge    = "uncovered-edge"
+	MissingEdge      = "missing-edge"
+	NotClosed        = "not-closed"
+	UnusedEdge       = "unused-edge"
+	OverusedEdge     = "overused-edge"
+	Cycle            = "cycle"
+	Disconnected     = "disconnected"
+	WrongSize        = "wrong-size"
+	SharedVertex     = "shared-vertex"
+	DirectedGraph    = "directed-graph"
+)
+
+type Violation struct {
+	Kind     string `json:"kind"`
+	Vertices []int  `json:"vertices,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (v Violation) String() string {
+	return v.Kind + ": " + v.Message
+}
+
+// Error is returned when a solution is invalid.
+type Error struct {
+	Check      string
+	Violations []Violation
+}
+
+// maxReported bounds the violations spelled out by Error().
+const maxReported = 5
+
+func (e *Error) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %d violation(s)", e.Check, len(e.Violations))
+	for i, v := range e.Violations {
+		if i == maxReported {
+			fmt.Fprintf(&sb, "; ... and %d more", len(e.Violations)-maxReported)
+			break
+		}
+		sb.WriteString("; ")
+		sb.WriteString(v.String())
+	}
+	return sb.String()
+}
+
+// checker collects violations for one Verify call.
+type checker struct {
+	graph *g.Graph
+	err   Error
+}
+
+func newChecker(check string, graph *g.Graph) *checker {
+	return &checker{graph: graph, err: Error{Check: check}}
+}
+
+func (c *checker) add(kind string, vertices []int, format string, args ...any) {
+	c.err.Violations = append(c.err.Violations, Violation{Kind: kind, Vertices: vertices, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) result() error {
+	if len(c.err.Violations) == 0 {
+		return nil
+	}
+	return &c.err
+}
+
+func (c *checker) n() int {
+	return len(c.graph.AdjMatrix)
+}
+
+// inRange reports whether v is a vertex of the graph, recording a violation
+// if it is not.
+func (c *checker) inRange(v int) bool {
+	if v < 1 || v > c.n() {
+		c.add(VertexOutOfRange, []int{v}, "vertex %d is not in 1..%d", v, c.n())
+		return false
+	}
+	return true
+}
+
+func (c *checker) valid(v int) bool {
+	return v >= 1 && v <= c.n()
+}
+
+func (c *checker) adjacent(u, v int) bool {
+	return c.graph.AdjMatrix[u-1][v-1] > 0 || (!c.graph.Directed && c.graph.AdjMatrix[v-1][u-1] > 0)
+}
+
+// VerifyVertexCover checks that every edge has an endpoint in cover.
+func VerifyVertexCover(graph *g.Graph, cover []int) error {
+	c := newChecker("vertex cover", graph)
+	in := make([]bool, c.n()+1)
+	for _, v := range cover {
+		if !c.inRange(v) {
+			continue
+		}
+		if in[v] {
+			c.add(DuplicateVertex, []int{v}, "vertex %d listed more than once", v)
+		}
+		in[v] = true
+	}
+	for _, e := range graph.Edges {
+		if !in[e[0]] && !in[e[1]] {
+			c.add(UncoveredEdge, []int{e[0], e[1]}, "edge (%d, %d) has no endpoint in the cover", e[0], e[1])
+		}
+	}
+	return c.result()
+}
+
+// closedWalk drops the repeated first vertex at the end of walk, if present.
+func closedWalk(walk []int) ([]int, bool) {
+	if len(walk) > 1 && walk[0] == walk[len(walk)-1] {
+		return walk[:len(walk)-1], true
+	}
+	return walk, false
+}
+
+// checkPermutation records vertices missing from or repeated in order.
+func (c *checker) checkPermutation(order []int) {
+	seen := make([]int, c.n()+1)
+	for _, v := range order {
+		if !c.inRange(v) {
+			continue
+		}
+		seen[v]++
+		if seen[v] == 2 {
+			c.add(DuplicateVertex, []int{v}, "vertex %d visited more than once", v)
+		}
+	}
+	for v := 1; v <= c.n(); v++ {
+		if seen[v] == 0 {
+			c.add(MissingVertex, []int{v}, "vertex %d is not visited", v)
+		}
+	}
+}
+
+// VerifyTour checks that tour visits every vertex exactly once. The tour is
+// closed implicitly (the last vertex returns to the first) and may also
+// repeat the first vertex at the end. Consecutive vertices need not be
+// adjacent, as in tours over the metric closure such as Christofides'.
+func VerifyTour(graph *g.Graph, tour []int) error {
+	c := newChecker("tour", graph)
+	order, _ := closedWalk(tour)
+	c.checkPermutation(order)
+	return c.result()
+}
+
+// VerifyHamiltonianCycle checks that cycle visits every vertex exactly once
+// and that every step, including the one closing the cycle, follows an edge
+// of the graph.
+func VerifyHamiltonianCycle(graph *g.Graph, cycle []int) error {
+	c := newChecker("Hamiltonian cycle", graph)
+	order, _ := closedWalk(cycle)
+	c.checkPermutation(order)
+	if len(order) > 1 {
+		for i, u := range order {
+			v := order[(i+1)%len(order)]
+			// Wierzchołki spoza zakresu zgłosiło już checkPermutation
+			if c.valid(u) && c.valid(v) && !c.adjacent(u, v) {
+				c.add(MissingEdge, []int{u, v}, "no edge from %d to %d", u, v)
+			}
+		}
+	}
+	return c.result()
+}
+
+// checkWalk verifies that circuit is closed and follows edges of the graph,
+// and returns how many times each edge (u-1, v-1) was traversed. For
+// undirected graphs both orientations are counted under u <= v.
+func (c *checker) checkWalk(circuit []int) [][]int {
+	n := c.n()
+	used := make([][]int, n)
+	for i := range used {
+		used[i] = make([]int, n)
+	}
+	if len(circuit) == 0 {
+		if len(c.graph.Edges) > 0 {
+			c.add(WrongSize, nil, "empty circuit")
+		}
+		return used
+	}
+	if circuit[0] != circuit[len(circuit)-1] {
+		c.add(NotClosed, []int{circuit[0], circuit[len(circuit)-1]}, "circuit starts at %d but ends at %d", circuit[0], circuit[len(circuit)-1])
+	}
+	for _, v := range circuit {
+		c.inRange(v)
+	}
+	for i := 0; i+1 < len(circuit); i++ {
+		u, v := circuit[i], circuit[i+1]
+		if !c.valid(u) || !c.valid(v) {
+			continue
+		}
+		if !c.adjacent(u, v) {
+			c.add(MissingEdge, []int{u, v}, "step %d: no edge from %d to %d", i+1, u, v)
+			continue
+		}
+		if !c.graph.Directed && u > v {
+			u, v = v, u
+		}
+		used[u-1][v-1]++
+	}
+	return used
+}
+
+// multiplicity is the number of parallel edges from u to v (0-based) as
+// stored in the adjacency matrix.
+func (c *checker) multiplicity(u, v int) int {
+	if !c.graph.Directed && u > v {
+		u, v = v, u
+	}
+	return c.graph.AdjMatrix[u][v]
+}
+
+// VerifyEulerianCircuit checks that circuit is a closed walk traversing every
+// edge exactly once. Parallel edges are counted by their multiplicity in the
+// adjacency matrix.
+func VerifyEulerianCircuit(graph *g.Graph, circuit []int) error {
+	c := newChecker("Eulerian circuit", graph)
+	used := c.checkWalk(circuit)
+	c.checkEdgeUse(used, true)
+	return c.result()
+}
+
+// VerifyPostmanCircuit checks that circuit is a closed walk traversing every
+// edge at least once, as required of a Chinese postman solution.
+func VerifyPostmanCircuit(graph *g.Graph, circuit []int) error {
+	c := newChecker("postman circuit", graph)
+	used := c.checkWalk(circuit)
+	c.checkEdgeUse(used, false)
+	return c.result()
+}
+
+func (c *checker) checkEdgeUse(used [][]int, exact bool) {
+	for u := range used {
+		for v := range used[u] {
+			if !c.graph.Directed && v < u {
+				continue
+			}
+			want, got := c.multiplicity(u, v), used[u][v]
+			switch {
+			case want > 0 && got == 0:
+				c.add(UnusedEdge, []int{u + 1, v + 1}, "edge (%d, %d) is not traversed", u+1, v+1)
+			case exact && got > want:
+				c.add(OverusedEdge, []int{u + 1, v + 1}, "edge (%d, %d) traversed %d times, exists %d times", u+1, v+1, got, want)
+			case exact && got < want:
+				c.add(UnusedEdge, []int{u + 1, v + 1}, "edge (%d, %d) traversed %d times, exists %d times", u+1, v+1, got, want)
+			}
+		}
+	}
+}
+
+// VerifySpanningTree checks that edges are edges of the graph forming a tree
+// over all its vertices. The graph must be undirected.
+func VerifySpanningTree(graph *g.Graph, edges [][2]int) error {
+	c := newChecker("spanning tree", graph)
+	if graph.Directed {
+		c.add(DirectedGraph, nil, "spanning trees are defined for undirected graphs")
+		return c.result()
+	}
+	n := c.n()
+	if n > 0 && len(edges) != n-1 {
+		c.add(WrongSize, nil, "%d edges, a spanning tree of %d vertices has %d", len(edges), n, n-1)
+	}
+	uf := g.NewUnionFind(n)
+	for _, e := range edges {
+		u, v := e[0], e[1]
+		if !c.inRange(u) || !c.inRange(v) {
+			continue
+		}
+		if !c.adjacent(u, v) {
+			c.add(MissingEdge, []int{u, v}, "(%d, %d) is not an edge of the graph", u, v)
+			continue
+		}
+		if uf.Find(u-1) == uf.Find(v-1) {
+			c.add(Cycle, []int{u, v}, "edge (%d, %d) closes a cycle", u, v)
+			continue
+		}
+		uf.Union(u-1, v-1)
+	}
+	for v := 2; v <= n; v++ {
+		if uf.Find(v-1) != uf.Find(0) {
+			c.add(Disconnected, []int{v}, "vertex %d is not connected to vertex 1", v)
+		}
+	}
+	return c.result()
+}
+
+// VerifyMatching checks that matching consists of edges of the graph with
+// no vertex in common.
+func VerifyMatching(graph *g.Graph, matching [][2]int) error {
+	c := newChecker("matching", graph)
+	matched := make(map[int][2]int)
+	for _, e := range matching {
+		u, v := e[0], e[1]
+		if !c.inRange(u) || !c.inRange(v) {
+			continue
+		}
+		if u == v {
+			c.add(MissingEdge, []int{u, v}, "(%d, %d) is a loop", u, v)
+			continue
+		}
+		if !c.adjacent(u, v) {
+			c.add(MissingEdge, []int{u, v}, "(%d, %d) is not an edge of the graph", u, v)
+		}
+		for _, x := range []int{u, v} {
+			if other, ok := matched[x]; ok {
+				c.add(SharedVertex, []int{x}, "vertex %d is matched by (%d, %d) and (%d, %d)", x, other[0], other[1], u, v)
+			}
+			matched[x] = e
+		}
+	}
+	return c.result()
+}
+
+// Solution verifies a solution of one of the problems of the solver package
+// ("vertex-cover", "tsp", "chinese-postman", "spanning-tree"). For
+// "chinese-postman" the graph must be the input before ChinesePostmanProblem
+// added its edges.
+func Solution(problem string, graph *g.Graph, solution any) error {
+	switch problem {
+	case "vertex-cover":
+		if s, ok := solution.([]int); ok {
+			return VerifyVertexCover(graph, s)
+		}
+	case "tsp":
+		if s, ok := solution.([]int); ok {
+			return VerifyTour(graph, s)
+		}
+	case "chinese-postman":
+		if s, ok := solution.([]int); ok {
+			return VerifyPostmanCircuit(graph, s)
+		}
+	case "spanning-tree":
+		if s, ok := solution.([][2]int); ok {
+			return VerifySpanningTree(graph, s)
+		}
+	default:
+		return fmt.Errorf("no validator for %q", problem)
+	}
+	return fmt.Errorf("%s: unexpected solution type %T", problem, solution)
+}
@@ -0,0 +1,128 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+
+	g "github.com/Simikao/graphOptimalisation/internal/graph"
+)
+
+// square is the cycle 1-2-3-4-1 with the chord 1-3.
+func square() *g.Graph {
+	graph := g.NewGraph(4, false, true)
+	graph.AddEdge(1, 2, 1).AddEdge(2, 3, 1).AddEdge(3, 4, 1).AddEdge(4, 1, 1).AddEdge(1, 3, 2)
+	return &graph
+}
+
+// kinds returns the kinds of the violations in err, or nil if err is nil.
+func kinds(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *Error
+	if !errors.As(err, &verr) {
+		t.Fatalf("got %v, want an *Error", err)
+	}
+	var out []string
+	for _, v := range verr.Violations {
+		out = append(out, v.Kind)
+	}
+	return out
+}
+
+func hasKind(got []string, want string) bool {
+	for _, k := range got {
+		if k == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		verify func(*g.Graph) error
+		want   string // pusty: rozwiązanie poprawne
+	}{
+		{"cover", func(gr *g.Graph) error { return VerifyVertexCover(gr, []int{1, 3}) }, ""},
+		{"cover misses an edge", func(gr *g.Graph) error { return VerifyVertexCover(gr, []int{1}) }, UncoveredEdge},
+		{"cover repeats a vertex", func(gr *g.Graph) error { return VerifyVertexCover(gr, []int{1, 3, 3}) }, DuplicateVertex},
+		{"cover out of range", func(gr *g.Graph) error { return VerifyVertexCover(gr, []int{1, 3, 9}) }, VertexOutOfRange},
+
+		{"tour", func(gr *g.Graph) error { return VerifyTour(gr, []int{1, 3, 2, 4}) }, ""},
+		{"tour closed explicitly", func(gr *g.Graph) error { return VerifyTour(gr, []int{1, 2, 3, 4, 1}) }, ""},
+		{"tour misses a vertex", func(gr *g.Graph) error { return VerifyTour(gr, []int{1, 2, 3}) }, MissingVertex},
+		{"tour repeats a vertex", func(gr *g.Graph) error { return VerifyTour(gr, []int{1, 2, 2, 4}) }, DuplicateVertex},
+
+		{"Hamiltonian cycle", func(gr *g.Graph) error { return VerifyHamiltonianCycle(gr, []int{1, 2, 3, 4}) }, ""},
+		{"Hamiltonian cycle without an edge", func(gr *g.Graph) error { return VerifyHamiltonianCycle(gr, []int{1, 2, 4, 3}) }, MissingEdge},
+
+		{"postman circuit", func(gr *g.Graph) error { return VerifyPostmanCircuit(gr, []int{1, 2, 3, 4, 1, 3, 1}) }, ""},
+		{"postman circuit misses an edge", func(gr *g.Graph) error { return VerifyPostmanCircuit(gr, []int{1, 2, 3, 4, 1}) }, UnusedEdge},
+		{"postman circuit not closed", func(gr *g.Graph) error { return VerifyPostmanCircuit(gr, []int{1, 2, 3, 4, 1, 3}) }, NotClosed},
+		{"Eulerian circuit overuses an edge", func(gr *g.Graph) error { return VerifyEulerianCircuit(gr, []int{1, 2, 3, 4, 1, 3, 1}) }, OverusedEdge},
+
+		{"spanning tree", func(gr *g.Graph) error { return VerifySpanningTree(gr, [][2]int{{1, 2}, {2, 3}, {3, 4}}) }, ""},
+		{"spanning tree with a cycle", func(gr *g.Graph) error { return VerifySpanningTree(gr, [][2]int{{1, 2}, {2, 3}, {1, 3}}) }, Cycle},
+		{"spanning tree too small", func(gr *g.Graph) error { return VerifySpanningTree(gr, [][2]int{{1, 2}, {2, 3}}) }, WrongSize},
+		{"spanning tree with a non-edge", func(gr *g.Graph) error { return VerifySpanningTree(gr, [][2]int{{1, 2}, {2, 4}, {3, 4}}) }, MissingEdge},
+
+		{"matching", func(gr *g.Graph) error { return VerifyMatching(gr, [][2]int{{1, 2}, {3, 4}}) }, ""},
+		{"matching shares a vertex", func(gr *g.Graph) error { return VerifyMatching(gr, [][2]int{{1, 2}, {1, 3}}) }, SharedVertex},
+		{"matching with a non-edge", func(gr *g.Graph) error { return VerifyMatching(gr, [][2]int{{2, 4}}) }, MissingEdge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := kinds(t, tt.verify(square()))
+			switch {
+			case tt.want == "" && got != nil:
+				t.Errorf("valid solution rejected: %v", got)
+			case tt.want != "" && !hasKind(got, tt.want):
+				t.Errorf("got violations %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyEmptyAndSingleVertex(t *testing.T) {
+	empty := g.NewGraph(0, false, false)
+	single := g.NewGraph(1, false, false)
+	if err := VerifyVertexCover(&empty, nil); err != nil {
+		t.Errorf("empty cover of the empty graph: %v", err)
+	}
+	if err := VerifySpanningTree(&empty, nil); err != nil {
+		t.Errorf("empty tree of the empty graph: %v", err)
+	}
+	if err := VerifySpanningTree(&single, nil); err != nil {
+		t.Errorf("empty tree of a single vertex: %v", err)
+	}
+	if err := VerifyTour(&single, []int{1}); err != nil {
+		t.Errorf("tour of a single vertex: %v", err)
+	}
+	if err := VerifyPostmanCircuit(&single, nil); err != nil {
+		t.Errorf("empty circuit without edges: %v", err)
+	}
+}
+
+func TestVerifySpanningTreeDirected(t *testing.T) {
+	graph := g.NewGraph(2, true, false)
+	graph.AddEdge(1, 2)
+	if got := kinds(t, VerifySpanningTree(&graph, [][2]int{{1, 2}})); !hasKind(got, DirectedGraph) {
+		t.Errorf("got %v, want %s", got, DirectedGraph)
+	}
+}
+
+func TestSolution(t *testing.T) {
+	graph := square()
+	if err := Solution("vertex-cover", graph, []int{1, 3}); err != nil {
+		t.Error(err)
+	}
+	if err := Solution("spanning-tree", graph, []int{1, 2}); err == nil {
+		t.Error("wrong solution type accepted")
+	}
+	if err := Solution("no-such-problem", graph, nil); err == nil {
+		t.Error("unknown problem accepted")
+	}
+}